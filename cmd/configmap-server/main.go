@@ -3,22 +3,16 @@ package main
 import (
 	"context"
 	"fmt"
-	"net"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/reflection"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
-	"github.com/operator-framework/operator-registry/pkg/api"
-	health "github.com/operator-framework/operator-registry/pkg/api/grpc_health_v1"
-	"github.com/operator-framework/operator-registry/pkg/lib/dns"
-	"github.com/operator-framework/operator-registry/pkg/lib/graceful"
-	"github.com/operator-framework/operator-registry/pkg/lib/log"
+	"github.com/operator-framework/operator-registry/internal/serving"
 	"github.com/operator-framework/operator-registry/pkg/registry"
 	"github.com/operator-framework/operator-registry/pkg/server"
 	"github.com/operator-framework/operator-registry/pkg/sqlite"
@@ -44,8 +38,7 @@ func init() {
 	rootCmd.Flags().StringP("database", "d", "bundles.db", "name of db to output")
 	rootCmd.Flags().StringP("configMapName", "c", "", "name of a configmap")
 	rootCmd.Flags().StringP("configMapNamespace", "n", "", "namespace of a configmap")
-	rootCmd.Flags().StringP("port", "p", "50051", "port number to serve on")
-	rootCmd.Flags().StringP("termination-log", "t", "/dev/termination-log", "path to a container termination log file")
+	serving.AddFlags(rootCmd.Flags())
 	rootCmd.Flags().Bool("permissive", false, "allow registry load errors")
 	if err := rootCmd.Flags().MarkHidden("debug"); err != nil {
 		logrus.Panic(err.Error())
@@ -59,27 +52,17 @@ func main() {
 }
 
 func runCmdFunc(cmd *cobra.Command, args []string) error {
-	// Immediately set up termination log
-	terminationLogPath, err := cmd.Flags().GetString("termination-log")
+	opts, err := serving.OptionsFromFlags(cmd.Flags())
 	if err != nil {
 		return err
 	}
-	err = log.AddDefaultWriterHooks(terminationLogPath)
-	if err != nil {
-		logrus.WithError(err).Warn("unable to set termination log path")
-	}
-	// Ensure there is a default nsswitch config
-	if err := dns.EnsureNsswitch(); err != nil {
-		logrus.WithError(err).Warn("unable to write default nsswitch config")
-	}
+	// Immediately set up termination log
+	serving.Prepare(logrus.StandardLogger(), opts)
+
 	kubeconfig, err := cmd.Flags().GetString("kubeconfig")
 	if err != nil {
 		return err
 	}
-	port, err := cmd.Flags().GetString("port")
-	if err != nil {
-		return err
-	}
 	configMapName, err := cmd.Flags().GetString("configMapName")
 	if err != nil {
 		return err
@@ -96,7 +79,7 @@ func runCmdFunc(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	logger := logrus.WithFields(logrus.Fields{"configMapName": configMapName, "configMapNamespace": configMapNamespace, "port": port})
+	logger := logrus.WithFields(logrus.Fields{"configMapName": configMapName, "configMapNamespace": configMapNamespace, "port": opts.Port})
 
 	client := NewClientFromConfig(kubeconfig, logger.Logger)
 	configMap, err := client.CoreV1().ConfigMaps(configMapNamespace).Get(context.TODO(), configMapName, metav1.GetOptions{})
@@ -104,6 +87,7 @@ func runCmdFunc(cmd *cobra.Command, args []string) error {
 		logger.Fatalf("error getting configmap: %s", err)
 	}
 
+	loadStart := time.Now()
 	db, err := sqlite.Open(dbName)
 	if err != nil {
 		return err
@@ -144,22 +128,15 @@ func runCmdFunc(cmd *cobra.Command, args []string) error {
 		logger.Warn("no tables found in db")
 	}
 
-	lis, err := net.Listen("tcp", ":"+port)
-	if err != nil {
-		logger.Fatalf("failed to listen: %s", err)
+	if sqlStore, ok := store.(*sqlite.SQLQuerier); ok && sqlStore != nil {
+		opts.CatalogInfo, err = server.NewSQLCatalogInfo(context.TODO(), server.SourceTypeConfigMap, server.DigestData(configMap.Data), time.Now(), sqlStore)
+		if err != nil {
+			logger.WithError(err).Warn("couldn't determine catalog info")
+		}
 	}
-	s := grpc.NewServer()
-
-	api.RegisterRegistryServer(s, server.NewRegistryServer(store))
-	health.RegisterHealthServer(s, server.NewHealthServer())
-	reflection.Register(s)
+	opts.LoadDuration = time.Since(loadStart)
 
-	logger.Info("serving registry")
-	return graceful.Shutdown(logger, func() error {
-		return s.Serve(lis)
-	}, func() {
-		s.GracefulStop()
-	})
+	return serving.Serve(cmd.Context(), logger, store, opts)
 }
 
 // NewClient creates a kubernetes client or bails out on on failures.
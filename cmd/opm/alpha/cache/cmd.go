@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+)
+
+func NewCmd() *cobra.Command {
+	var dir string
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the image cache",
+		Long: `Manage the cache of image content shared by opm invocations.
+
+Content is evicted from the cache least recently used first.`,
+	}
+	defaultDir, _ := containerdregistry.DefaultCacheDir()
+	cmd.PersistentFlags().StringVar(&dir, "cache-dir", defaultDir, "directory of the image cache")
+
+	cmd.AddCommand(listCmd(&dir), pruneCmd(&dir))
+	return cmd
+}
+
+func listCmd(dir *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the content of the image cache",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := containerdregistry.NewCache(*dir).List()
+			if err != nil {
+				return err
+			}
+
+			var total int64
+			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "DIGEST\tSIZE\tLAST USED")
+			for _, entry := range entries {
+				total += entry.Size
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", entry.Digest, formatSize(entry.Size), entry.LastUsed.Format(time.RFC3339))
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "%d blobs, %s\n", len(entries), formatSize(total))
+			return nil
+		},
+	}
+}
+
+func pruneCmd(dir *string) *cobra.Command {
+	var (
+		maxSize   string
+		unusedFor time.Duration
+		timeout   time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Evict content from the image cache",
+		Long: `Evict content from the image cache.
+
+Without flags, the cache is emptied. With --max-size, the least recently used content is evicted
+until the cache fits, and with --unused-for, content that hasn't been used for that long is evicted.
+
+Pruning waits for other opm invocations using the cache to finish.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var opts []containerdregistry.PruneOption
+			if maxSize != "" {
+				size, err := resource.ParseQuantity(maxSize)
+				if err != nil || size.Sign() < 0 {
+					return fmt.Errorf("invalid --max-size value %q, expected a size such as 10Gi", maxSize)
+				}
+				opts = append(opts, containerdregistry.PruneToSize(size.Value()))
+			}
+			if unusedFor > 0 {
+				opts = append(opts, containerdregistry.PruneUnusedFor(unusedFor))
+			}
+
+			ctx := cmd.Context()
+			if timeout > 0 {
+				var cancel func()
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			pruned, err := containerdregistry.NewCache(*dir).Prune(ctx, opts...)
+			var total int64
+			for _, entry := range pruned {
+				total += entry.Size
+			}
+			fmt.Fprintf(os.Stderr, "evicted %d blobs, %s\n", len(pruned), formatSize(total))
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&maxSize, "max-size", "", "evict the least recently used content until the cache is no larger than this size, such as 10Gi")
+	cmd.Flags().DurationVar(&unusedFor, "unused-for", 0, "evict content that hasn't been used for at least this long, such as 168h")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "give up waiting for other invocations using the cache after this long")
+	return cmd
+}
+
+// formatSize formats a number of bytes with a binary unit, such as 1.5MiB.
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -4,7 +4,11 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/operator-framework/operator-registry/cmd/opm/alpha/bundle"
+	"github.com/operator-framework/operator-registry/cmd/opm/alpha/cache"
 	initcmd "github.com/operator-framework/operator-registry/cmd/opm/alpha/init"
+	"github.com/operator-framework/operator-registry/cmd/opm/alpha/pin"
+	"github.com/operator-framework/operator-registry/cmd/opm/alpha/proxy"
+	"github.com/operator-framework/operator-registry/cmd/opm/alpha/query"
 	"github.com/operator-framework/operator-registry/cmd/opm/alpha/render"
 	"github.com/operator-framework/operator-registry/cmd/opm/alpha/serve"
 	"github.com/operator-framework/operator-registry/cmd/opm/alpha/validate"
@@ -17,6 +21,6 @@ func NewCmd() *cobra.Command {
 		Short:  "Run an alpha subcommand",
 	}
 
-	runCmd.AddCommand(bundle.NewCmd(), initcmd.NewCmd(), serve.NewCmd(), render.NewCmd(), validate.NewCmd())
+	runCmd.AddCommand(bundle.NewCmd(), initcmd.NewCmd(), serve.NewCmd(), render.NewCmd(), validate.NewCmd(), query.NewCmd(), proxy.NewCmd(), cache.NewCmd(), pin.NewCmd())
 	return runCmd
 }
@@ -0,0 +1,61 @@
+package pin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/operator-framework/operator-registry/internal/action"
+)
+
+func NewCmd() *cobra.Command {
+	var pin action.Pin
+	cmd := &cobra.Command{
+		Use:   "pin [declcfg-dir | sqlite-file]",
+		Short: "Pin the images of a catalog to digests",
+		Long: `Pin the images of a catalog to digests.
+
+Every bundle image and related image a declarative config directory or an sqlite database file
+references by tag is resolved in its registry, and the catalog is rewritten in place to reference
+it by digest instead. The tag each image was pinned from is kept in an olm.pinnedImage property
+of the bundles referencing it. Images already referenced by digest are left unchanged.
+
+Declarative config files with pinned images are rewritten in the format they were read in.
+If any image can't be resolved, the catalog is left unchanged.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			pin.Ref = args[0]
+
+			// The bundle loading impl is somewhat verbose, even on the happy path,
+			// so discard all logrus default logger logs. Any important failures will be
+			// returned from pin.Run and logged as fatal errors.
+			logrus.SetOutput(ioutil.Discard)
+
+			pinned, err := pin.Run(cmd.Context())
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "BUNDLE\tTAG\tDIGEST")
+			for _, p := range pinned {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", p.Bundle, p.Tag, p.Image)
+			}
+			if err := tw.Flush(); err != nil {
+				log.Fatal(err)
+			}
+			if pin.DryRun {
+				fmt.Fprintf(os.Stderr, "%d images would be pinned\n", len(pinned))
+			} else {
+				fmt.Fprintf(os.Stderr, "pinned %d images\n", len(pinned))
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&pin.DryRun, "dry-run", false, "report the images that would be pinned without rewriting the catalog")
+	return cmd
+}
@@ -0,0 +1,203 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	health "github.com/operator-framework/operator-registry/pkg/api/grpc_health_v1"
+	"github.com/operator-framework/operator-registry/pkg/client"
+	"github.com/operator-framework/operator-registry/pkg/lib/certs"
+	"github.com/operator-framework/operator-registry/pkg/lib/graceful"
+	"github.com/operator-framework/operator-registry/pkg/proxy"
+	"github.com/operator-framework/operator-registry/pkg/server"
+)
+
+// config lists the upstream registries to proxy.
+type config struct {
+	Upstreams []upstreamConfig `json:"upstreams"`
+}
+
+type upstreamConfig struct {
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Priority int    `json:"priority"`
+
+	CAFile                string `json:"caFile,omitempty"`
+	CertFile              string `json:"certFile,omitempty"`
+	KeyFile               string `json:"keyFile,omitempty"`
+	InsecureSkipTLSVerify bool   `json:"insecureSkipTLSVerify,omitempty"`
+}
+
+type proxyCmd struct {
+	configFile          string
+	port                string
+	tlsCert             string
+	tlsKey              string
+	clientCA            string
+	cacheTTL            time.Duration
+	healthCheckInterval time.Duration
+	upstreamTimeout     time.Duration
+	accessLog           bool
+	limits              server.Limits
+	debug               bool
+
+	logger *logrus.Entry
+}
+
+func NewCmd() *cobra.Command {
+	logger := logrus.New()
+	p := &proxyCmd{logger: logrus.NewEntry(logger)}
+	cmd := &cobra.Command{
+		Use:   "proxy",
+		Short: "Serve the merged contents of several registries",
+		Long: `Serve the registry API by fanning requests out to several upstream registries and merging their responses.
+
+Upstreams are listed in a config file:
+
+  upstreams:
+  - name: internal
+    address: internal-catalog:50051
+    priority: 20
+  - name: community
+    address: community-catalog:50051
+    priority: 10
+    caFile: ca.crt
+
+When a package is in more than one upstream, it is served entirely by the upstream with the highest priority.
+Upstreams that fail are left out of responses until they recover. The health of each upstream is reported by the
+health service, under the upstream's name.
+`,
+		Args: cobra.NoArgs,
+		PreRun: func(_ *cobra.Command, _ []string) {
+			if p.debug {
+				logger.SetLevel(logrus.DebugLevel)
+			}
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			limits, err := server.LimitsFromFlags(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			p.limits = limits
+			return p.run(cmd.Context())
+		},
+	}
+	cmd.Flags().StringVarP(&p.configFile, "config", "c", "", "path to the config file listing the upstream registries")
+	cmd.Flags().StringVarP(&p.port, "port", "p", "50051", "port number to serve on")
+	cmd.Flags().StringVar(&p.tlsCert, "tls-cert", "", "path to a PEM encoded certificate to serve TLS with, reloaded when it changes")
+	cmd.Flags().StringVar(&p.tlsKey, "tls-key", "", "path to the PEM encoded private key of the TLS certificate, reloaded when it changes")
+	cmd.Flags().StringVar(&p.clientCA, "client-ca", "", "path to a PEM encoded CA bundle used to verify client certificates, which are required if set")
+	cmd.Flags().DurationVar(&p.cacheTTL, "cache-ttl", proxy.DefaultCacheTTL, "how long to cache responses for, disabled if 0")
+	cmd.Flags().DurationVar(&p.healthCheckInterval, "health-check-interval", 10*time.Second, "interval at which to check the health of upstreams")
+	cmd.Flags().DurationVar(&p.upstreamTimeout, "upstream-timeout", 30*time.Second, "timeout for calls to upstreams")
+	cmd.Flags().BoolVar(&p.accessLog, "access-log", true, "log every request handled")
+	server.AddLimitFlags(cmd.Flags())
+	cmd.Flags().BoolVar(&p.debug, "debug", false, "enable debug logging")
+	if err := cmd.MarkFlagRequired("config"); err != nil {
+		logrus.Panic(err.Error())
+	}
+	return cmd
+}
+
+func (p *proxyCmd) run(ctx context.Context) error {
+	upstreams, err := p.upstreams()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, u := range upstreams {
+			u.Client.Close()
+		}
+	}()
+
+	proxyServer, err := proxy.NewServer(upstreams, proxy.WithCacheTTL(p.cacheTTL), proxy.WithLogger(p.logger))
+	if err != nil {
+		return err
+	}
+	monitorCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go proxyServer.MonitorHealth(monitorCtx, p.healthCheckInterval)
+
+	tlsConfig, err := certs.ServerTLSConfig(p.tlsCert, p.tlsKey, p.clientCA)
+	if err != nil {
+		return err
+	}
+	var grpcOpts []grpc.ServerOption
+	if tlsConfig != nil {
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	var accessLogger logrus.FieldLogger
+	if p.accessLog {
+		accessLogger = p.logger
+	}
+	limiter, err := server.NewLimiter(p.limits)
+	if err != nil {
+		return err
+	}
+	grpcOpts = append(grpcOpts, server.InterceptorOptions(accessLogger, nil, limiter)...)
+
+	lis, err := net.Listen("tcp", ":"+p.port)
+	if err != nil {
+		return fmt.Errorf("failed to listen on port %s: %v", p.port, err)
+	}
+	grpcServer := grpc.NewServer(grpcOpts...)
+	api.RegisterRegistryServer(grpcServer, proxyServer)
+	health.RegisterHealthServer(grpcServer, server.NewHealthServer(server.WithHealthChecker(proxyServer.HealthStatus)))
+	reflection.Register(grpcServer)
+
+	p.logger.WithFields(logrus.Fields{"port": p.port, "upstreams": len(upstreams)}).Info("serving registry proxy")
+	return graceful.Shutdown(p.logger, func() error {
+		return grpcServer.Serve(lis)
+	}, func() {
+		grpcServer.GracefulStop()
+	})
+}
+
+// upstreams connects to the upstreams listed in the config file.
+func (p *proxyCmd) upstreams() ([]proxy.Upstream, error) {
+	data, err := ioutil.ReadFile(p.configFile)
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %v", err)
+	}
+	var cfg config
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse config file %s: %v", p.configFile, err)
+	}
+
+	var upstreams []proxy.Upstream
+	for i, u := range cfg.Upstreams {
+		if u.Address == "" {
+			return nil, fmt.Errorf("upstream %d (%q) has no address", i, u.Name)
+		}
+		opts := []client.ClientOption{client.WithCallTimeout(p.upstreamTimeout)}
+		if u.CAFile != "" {
+			opts = append(opts, client.WithTLS(u.CAFile))
+		}
+		if u.CertFile != "" || u.KeyFile != "" {
+			opts = append(opts, client.WithClientCertificate(u.CertFile, u.KeyFile))
+		}
+		if u.InsecureSkipTLSVerify {
+			opts = append(opts, client.WithInsecureSkipVerify())
+		}
+		c, err := client.NewClient(u.Address, opts...)
+		if err != nil {
+			for _, u := range upstreams {
+				u.Client.Close()
+			}
+			return nil, fmt.Errorf("connect to upstream %q at %s: %v", u.Name, u.Address, err)
+		}
+		upstreams = append(upstreams, proxy.Upstream{Name: u.Name, Priority: u.Priority, Client: c})
+	}
+	return upstreams, nil
+}
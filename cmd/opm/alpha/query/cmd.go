@@ -0,0 +1,206 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/operator-framework/operator-registry/internal/action"
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/client"
+)
+
+type query struct {
+	address       string
+	output        string
+	timeout       time.Duration
+	caFile        string
+	certFile      string
+	keyFile       string
+	skipTLSVerify bool
+}
+
+func NewCmd() *cobra.Command {
+	q := &query{}
+	cmd := &cobra.Command{
+		Use:   "query",
+		Short: "Query a running registry",
+		Long: `Query a running registry over its gRPC API.
+
+Results are written as a table by default, or as the JSON or YAML encoding of the registry API messages.
+`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			switch q.output {
+			case "table", "json", "yaml":
+				return nil
+			}
+			return fmt.Errorf("invalid --output value %q, expected (table|json|yaml)", q.output)
+		},
+	}
+	cmd.PersistentFlags().StringVar(&q.address, "address", "localhost:50051", "address of the registry to query")
+	cmd.PersistentFlags().StringVarP(&q.output, "output", "o", "table", "Output format (table|json|yaml)")
+	cmd.PersistentFlags().DurationVar(&q.timeout, "timeout", 30*time.Second, "timeout for the query")
+	cmd.PersistentFlags().StringVar(&q.caFile, "ca-file", "", "connect over TLS, trusting the CA certificates in this file in addition to the system roots")
+	cmd.PersistentFlags().StringVar(&q.certFile, "tls-cert", "", "connect over TLS, presenting this client certificate")
+	cmd.PersistentFlags().StringVar(&q.keyFile, "tls-key", "", "key of the client certificate")
+	cmd.PersistentFlags().BoolVar(&q.skipTLSVerify, "insecure-skip-tls-verify", false, "connect over TLS without verifying the registry's certificate")
+
+	cmd.AddCommand(
+		q.packagesCmd(),
+		q.packageCmd(),
+		q.bundleCmd(),
+		q.replacementsCmd(),
+		q.providersCmd(),
+	)
+	return cmd
+}
+
+func (q *query) packagesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "packages",
+		Short: "List packages with their default channel and channels",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return q.run(cmd, func(ctx context.Context, query action.Query) (*result, error) {
+				pkgs, err := query.ListPackages(ctx)
+				if err != nil {
+					return nil, err
+				}
+				res := &result{list: true, header: []string{"NAME", "DEFAULT CHANNEL", "CHANNELS"}}
+				for _, pkg := range pkgs {
+					var channels []string
+					for _, ch := range pkg.GetChannels() {
+						channels = append(channels, ch.GetName())
+					}
+					res.add(pkg, pkg.GetName(), pkg.GetDefaultChannelName(), strings.Join(channels, ","))
+				}
+				return res, nil
+			})
+		},
+	}
+}
+
+func (q *query) packageCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "package <packageName>",
+		Short: "Show the channels of a package and their heads",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return q.run(cmd, func(ctx context.Context, query action.Query) (*result, error) {
+				pkg, err := query.GetPackage(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+				res := &result{header: []string{"CHANNEL", "HEAD", "DEFAULT"}}
+				res.messages = []proto.Message{pkg}
+				for _, ch := range pkg.GetChannels() {
+					res.rows = append(res.rows, []string{ch.GetName(), ch.GetCsvName(), fmt.Sprint(ch.GetName() == pkg.GetDefaultChannelName())})
+				}
+				return res, nil
+			})
+		},
+	}
+}
+
+func (q *query) bundleCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "bundle <packageName> <channelName> [csvName]",
+		Short: "Get a bundle from a package channel, or the channel head if no CSV name is given",
+		Args:  cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var csvName string
+			if len(args) == 3 {
+				csvName = args[2]
+			}
+			return q.run(cmd, func(ctx context.Context, query action.Query) (*result, error) {
+				b, err := query.GetBundle(ctx, args[0], args[1], csvName)
+				if err != nil {
+					return nil, err
+				}
+				res := &result{header: []string{"NAME", "PACKAGE", "CHANNEL", "VERSION", "REPLACES", "SKIP RANGE", "IMAGE"}}
+				res.add(b, b.GetCsvName(), b.GetPackageName(), b.GetChannelName(), b.GetVersion(), b.GetReplaces(), b.GetSkipRange(), b.GetBundlePath())
+				return res, nil
+			})
+		},
+	}
+}
+
+func (q *query) replacementsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "replacements <csvName>",
+		Short: "Walk the channel entries that replace a bundle, up to the heads of their channels",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return q.run(cmd, func(ctx context.Context, query action.Query) (*result, error) {
+				entries, err := query.WalkReplacements(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+				return channelEntriesResult(entries), nil
+			})
+		},
+	}
+}
+
+func (q *query) providersCmd() *cobra.Command {
+	var latest bool
+	cmd := &cobra.Command{
+		Use:   "providers <group> <version> <kind>",
+		Short: "Find the channel entries of bundles that provide an API",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return q.run(cmd, func(ctx context.Context, query action.Query) (*result, error) {
+				entries, err := query.Providers(ctx, args[0], args[1], args[2], latest)
+				if err != nil {
+					return nil, err
+				}
+				return channelEntriesResult(entries), nil
+			})
+		},
+	}
+	cmd.Flags().BoolVar(&latest, "latest", false, "only show the latest provider in each channel")
+	return cmd
+}
+
+func channelEntriesResult(entries []*api.ChannelEntry) *result {
+	res := &result{list: true, header: []string{"PACKAGE", "CHANNEL", "BUNDLE", "REPLACES"}}
+	for _, e := range entries {
+		res.add(e, e.GetPackageName(), e.GetChannelName(), e.GetBundleName(), e.GetReplaces())
+	}
+	return res
+}
+
+// run connects to the registry and writes the result of fn to stdout.
+func (q *query) run(cmd *cobra.Command, fn func(context.Context, action.Query) (*result, error)) error {
+	// Arguments have been validated by now, so usage won't help with any errors.
+	cmd.SilenceUsage = true
+
+	var opts []client.ClientOption
+	if q.caFile != "" {
+		opts = append(opts, client.WithTLS(q.caFile))
+	}
+	if q.certFile != "" || q.keyFile != "" {
+		opts = append(opts, client.WithClientCertificate(q.certFile, q.keyFile))
+	}
+	if q.skipTLSVerify {
+		opts = append(opts, client.WithInsecureSkipVerify())
+	}
+	c, err := client.NewClient(q.address, opts...)
+	if err != nil {
+		return fmt.Errorf("connect to %s: %v", q.address, err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), q.timeout)
+	defer cancel()
+	res, err := fn(ctx, action.Query{Client: c})
+	if err != nil {
+		return err
+	}
+	return res.write(os.Stdout, q.output)
+}
@@ -0,0 +1,72 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"sigs.k8s.io/yaml"
+)
+
+// result is the output of a query, as registry API messages and as a table.
+type result struct {
+	// list is set if messages are written as an array, even if there's only one.
+	list     bool
+	messages []proto.Message
+	header   []string
+	rows     [][]string
+}
+
+// add adds a message and its table row.
+func (r *result) add(m proto.Message, row ...string) {
+	r.messages = append(r.messages, m)
+	r.rows = append(r.rows, row)
+}
+
+func (r *result) write(w io.Writer, output string) error {
+	switch output {
+	case "json":
+		data, err := r.marshalJSON()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case "yaml":
+		data, err := r.marshalJSON()
+		if err != nil {
+			return err
+		}
+		if data, err = yaml.JSONToYAML(data); err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(r.header, "\t"))
+	for _, row := range r.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func (r *result) marshalJSON() ([]byte, error) {
+	messages := make([]json.RawMessage, 0, len(r.messages))
+	for _, m := range r.messages {
+		data, err := protojson.Marshal(m)
+		if err != nil {
+			return nil, fmt.Errorf("marshal %s: %v", m.ProtoReflect().Descriptor().FullName(), err)
+		}
+		messages = append(messages, data)
+	}
+	if !r.list && len(messages) == 1 {
+		return json.MarshalIndent(messages[0], "", "  ")
+	}
+	return json.MarshalIndent(messages, "", "  ")
+}
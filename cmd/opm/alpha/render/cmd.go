@@ -8,20 +8,34 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/operator-framework/operator-registry/internal/action"
 	"github.com/operator-framework/operator-registry/internal/declcfg"
+	"github.com/operator-framework/operator-registry/pkg/image/signature"
 )
 
 func NewCmd() *cobra.Command {
 	var (
-		render action.Render
-		output string
+		render       action.Render
+		output       string
+		maxCacheSize string
+		keyFiles     []string
+		policyName   string
 	)
 	cmd := &cobra.Command{
 		Use:   "render [index-image | bundle-image | sqlite-file]...",
 		Short: "Generate a declarative config blob from the provided index images, bundle images, and sqlite database files",
-		Args:  cobra.MinimumNArgs(1),
+		Long: `Generate a declarative config blob from the provided index images, bundle images, and sqlite database files.
+
+Images can be referenced in a remote registry, in an OCI image layout directory (oci:<dir>[:tag]),
+or in a tarball written by docker save (docker-archive:<file>).
+
+Images are pulled into a temporary directory that is removed once rendered, unless
+--cache-dir is set. Pulled image content is then kept in a cache shared by the
+invocations using that directory, so that unchanged images and layers are not
+downloaded again. See 'opm alpha cache' to manage it.`,
+		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			render.Refs = args
 
@@ -35,6 +49,25 @@ func NewCmd() *cobra.Command {
 				log.Fatalf("invalid --output value %q, expected (json|yaml)", output)
 			}
 
+			size, err := resource.ParseQuantity(maxCacheSize)
+			if err != nil || size.Sign() < 0 {
+				log.Fatalf("invalid --cache-max-size value %q, expected a size such as 10Gi", maxCacheSize)
+			}
+			render.MaxCacheSize = size.Value()
+
+			if len(keyFiles) > 0 {
+				policy, err := signature.ParsePolicy(policyName)
+				if err != nil {
+					log.Fatal(err)
+				}
+				verifier, err := signature.LoadCosignVerifier(keyFiles)
+				if err != nil {
+					log.Fatal(err)
+				}
+				// Warnings go to a logger of their own, since the default logger's logs are discarded.
+				render.Verifier = signature.WithPolicy(verifier, policy, logrus.NewEntry(logrus.New()))
+			}
+
 			// The bundle loading impl is somewhat verbose, even on the happy path,
 			// so discard all logrus default logger logs. Any important failures will be
 			// returned from render.Run and logged as fatal errors.
@@ -51,5 +84,11 @@ func NewCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&output, "output", "o", "json", "Output format (json|yaml)")
+
+	cmd.Flags().StringVar(&render.CacheDir, "cache-dir", "", "directory of an image cache shared by invocations, such as the default directory of 'opm alpha cache'; images are pulled into a temporary directory if unset")
+	cmd.Flags().StringVar(&maxCacheSize, "cache-max-size", "10Gi", "size the image cache set by --cache-dir is pruned to after rendering, evicting the least recently used content first, or 0 for no limit")
+	cmd.Flags().StringVar(&render.Platform, "platform", "", "platform of the images to render from multi-platform images, such as linux/arm64; defaults to the host platform")
+	cmd.Flags().StringSliceVar(&keyFiles, "signature-key", nil, "public key files to verify the cosign signatures of images with before they're pulled; images are not verified if unset")
+	cmd.Flags().StringVar(&policyName, "signature-policy", string(signature.PolicyEnforce), "what happens to images whose signatures can't be verified (enforce|warn)")
 	return cmd
 }
@@ -3,20 +3,15 @@ package serve
 import (
 	"context"
 	"fmt"
-	"net"
 	"os"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/reflection"
 
 	"github.com/operator-framework/operator-registry/internal/declcfg"
-	"github.com/operator-framework/operator-registry/pkg/api"
-	health "github.com/operator-framework/operator-registry/pkg/api/grpc_health_v1"
-	"github.com/operator-framework/operator-registry/pkg/lib/dns"
-	"github.com/operator-framework/operator-registry/pkg/lib/graceful"
-	"github.com/operator-framework/operator-registry/pkg/lib/log"
+	"github.com/operator-framework/operator-registry/internal/model"
+	"github.com/operator-framework/operator-registry/internal/serving"
 	"github.com/operator-framework/operator-registry/pkg/registry"
 	"github.com/operator-framework/operator-registry/pkg/server"
 )
@@ -24,9 +19,9 @@ import (
 type serve struct {
 	configDir string
 
-	port           string
-	terminationLog string
+	opts           serving.Options
 	debug          bool
+	reloadInterval time.Duration
 
 	logger *logrus.Entry
 }
@@ -49,54 +44,114 @@ func NewCmd() *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			opts, err := serving.OptionsFromFlags(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			s.opts = opts
 			return s.run(cmd.Context())
 		},
 	}
 
 	cmd.Flags().BoolVar(&s.debug, "debug", false, "enable debug logging")
-	cmd.Flags().StringVarP(&s.port, "port", "p", "50051", "port number to serve on")
-	cmd.Flags().StringVarP(&s.terminationLog, "termination-log", "t", "/dev/termination-log", "path to a container termination log file")
+	serving.AddFlags(cmd.Flags())
+	cmd.Flags().DurationVar(&s.reloadInterval, "reload-interval", 0, "interval at which to check the config directory for changes and reload it, reporting them to Watch callers; reloads and Watch are disabled if 0")
 	return cmd
 }
 
 func (s *serve) run(ctx context.Context) error {
 	// Immediately set up termination log
-	err := log.AddDefaultWriterHooks(s.terminationLog)
+	serving.Prepare(s.logger, s.opts)
+
+	s.logger = s.logger.WithFields(logrus.Fields{"configs": s.configDir, "port": s.opts.Port})
+
+	loadStart := time.Now()
+	m, digest, err := s.load()
 	if err != nil {
-		s.logger.WithError(err).Warn("unable to set termination log path")
+		return err
 	}
+	s.opts.CatalogInfo = server.NewModelCatalogInfo(server.SourceTypeDeclcfg, digest, time.Now(), m)
+	s.opts.LoadDuration = time.Since(loadStart)
 
-	// Ensure there is a default nsswitch config
-	if err := dns.EnsureNsswitch(); err != nil {
-		s.logger.WithError(err).Warn("unable to write default nsswitch config")
+	// Configs only change when they're reloaded, so Watch is only served if they are
+	if s.reloadInterval > 0 {
+		watcher, err := server.NewModelWatcher(m)
+		if err != nil {
+			return fmt.Errorf("could not watch index model: %v", err)
+		}
+		s.opts.Watcher = watcher
+		s.opts.Reload = func(ctx context.Context, registryServer *server.RegistryServer, metrics *server.Metrics) {
+			s.reload(ctx, digest, registryServer, watcher, metrics)
+		}
 	}
 
-	s.logger = s.logger.WithFields(logrus.Fields{"configs": s.configDir, "port": s.port})
+	return serving.Serve(ctx, s.logger, registry.NewQuerier(m), s.opts)
+}
 
-	cfg, err := declcfg.LoadFS(os.DirFS(s.configDir))
+// load builds the index model from the config directory and returns it along with
+// the directory's digest.
+func (s *serve) load() (model.Model, string, error) {
+	configFS := os.DirFS(s.configDir)
+	digest, err := server.DigestFS(configFS)
 	if err != nil {
-		return fmt.Errorf("load declarative config directory: %v", err)
+		return nil, "", fmt.Errorf("digest declarative config directory: %v", err)
 	}
 
-	m, err := declcfg.ConvertToModel(*cfg)
+	cfg, err := declcfg.LoadFS(configFS)
 	if err != nil {
-		return fmt.Errorf("could not build index model from declarative config: %v", err)
+		return nil, "", fmt.Errorf("load declarative config directory: %v", err)
 	}
-	store := registry.NewQuerier(m)
 
-	lis, err := net.Listen("tcp", ":"+s.port)
+	m, err := declcfg.ConvertToModel(*cfg)
 	if err != nil {
-		s.logger.Fatalf("failed to listen: %s", err)
+		return nil, "", fmt.Errorf("could not build index model from declarative config: %v", err)
 	}
+	return m, digest, nil
+}
+
+// reload polls the config directory for changes every reload interval. Changed
+// configs replace the served model and are reported to watchers; configs that fail
+// to load are logged and the previous model is kept. Reload attempts are recorded
+// in metrics, if set.
+func (s *serve) reload(ctx context.Context, digest string, registryServer *server.RegistryServer, watcher *server.ModelWatcher, metrics *server.Metrics) {
+	ticker := time.NewTicker(s.reloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current, err := server.DigestFS(os.DirFS(s.configDir))
+		if err != nil {
+			s.logger.WithError(err).Warn("unable to check declarative config directory for changes")
+			continue
+		}
+		if current == digest {
+			continue
+		}
 
-	grpcServer := grpc.NewServer()
-	api.RegisterRegistryServer(grpcServer, server.NewRegistryServer(store))
-	health.RegisterHealthServer(grpcServer, server.NewHealthServer())
-	reflection.Register(grpcServer)
-	s.logger.Info("serving registry")
-	return graceful.Shutdown(s.logger, func() error {
-		return grpcServer.Serve(lis)
-	}, func() {
-		grpcServer.GracefulStop()
-	})
+		loadStart := time.Now()
+		m, loadedDigest, err := s.load()
+		if err != nil {
+			if metrics != nil {
+				metrics.ObserveLoad(nil, time.Since(loadStart), err)
+			}
+			s.logger.WithError(err).Warn("unable to reload declarative config directory, continuing to serve previous configs")
+			continue
+		}
+		info := server.NewModelCatalogInfo(server.SourceTypeDeclcfg, loadedDigest, time.Now(), m)
+		registryServer.Reload(registry.NewQuerier(m), info)
+		if metrics != nil {
+			metrics.ObserveLoad(info, time.Since(loadStart), nil)
+		}
+		digest = loadedDigest
+		s.logger.WithField("digest", digest).Info("reloaded declarative configs")
+
+		// Notify watchers only once the new model is being served.
+		if err := watcher.Update(m); err != nil {
+			s.logger.WithError(err).Warn("unable to compute changes to declarative configs for watchers")
+		}
+	}
 }
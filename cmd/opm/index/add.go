@@ -8,7 +8,10 @@ import (
 	"k8s.io/kubectl/pkg/util/templates"
 
 	"github.com/operator-framework/operator-registry/pkg/containertools"
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+	"github.com/operator-framework/operator-registry/pkg/image/signature"
 	"github.com/operator-framework/operator-registry/pkg/lib/indexer"
+	lregistry "github.com/operator-framework/operator-registry/pkg/lib/registry"
 	"github.com/operator-framework/operator-registry/pkg/registry"
 )
 
@@ -50,18 +53,23 @@ func addIndexAddCmd(parent *cobra.Command) {
 	indexCmd.Flags().Bool("debug", false, "enable debug logging")
 	indexCmd.Flags().Bool("generate", false, "if enabled, just creates the dockerfile and saves it to local disk")
 	indexCmd.Flags().StringP("out-dockerfile", "d", "", "if generating the dockerfile, this flag is used to (optionally) specify a dockerfile name")
-	indexCmd.Flags().StringP("from-index", "f", "", "previous index to add to")
+	indexCmd.Flags().StringP("from-index", "f", "", "previous index to add to, which may be local: oci:<dir>[:tag] or docker-archive:<file>")
 	// adding empty list of strings is a valid value.
 	indexCmd.Flags().StringSliceP("bundles", "b", nil, "comma separated list of bundles to add")
 	if err := indexCmd.MarkFlagRequired("bundles"); err != nil {
 		logrus.Panic("Failed to set required `bundles` flag for `index add`")
 	}
-	indexCmd.Flags().StringP("binary-image", "i", "", "container image for on-image `opm` command")
+	indexCmd.Flags().StringP("binary-image", "i", "", "container image for on-image `opm` command. With --build-tool=none, a comma separated list of images of different platforms builds a multi-arch image")
 	indexCmd.Flags().StringP("container-tool", "c", "", "tool to interact with container images (save, build, etc.). One of: [docker, podman]")
-	indexCmd.Flags().StringP("build-tool", "u", "", "tool to build container images. One of: [docker, podman]. Defaults to podman. Overrides part of container-tool.")
+	indexCmd.Flags().StringP("build-tool", "u", "", "tool to build container images. One of: [none, docker, podman]. Defaults to podman. Overrides part of container-tool.")
 	indexCmd.Flags().StringP("pull-tool", "p", "", "tool to pull container images. One of: [none, docker, podman]. Defaults to none. Overrides part of container-tool.")
+	indexCmd.Flags().String("build-output", "", "where to write the image built with --build-tool=none. One of: [oci:<dir>, docker-archive:<file>]")
 	indexCmd.Flags().StringP("tag", "t", "", "custom tag for container image being built")
 	indexCmd.Flags().Bool("permissive", false, "allow registry load errors")
+	indexCmd.Flags().String("platform", "", "platform of the images to pull from the multi-platform images of --from-index and --bundles, such as linux/arm64; defaults to the host platform")
+	indexCmd.Flags().Int("pull-concurrency", lregistry.DefaultPullConcurrency, "number of bundle images pulled and unpacked at once")
+	indexCmd.Flags().StringSlice("signature-key", nil, "public key files to verify the cosign signatures of bundle images with before they're pulled; bundles are not verified if unset")
+	indexCmd.Flags().String("signature-policy", string(signature.PolicyEnforce), "what happens to bundle images whose signatures can't be verified. One of: [enforce, warn]")
 	indexCmd.Flags().StringP("mode", "", "replaces", "graph update mode that defines how channel graphs are updated. One of: [replaces, semver, semver-skippatch]")
 
 	indexCmd.Flags().Bool("overwrite-latest", false, "overwrite the latest bundles (channel heads) with those of the same csv name given by --bundles")
@@ -143,11 +151,42 @@ func runIndexAddCmdFunc(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	pullConcurrency, err := cmd.Flags().GetInt("pull-concurrency")
+	if err != nil {
+		return err
+	}
+	if pullConcurrency < 1 {
+		return fmt.Errorf("--pull-concurrency must be at least 1, got %d", pullConcurrency)
+	}
+
+	platform, err := cmd.Flags().GetString("platform")
+	if err != nil {
+		return err
+	}
+
+	keyFiles, err := cmd.Flags().GetStringSlice("signature-key")
+	if err != nil {
+		return err
+	}
+	policyName, err := cmd.Flags().GetString("signature-policy")
+	if err != nil {
+		return err
+	}
+	policy, err := signature.ParsePolicy(policyName)
+	if err != nil {
+		return err
+	}
+
 	pullTool, buildTool, err := getContainerTools(cmd)
 	if err != nil {
 		return err
 	}
 
+	buildOutput, err := getBuildOutput(cmd, buildTool, generate)
+	if err != nil {
+		return err
+	}
+
 	logger := logrus.WithFields(logrus.Fields{"bundles": bundles})
 
 	logger.Info("building the index")
@@ -169,6 +208,17 @@ func runIndexAddCmdFunc(cmd *cobra.Command, args []string) error {
 		SkipTLS:           skipTLS,
 		Overwrite:         overwrite,
 		EnableAlpha:       enableAlpha,
+		BuildOutput:       buildOutput,
+		PullConcurrency:   pullConcurrency,
+		Platform:          platform,
+	}
+
+	if len(keyFiles) > 0 {
+		verifier, err := signature.LoadCosignVerifier(keyFiles, containerdregistry.SkipTLS(skipTLS))
+		if err != nil {
+			return err
+		}
+		request.Verifier = signature.WithPolicy(verifier, policy, logger)
 	}
 
 	err = indexAdder.AddToIndex(request)
@@ -187,10 +237,6 @@ func getContainerTools(cmd *cobra.Command) (string, string, error) {
 		return "", "", err
 	}
 
-	if buildTool == "none" {
-		return "", "", fmt.Errorf("none is not a valid container-tool for index add")
-	}
-
 	pullTool, err := cmd.Flags().GetString("pull-tool")
 	if err != nil {
 		return "", "", err
@@ -220,3 +266,23 @@ func getContainerTools(cmd *cobra.Command) (string, string, error) {
 
 	return pullTool, buildTool, nil
 }
+
+// getBuildOutput returns where to write an image built without a container tool
+func getBuildOutput(cmd *cobra.Command, buildTool string, generate bool) (string, error) {
+	buildOutput, err := cmd.Flags().GetString("build-output")
+	if err != nil {
+		return "", err
+	}
+
+	if buildTool != "none" || generate {
+		if buildOutput != "" {
+			return "", fmt.Errorf("build-output can only be set with --build-tool=none")
+		}
+		return "", nil
+	}
+
+	if buildOutput == "" {
+		return "", fmt.Errorf("build-output is required with --build-tool=none")
+	}
+	return buildOutput, nil
+}
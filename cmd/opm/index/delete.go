@@ -35,10 +35,11 @@ func newIndexDeleteCmd() *cobra.Command {
 	if err := indexCmd.MarkFlagRequired("operators"); err != nil {
 		logrus.Panic("Failed to set required `operators` flag for `index delete`")
 	}
-	indexCmd.Flags().StringP("binary-image", "i", "", "container image for on-image `opm` command")
+	indexCmd.Flags().StringP("binary-image", "i", "", "container image for on-image `opm` command. With --build-tool=none, a comma separated list of images of different platforms builds a multi-arch image")
 	indexCmd.Flags().StringP("container-tool", "c", "", "tool to interact with container images (save, build, etc.). One of: [none, docker, podman]")
-	indexCmd.Flags().StringP("build-tool", "u", "", "tool to build container images. One of: [docker, podman]. Defaults to podman. Overrides part of container-tool.")
+	indexCmd.Flags().StringP("build-tool", "u", "", "tool to build container images. One of: [none, docker, podman]. Defaults to podman. Overrides part of container-tool.")
 	indexCmd.Flags().StringP("pull-tool", "p", "", "tool to pull container images. One of: [none, docker, podman]. Defaults to none. Overrides part of container-tool.")
+	indexCmd.Flags().String("build-output", "", "where to write the image built with --build-tool=none. One of: [oci:<dir>, docker-archive:<file>]")
 	indexCmd.Flags().StringP("tag", "t", "", "custom tag for container image being built")
 	indexCmd.Flags().Bool("permissive", false, "allow registry load errors")
 
@@ -81,6 +82,11 @@ func runIndexDeleteCmdFunc(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	buildOutput, err := getBuildOutput(cmd, buildTool, generate)
+	if err != nil {
+		return err
+	}
+
 	tag, err := cmd.Flags().GetString("tag")
 	if err != nil {
 		return err
@@ -114,6 +120,7 @@ func runIndexDeleteCmdFunc(cmd *cobra.Command, args []string) error {
 		Tag:               tag,
 		Permissive:        permissive,
 		SkipTLS:           skipTLS,
+		BuildOutput:       buildOutput,
 	}
 
 	err = indexDeleter.DeleteFromIndex(request)
@@ -51,10 +51,11 @@ func newIndexDeprecateTruncateCmd() *cobra.Command {
 	if err := indexCmd.MarkFlagRequired("bundles"); err != nil {
 		logrus.Panic("Failed to set required `bundles` flag for `index add`")
 	}
-	indexCmd.Flags().StringP("binary-image", "i", "", "container image for on-image `opm` command")
+	indexCmd.Flags().StringP("binary-image", "i", "", "container image for on-image `opm` command. With --build-tool=none, a comma separated list of images of different platforms builds a multi-arch image")
 	indexCmd.Flags().StringP("container-tool", "c", "", "tool to interact with container images (save, build, etc.). One of: [docker, podman]")
-	indexCmd.Flags().StringP("build-tool", "u", "", "tool to build container images. One of: [docker, podman]. Defaults to podman. Overrides part of container-tool.")
+	indexCmd.Flags().StringP("build-tool", "u", "", "tool to build container images. One of: [none, docker, podman]. Defaults to podman. Overrides part of container-tool.")
 	indexCmd.Flags().StringP("pull-tool", "p", "", "tool to pull container images. One of: [none, docker, podman]. Defaults to none. Overrides part of container-tool.")
+	indexCmd.Flags().String("build-output", "", "where to write the image built with --build-tool=none. One of: [oci:<dir>, docker-archive:<file>]")
 	indexCmd.Flags().StringP("tag", "t", "", "custom tag for container image being built")
 	indexCmd.Flags().Bool("permissive", false, "allow registry load errors")
 	if err := indexCmd.Flags().MarkHidden("debug"); err != nil {
@@ -105,6 +106,11 @@ func runIndexDeprecateTruncateCmdFunc(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	buildOutput, err := getBuildOutput(cmd, buildTool, generate)
+	if err != nil {
+		return err
+	}
+
 	skipTLS, err := cmd.Flags().GetBool("skip-tls")
 	if err != nil {
 		return err
@@ -128,6 +134,7 @@ func runIndexDeprecateTruncateCmdFunc(cmd *cobra.Command, args []string) error {
 		Bundles:           bundles,
 		Permissive:        permissive,
 		SkipTLS:           skipTLS,
+		BuildOutput:       buildOutput,
 	}
 
 	err = indexDeprecator.DeprecateFromIndex(request)
@@ -1,8 +1,6 @@
 package index
 
 import (
-	"fmt"
-
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
@@ -38,7 +36,10 @@ func newIndexPruneCmd() *cobra.Command {
 		logrus.Panic("Failed to set required `packages` flag for `index prune`")
 	}
 	indexCmd.Flags().StringP("binary-image", "i", "", "container image for on-image `opm` command")
-	indexCmd.Flags().StringP("container-tool", "c", "podman", "tool to interact with container images (save, build, etc.). One of: [docker, podman]")
+	indexCmd.Flags().StringP("container-tool", "c", "", "tool to interact with container images (save, build, etc.). One of: [none, docker, podman]")
+	indexCmd.Flags().StringP("build-tool", "u", "", "tool to build container images. One of: [none, docker, podman]. Defaults to podman. Overrides part of container-tool.")
+	indexCmd.Flags().String("pull-tool", "", "tool to pull container images. One of: [none, docker, podman]. Defaults to none. Overrides part of container-tool.")
+	indexCmd.Flags().String("build-output", "", "where to write the image built with --build-tool=none. One of: [oci:<dir>, docker-archive:<file>]")
 	indexCmd.Flags().StringP("tag", "t", "", "custom tag for container image being built")
 	indexCmd.Flags().Bool("permissive", false, "allow registry load errors")
 
@@ -76,13 +77,14 @@ func runIndexPruneCmdFunc(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	containerTool, err := cmd.Flags().GetString("container-tool")
+	pullTool, buildTool, err := getContainerTools(cmd)
 	if err != nil {
 		return err
 	}
 
-	if containerTool == "none" {
-		return fmt.Errorf("none is not a valid container-tool for index prune")
+	buildOutput, err := getBuildOutput(cmd, buildTool, generate)
+	if err != nil {
+		return err
 	}
 
 	tag, err := cmd.Flags().GetString("tag")
@@ -104,7 +106,10 @@ func runIndexPruneCmdFunc(cmd *cobra.Command, args []string) error {
 
 	logger.Info("pruning the index")
 
-	indexPruner := indexer.NewIndexPruner(containertools.NewContainerTool(containerTool, containertools.PodmanTool), logger)
+	indexPruner := indexer.NewIndexPruner(
+		containertools.NewContainerTool(buildTool, containertools.PodmanTool),
+		containertools.NewContainerTool(pullTool, containertools.NoneTool),
+		logger)
 
 	request := indexer.PruneFromIndexRequest{
 		Generate:          generate,
@@ -115,6 +120,7 @@ func runIndexPruneCmdFunc(cmd *cobra.Command, args []string) error {
 		Tag:               tag,
 		Permissive:        permissive,
 		SkipTLS:           skipTLS,
+		BuildOutput:       buildOutput,
 	}
 
 	err = indexPruner.PruneFromIndex(request)
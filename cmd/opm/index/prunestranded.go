@@ -1,8 +1,6 @@
 package index
 
 import (
-	"fmt"
-
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
@@ -34,7 +32,10 @@ func newIndexPruneStrandedCmd() *cobra.Command {
 		logrus.Panic("Failed to set required `from-index` flag for `index prune-stranded`")
 	}
 	indexCmd.Flags().StringP("binary-image", "i", "", "container image for on-image `opm` command")
-	indexCmd.Flags().StringP("container-tool", "c", "podman", "tool to interact with container images (save, build, etc.). One of: [docker, podman]")
+	indexCmd.Flags().StringP("container-tool", "c", "", "tool to interact with container images (save, build, etc.). One of: [none, docker, podman]")
+	indexCmd.Flags().StringP("build-tool", "u", "", "tool to build container images. One of: [none, docker, podman]. Defaults to podman. Overrides part of container-tool.")
+	indexCmd.Flags().StringP("pull-tool", "p", "", "tool to pull container images. One of: [none, docker, podman]. Defaults to none. Overrides part of container-tool.")
+	indexCmd.Flags().String("build-output", "", "where to write the image built with --build-tool=none. One of: [oci:<dir>, docker-archive:<file>]")
 	indexCmd.Flags().StringP("tag", "t", "", "custom tag for container image being built")
 
 	if err := indexCmd.Flags().MarkHidden("debug"); err != nil {
@@ -66,13 +67,14 @@ func runIndexPruneStrandedCmdFunc(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	containerTool, err := cmd.Flags().GetString("container-tool")
+	pullTool, buildTool, err := getContainerTools(cmd)
 	if err != nil {
 		return err
 	}
 
-	if containerTool == "none" {
-		return fmt.Errorf("none is not a valid container-tool for index prune")
+	buildOutput, err := getBuildOutput(cmd, buildTool, generate)
+	if err != nil {
+		return err
 	}
 
 	tag, err := cmd.Flags().GetString("tag")
@@ -89,7 +91,10 @@ func runIndexPruneStrandedCmdFunc(cmd *cobra.Command, args []string) error {
 
 	logger.Info("pruning stranded bundles from the index")
 
-	indexPruner := indexer.NewIndexStrandedPruner(containertools.NewContainerTool(containerTool, containertools.PodmanTool), logger)
+	indexPruner := indexer.NewIndexStrandedPruner(
+		containertools.NewContainerTool(buildTool, containertools.PodmanTool),
+		containertools.NewContainerTool(pullTool, containertools.NoneTool),
+		logger)
 
 	request := indexer.PruneStrandedFromIndexRequest{
 		Generate:          generate,
@@ -98,6 +103,7 @@ func runIndexPruneStrandedCmdFunc(cmd *cobra.Command, args []string) error {
 		OutDockerfile:     outDockerfile,
 		Tag:               tag,
 		SkipTLS:           skipTLS,
+		BuildOutput:       buildOutput,
 	}
 
 	err = indexPruner.PruneStrandedFromIndex(request)
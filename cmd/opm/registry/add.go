@@ -8,6 +8,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/operator-framework/operator-registry/pkg/containertools"
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+	"github.com/operator-framework/operator-registry/pkg/image/signature"
+	"github.com/operator-framework/operator-registry/pkg/lib/certs"
 	"github.com/operator-framework/operator-registry/pkg/lib/registry"
 	reg "github.com/operator-framework/operator-registry/pkg/registry"
 )
@@ -30,12 +33,16 @@ func newRegistryAddCmd() *cobra.Command {
 
 	rootCmd.Flags().Bool("debug", false, "enable debug logging")
 	rootCmd.Flags().StringP("database", "d", "bundles.db", "relative path to database file")
-	rootCmd.Flags().StringSliceP("bundle-images", "b", []string{}, "comma separated list of links to bundle image")
+	rootCmd.Flags().StringSliceP("bundle-images", "b", []string{}, "comma separated list of links to bundle image, which may be local: oci:<dir>[:tag] or docker-archive:<file>")
 	rootCmd.Flags().Bool("permissive", false, "allow registry load errors")
 	rootCmd.Flags().Bool("skip-tls", false, "skip TLS certificate verification for container image registries while pulling bundles")
 	rootCmd.Flags().String("ca-file", "", "the root certificates to use when --container-tool=none; see docker/podman docs for certificate loading instructions")
 	rootCmd.Flags().StringP("mode", "", "replaces", "graph update mode that defines how channel graphs are updated. One of: [replaces, semver, semver-skippatch]")
+	rootCmd.Flags().Int("pull-concurrency", registry.DefaultPullConcurrency, "number of bundle images pulled and unpacked at once")
+	rootCmd.Flags().StringSlice("signature-key", nil, "public key files to verify the cosign signatures of bundle images with before they're pulled; bundles are not verified if unset")
+	rootCmd.Flags().String("signature-policy", string(signature.PolicyEnforce), "what happens to bundle images whose signatures can't be verified. One of: [enforce, warn]")
 	rootCmd.Flags().StringP("container-tool", "c", "none", "tool to interact with container images (save, build, etc.). One of: [none, docker, podman]")
+	rootCmd.Flags().String("platform", "", "platform of the images to pull from multi-platform bundle images, such as linux/arm64; defaults to the host platform")
 
 	return rootCmd
 }
@@ -61,11 +68,34 @@ func addFunc(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	pullConcurrency, err := cmd.Flags().GetInt("pull-concurrency")
+	if err != nil {
+		return err
+	}
+	if pullConcurrency < 1 {
+		return fmt.Errorf("--pull-concurrency must be at least 1, got %d", pullConcurrency)
+	}
+	keyFiles, err := cmd.Flags().GetStringSlice("signature-key")
+	if err != nil {
+		return err
+	}
+	policyName, err := cmd.Flags().GetString("signature-policy")
+	if err != nil {
+		return err
+	}
+	policy, err := signature.ParsePolicy(policyName)
+	if err != nil {
+		return err
+	}
 	containerToolStr, err := cmd.Flags().GetString("container-tool")
 	if err != nil {
 		return err
 	}
 	containerTool := containertools.NewContainerTool(containerToolStr, containertools.NoneTool)
+	platform, err := cmd.Flags().GetString("platform")
+	if err != nil {
+		return err
+	}
 	mode, err := cmd.Flags().GetString("mode")
 	if err != nil {
 		return err
@@ -86,14 +116,16 @@ func addFunc(cmd *cobra.Command, args []string) error {
 	}
 
 	request := registry.AddToRegistryRequest{
-		Permissive:    permissive,
-		SkipTLS:       skipTLS,
-		CaFile:        caFile,
-		InputDatabase: fromFilename,
-		Bundles:       bundleImages,
-		Mode:          modeEnum,
-		ContainerTool: containerTool,
-		Overwrite:     false,
+		Permissive:      permissive,
+		SkipTLS:         skipTLS,
+		CaFile:          caFile,
+		InputDatabase:   fromFilename,
+		Bundles:         bundleImages,
+		Mode:            modeEnum,
+		ContainerTool:   containerTool,
+		Overwrite:       false,
+		PullConcurrency: pullConcurrency,
+		Platform:        platform,
 	}
 
 	logger := logrus.WithFields(logrus.Fields{"bundles": bundleImages})
@@ -102,6 +134,18 @@ func addFunc(cmd *cobra.Command, args []string) error {
 		logger.Warn("--skip-tls flag is set: this mode is insecure and meant for development purposes only.")
 	}
 
+	if len(keyFiles) > 0 {
+		rootCAs, err := certs.RootCAs(caFile)
+		if err != nil {
+			return fmt.Errorf("failed to get RootCAs: %v", err)
+		}
+		verifier, err := signature.LoadCosignVerifier(keyFiles, containerdregistry.SkipTLS(skipTLS), containerdregistry.WithRootCAs(rootCAs))
+		if err != nil {
+			return err
+		}
+		request.Verifier = signature.WithPolicy(verifier, policy, logger)
+	}
+
 	logger.Info("adding to the registry")
 
 	registryAdder := registry.NewRegistryAdder(logger)
@@ -1,9 +1,17 @@
 package registry
 
 import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/operator-framework/operator-registry/pkg/containertools"
+	"github.com/operator-framework/operator-registry/pkg/lib/certs"
+	"github.com/operator-framework/operator-registry/pkg/lib/indexer"
 	"github.com/operator-framework/operator-registry/pkg/mirror"
 )
 
@@ -12,8 +20,13 @@ func MirrorCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Hidden: true,
 		Use:    "mirror [src image] [dest image]",
+		Args:   cobra.ExactArgs(2),
 		Short:  "mirror an operator-registry catalog",
-		Long:   `mirror an operator-registry catalog image from one registry to another`,
+		Long: `mirror an operator-registry catalog image from one registry to another
+
+Every image referenced by the catalog is copied to the destination registry, including
+the images of every platform of manifest lists. Content already in the destination is
+skipped, so an interrupted mirror resumes when run again.`,
 
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			if debug, _ := cmd.Flags().GetBool("debug"); debug {
@@ -26,7 +39,56 @@ func MirrorCmd() *cobra.Command {
 			src := args[0]
 			dest := args[1]
 
-			mirrorer, err := mirror.NewIndexImageMirror(o.ToOption(), mirror.WithSource(src), mirror.WithDest(dest))
+			skipTLS, err := cmd.Flags().GetBool("skip-tls")
+			if err != nil {
+				return err
+			}
+			caFile, err := cmd.Flags().GetString("ca-file")
+			if err != nil {
+				return err
+			}
+			if caFile != "" && skipTLS {
+				return errors.New("--skip-tls must be false when --ca-file is set")
+			}
+			blobConcurrency, err := cmd.Flags().GetInt("blob-concurrency")
+			if err != nil {
+				return err
+			}
+			if blobConcurrency < 1 {
+				return fmt.Errorf("--blob-concurrency must be at least 1, got %d", blobConcurrency)
+			}
+			rootCAs, err := certs.RootCAs(caFile)
+			if err != nil {
+				return fmt.Errorf("failed to get RootCAs: %v", err)
+			}
+
+			logger := logrus.WithFields(logrus.Fields{"src": src, "dest": dest})
+			imageMirrorer, err := mirror.NewRegistryMirrorer(
+				mirror.WithLogger(logger),
+				mirror.WithSkipTLS(skipTLS),
+				mirror.WithRootCAs(rootCAs),
+				mirror.WithBlobConcurrency(blobConcurrency),
+			)
+			if err != nil {
+				return err
+			}
+
+			buildDir, err := ioutil.TempDir("", "mirror-")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(buildDir)
+			extractor := indexer.ImageIndexer{PullTool: containertools.NoneTool, Logger: logger}
+			var extract mirror.DatabaseExtractorFunc = func(from string) (string, error) {
+				return extractor.ExtractDatabase(buildDir, from, caFile, skipTLS)
+			}
+
+			mirrorer, err := mirror.NewIndexImageMirror(o.ToOption(),
+				mirror.WithSource(src),
+				mirror.WithDest(dest),
+				mirror.WithMirrorer(imageMirrorer),
+				mirror.WithExtractor(extract),
+			)
 			if err != nil {
 				return err
 			}
@@ -40,7 +102,9 @@ func MirrorCmd() *cobra.Command {
 	flags := cmd.Flags()
 
 	cmd.Flags().Bool("debug", false, "Enable debug logging.")
-	flags.StringVar(&o.ManifestDir, "--to-manifests", "manifests", "Local path to store manifests.")
+	flags.StringVar(&o.ManifestDir, "to-manifests", "manifests", "Local path to store manifests.")
+	flags.String("ca-file", "", "the root certificates to trust for container image registries")
+	flags.Int("blob-concurrency", mirror.DefaultBlobConcurrency, "number of blobs copied at once")
 
 	return cmd
 }
@@ -1,25 +1,12 @@
 package main
 
 import (
-	"context"
-	"database/sql"
-	"fmt"
-	"net"
-	"os"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/reflection"
 
-	"github.com/operator-framework/operator-registry/pkg/api"
-	health "github.com/operator-framework/operator-registry/pkg/api/grpc_health_v1"
-	"github.com/operator-framework/operator-registry/pkg/lib/dns"
-	"github.com/operator-framework/operator-registry/pkg/lib/graceful"
-	"github.com/operator-framework/operator-registry/pkg/lib/log"
-	"github.com/operator-framework/operator-registry/pkg/lib/tmp"
-	"github.com/operator-framework/operator-registry/pkg/server"
-	"github.com/operator-framework/operator-registry/pkg/sqlite"
+	"github.com/operator-framework/operator-registry/internal/serving"
 )
 
 var rootCmd = &cobra.Command{
@@ -39,9 +26,9 @@ var rootCmd = &cobra.Command{
 func init() {
 	rootCmd.Flags().Bool("debug", false, "enable debug logging")
 	rootCmd.Flags().StringP("database", "d", "bundles.db", "relative path to sqlite db")
-	rootCmd.Flags().StringP("port", "p", "50051", "port number to serve on")
-	rootCmd.Flags().StringP("termination-log", "t", "/dev/termination-log", "path to a container termination log file")
+	serving.AddFlags(rootCmd.Flags())
 	rootCmd.Flags().Bool("skip-migrate", false, "do  not attempt to migrate to the latest db revision when starting")
+	rootCmd.Flags().String("serve-mode", serving.ServeModeSQL, "how to serve the db: \"sql\" queries it for every request, \"memory\" loads it into memory at startup")
 	if err := rootCmd.Flags().MarkHidden("debug"); err != nil {
 		logrus.Panic(err.Error())
 	}
@@ -54,93 +41,35 @@ func main() {
 }
 
 func runCmdFunc(cmd *cobra.Command, args []string) error {
-	// Immediately set up termination log
-	terminationLogPath, err := cmd.Flags().GetString("termination-log")
-	if err != nil {
-		return err
-	}
-	err = log.AddDefaultWriterHooks(terminationLogPath)
-	if err != nil {
-		logrus.WithError(err).Warn("unable to set termination log path")
-	}
-	// Ensure there is a default nsswitch config
-	if err := dns.EnsureNsswitch(); err != nil {
-		logrus.WithError(err).Warn("unable to write default nsswitch config")
-	}
-	dbName, err := cmd.Flags().GetString("database")
+	opts, err := serving.OptionsFromFlags(cmd.Flags())
 	if err != nil {
 		return err
 	}
+	// Immediately set up termination log
+	serving.Prepare(logrus.StandardLogger(), opts)
 
-	port, err := cmd.Flags().GetString("port")
+	dbName, err := cmd.Flags().GetString("database")
 	if err != nil {
 		return err
 	}
-
-	logger := logrus.WithFields(logrus.Fields{"database": dbName, "port": port})
-
-	// make a writable copy of the db for migrations
-	tmpdb, err := tmp.CopyTmpDB(dbName)
+	skipMigrate, err := cmd.Flags().GetBool("skip-migrate")
 	if err != nil {
 		return err
 	}
-	defer os.Remove(tmpdb)
-
-	db, err := sqlite.Open(tmpdb)
+	serveMode, err := cmd.Flags().GetString("serve-mode")
 	if err != nil {
 		return err
 	}
 
-	// migrate to the latest version
-	if err := migrate(cmd, db); err != nil {
-		logger.WithError(err).Warnf("couldn't migrate db")
-	}
-
-	store := sqlite.NewSQLLiteQuerierFromDb(db)
-
-	// sanity check that the db is available
-	tables, err := store.ListTables(context.TODO())
-	if err != nil {
-		logger.WithError(err).Warnf("couldn't list tables in db")
-	}
-	if len(tables) == 0 {
-		logger.Warn("no tables found in db")
-	}
-
-	lis, err := net.Listen("tcp", ":"+port)
-	if err != nil {
-		logger.Fatalf("failed to listen: %s", err)
-	}
-	s := grpc.NewServer()
-
-	api.RegisterRegistryServer(s, server.NewRegistryServer(store))
-	health.RegisterHealthServer(s, server.NewHealthServer())
-	reflection.Register(s)
-	logger.Info("serving registry")
-
-	return graceful.Shutdown(logger, func() error {
-		return s.Serve(lis)
-	}, func() {
-		s.GracefulStop()
-	})
-}
-
-func migrate(cmd *cobra.Command, db *sql.DB) error {
-	shouldSkipMigrate, err := cmd.Flags().GetBool("skip-migrate")
-	if err != nil {
-		return err
-	}
-	if shouldSkipMigrate {
-		return nil
-	}
+	logger := logrus.WithFields(logrus.Fields{"database": dbName, "port": opts.Port, "serve-mode": serveMode})
 
-	migrator, err := sqlite.NewSQLLiteMigrator(db)
+	loadStart := time.Now()
+	store, info, err := serving.LoadDB(cmd.Context(), logger, dbName, skipMigrate, serveMode)
 	if err != nil {
 		return err
 	}
-	if migrator == nil {
-		return fmt.Errorf("failed to load migrator")
-	}
+	opts.CatalogInfo = info
+	opts.LoadDuration = time.Since(loadStart)
 
-	return migrator.Migrate(context.TODO())
+	return serving.Serve(cmd.Context(), logger, store, opts)
 }
@@ -0,0 +1,238 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/operator-framework/operator-registry/internal/declcfg"
+	"github.com/operator-framework/operator-registry/internal/property"
+	"github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/sqlite"
+)
+
+// Pin replaces the references by tag to the bundle images and related images of a catalog
+// with references by digest, resolved through Registry. The catalog is a declarative config
+// directory or an sqlite database file, and it's rewritten in place unless DryRun is set.
+type Pin struct {
+	Ref      string
+	Registry image.Registry
+	DryRun   bool
+}
+
+// PinnedImage is an image of a bundle whose reference by tag was replaced with a reference by digest.
+type PinnedImage struct {
+	Bundle string
+	Tag    string
+	Image  string
+}
+
+func (p Pin) Run(ctx context.Context) ([]PinnedImage, error) {
+	stat, err := os.Stat(p.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Registry == nil {
+		reg, err := Render{}.createRegistry()
+		if err != nil {
+			return nil, fmt.Errorf("create registry: %v", err)
+		}
+		defer reg.Destroy()
+		p.Registry = reg
+	}
+
+	var pinned []PinnedImage
+	if stat.IsDir() {
+		pinned, err = p.pinDeclcfg(ctx)
+	} else {
+		if err := checkDBFile(p.Ref); err != nil {
+			return nil, err
+		}
+		pinned, err = p.pinSqlite(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pin images of %q: %v", p.Ref, err)
+	}
+
+	sort.Slice(pinned, func(i, j int) bool {
+		if pinned[i].Bundle != pinned[j].Bundle {
+			return pinned[i].Bundle < pinned[j].Bundle
+		}
+		return pinned[i].Tag < pinned[j].Tag
+	})
+	return pinned, nil
+}
+
+func (p Pin) pinDeclcfg(ctx context.Context) ([]PinnedImage, error) {
+	root := os.DirFS(p.Ref)
+	cfg, err := declcfg.LoadFS(root)
+	if err != nil {
+		return nil, err
+	}
+	var refs []string
+	for _, b := range cfg.Bundles {
+		refs = append(refs, b.Image)
+		for _, ri := range b.RelatedImages {
+			refs = append(refs, ri.Image)
+		}
+	}
+	digests, err := p.resolve(ctx, refs)
+	if err != nil || len(digests) == 0 {
+		return nil, err
+	}
+
+	// Rewrite each file with pinned images as a whole, in the format it was read in.
+	var pinned []PinnedImage
+	files := map[string]*declcfg.DeclarativeConfig{}
+	if err := declcfg.WalkFS(root, func(path string, fileCfg *declcfg.DeclarativeConfig) error {
+		if filePinned := pinBundles(fileCfg.Bundles, digests); len(filePinned) > 0 {
+			pinned = append(pinned, filePinned...)
+			files[path] = fileCfg
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if p.DryRun {
+		return pinned, nil
+	}
+
+	for path, fileCfg := range files {
+		write := declcfg.WriteJSON
+		switch filepath.Ext(path) {
+		case ".yaml", ".yml":
+			write = declcfg.WriteYAML
+		}
+		var buf bytes.Buffer
+		if err := write(*fileCfg, &buf); err != nil {
+			return nil, fmt.Errorf("write %q: %v", path, err)
+		}
+		path = filepath.Join(p.Ref, filepath.FromSlash(path))
+		stat, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(path, buf.Bytes(), stat.Mode()); err != nil {
+			return nil, err
+		}
+	}
+	return pinned, nil
+}
+
+// pinBundles replaces the images of bundles that have a digest, recording the replaced references in their properties.
+func pinBundles(bundles []declcfg.Bundle, digests map[string]string) []PinnedImage {
+	var pinned []PinnedImage
+	for i := range bundles {
+		b := &bundles[i]
+		tags := sets.NewString()
+		if digest, ok := digests[b.Image]; ok {
+			tags.Insert(b.Image)
+			b.Image = digest
+		}
+		for j, ri := range b.RelatedImages {
+			if digest, ok := digests[ri.Image]; ok {
+				tags.Insert(ri.Image)
+				b.RelatedImages[j].Image = digest
+			}
+		}
+		for _, tag := range tags.List() {
+			b.Properties = append(b.Properties, property.MustBuildPinnedImage(digests[tag], tag))
+			pinned = append(pinned, PinnedImage{Bundle: b.Name, Tag: tag, Image: digests[tag]})
+		}
+	}
+	return pinned
+}
+
+func (p Pin) pinSqlite(ctx context.Context) ([]PinnedImage, error) {
+	db, err := p.openSqlite(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	pinner := sqlite.NewSQLImagePinner(db)
+	images, err := pinner.Images(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var refs []string
+	for _, img := range images {
+		refs = append(refs, img.Image)
+	}
+	digests, err := p.resolve(ctx, refs)
+	if err != nil || len(digests) == 0 {
+		return nil, err
+	}
+
+	var pinned []PinnedImage
+	for _, img := range images {
+		if digest, ok := digests[img.Image]; ok {
+			pinned = append(pinned, PinnedImage{Bundle: img.Bundle, Tag: img.Image, Image: digest})
+		}
+	}
+	if p.DryRun {
+		return pinned, nil
+	}
+	if err := pinner.Pin(ctx, digests); err != nil {
+		return nil, err
+	}
+	return pinned, nil
+}
+
+// openSqlite opens the database at the latest migration. Dry runs leave the file untouched:
+// it's opened read-only, and migrated in memory if its schema is outdated.
+func (p Pin) openSqlite(ctx context.Context) (*sql.DB, error) {
+	if p.DryRun {
+		return sqlite.OpenReadOnlyMigrated(ctx, p.Ref)
+	}
+
+	db, err := sqlite.Open(p.Ref)
+	if err != nil {
+		return nil, err
+	}
+	migrator, err := sqlite.NewSQLLiteMigrator(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if migrator == nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to load migrator")
+	}
+	if err := migrator.Migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// resolve returns the references by digest of the images referenced by tag, keyed by the references by tag.
+// Images referenced by digest already are skipped.
+func (p Pin) resolve(ctx context.Context, refs []string) (map[string]string, error) {
+	digests := map[string]string{}
+	var errs []error
+	for _, ref := range sets.NewString(refs...).List() {
+		if ref == "" || strings.Contains(ref, "@") {
+			continue
+		}
+		digested, err := p.Registry.Resolve(ctx, image.SimpleReference(ref))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("resolve %q: %v", ref, err))
+			continue
+		}
+		if digested.String() != ref {
+			digests[ref] = digested.String()
+		}
+	}
+	return digests, utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,126 @@
+package action_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/internal/action"
+	"github.com/operator-framework/operator-registry/internal/declcfg"
+	"github.com/operator-framework/operator-registry/internal/property"
+	"github.com/operator-framework/operator-registry/pkg/image"
+)
+
+func TestPin(t *testing.T) {
+	ctx := context.Background()
+	tags := []string{
+		"test.registry/foo-operator/foo-bundle:v0.1.0",
+		"test.registry/foo-operator/foo-bundle:v0.2.0",
+		"test.registry/foo-operator/foo:v0.1.0",
+		"test.registry/foo-operator/foo:v0.2.0",
+	}
+	reg := &image.MockRegistry{RemoteImages: map[image.Reference]*image.MockImage{}}
+	digests := map[string]string{}
+	for _, tag := range tags {
+		reg.RemoteImages[image.SimpleReference(tag)] = &image.MockImage{}
+		digested, err := reg.Resolve(ctx, image.SimpleReference(tag))
+		require.NoError(t, err)
+		digests[tag] = digested.String()
+	}
+	expectPinned := []action.PinnedImage{
+		{Bundle: "foo.v0.1.0", Tag: tags[0], Image: digests[tags[0]]},
+		{Bundle: "foo.v0.1.0", Tag: tags[2], Image: digests[tags[2]]},
+		{Bundle: "foo.v0.2.0", Tag: tags[1], Image: digests[tags[1]]},
+		{Bundle: "foo.v0.2.0", Tag: tags[3], Image: digests[tags[3]]},
+	}
+	requirePinned := func(t *testing.T, cfg *declcfg.DeclarativeConfig) {
+		require.Len(t, cfg.Bundles, 2)
+		for _, b := range cfg.Bundles {
+			props, err := property.Parse(b.Properties)
+			require.NoError(t, err)
+			for _, pin := range props.PinnedImages {
+				require.Equal(t, digests[pin.Tag], pin.Image)
+			}
+			require.Len(t, props.PinnedImages, 2)
+			require.Len(t, b.RelatedImages, 2)
+			for _, ri := range b.RelatedImages {
+				require.Contains(t, ri.Image, "@sha256:")
+			}
+			require.Contains(t, b.Image, "@sha256:")
+		}
+	}
+
+	t.Run("Declcfg", func(t *testing.T) {
+		dir := t.TempDir()
+		original, err := declcfgImage.ReadFile("testdata/foo-index-v0.2.0-declcfg/foo/index.yaml")
+		require.NoError(t, err)
+		file := filepath.Join(dir, "index.yaml")
+		require.NoError(t, os.WriteFile(file, original, 0644))
+
+		pinned, err := action.Pin{Ref: dir, Registry: reg, DryRun: true}.Run(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expectPinned, pinned)
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		require.Equal(t, original, data)
+
+		pinned, err = action.Pin{Ref: dir, Registry: reg}.Run(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expectPinned, pinned)
+		cfg, err := declcfg.LoadFS(os.DirFS(dir))
+		require.NoError(t, err)
+		requirePinned(t, cfg)
+		require.Len(t, cfg.Packages, 1)
+
+		// Pinning is idempotent.
+		pinned, err = action.Pin{Ref: dir, Registry: reg}.Run(ctx)
+		require.NoError(t, err)
+		require.Empty(t, pinned)
+	})
+
+	t.Run("Sqlite", func(t *testing.T) {
+		dbFile := filepath.Join(t.TempDir(), "index.db")
+		require.NoError(t, generateSqliteFile(dbFile, map[image.Reference]string{
+			image.SimpleReference(tags[0]): "testdata/foo-bundle-v0.1.0",
+			image.SimpleReference(tags[1]): "testdata/foo-bundle-v0.2.0",
+		}))
+		original, err := os.ReadFile(dbFile)
+		require.NoError(t, err)
+
+		pinned, err := action.Pin{Ref: dbFile, Registry: reg, DryRun: true}.Run(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expectPinned, pinned)
+		data, err := os.ReadFile(dbFile)
+		require.NoError(t, err)
+		require.Equal(t, original, data)
+
+		pinned, err = action.Pin{Ref: dbFile, Registry: reg}.Run(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expectPinned, pinned)
+
+		cfg, err := action.Render{Refs: []string{dbFile}, Registry: reg}.Run(ctx)
+		require.NoError(t, err)
+		requirePinned(t, cfg)
+	})
+
+	t.Run("Unresolved", func(t *testing.T) {
+		dir := t.TempDir()
+		original, err := declcfgImage.ReadFile("testdata/foo-index-v0.2.0-declcfg/foo/index.yaml")
+		require.NoError(t, err)
+		file := filepath.Join(dir, "index.yaml")
+		require.NoError(t, os.WriteFile(file, original, 0644))
+
+		missing := &image.MockRegistry{RemoteImages: map[image.Reference]*image.MockImage{
+			image.SimpleReference(tags[0]): {},
+		}}
+		_, err = action.Pin{Ref: dir, Registry: missing}.Run(ctx)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), tags[3])
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		require.Equal(t, original, data)
+	})
+}
@@ -0,0 +1,156 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/client"
+)
+
+// Query runs read-only queries against a registry.
+type Query struct {
+	Client client.Interface
+}
+
+// ListPackages returns every package in the registry, sorted by name.
+func (q Query) ListPackages(ctx context.Context) ([]*api.Package, error) {
+	it, err := q.Client.ListPackages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list packages: %v", err)
+	}
+	var names []string
+	for name := it.Next(); name != nil; name = it.Next() {
+		names = append(names, name.GetName())
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("list packages: %v", err)
+	}
+	sort.Strings(names)
+
+	pkgs := make([]*api.Package, 0, len(names))
+	for _, name := range names {
+		pkg, err := q.GetPackage(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+// GetPackage returns the package named name, with its channels sorted by name.
+func (q Query) GetPackage(ctx context.Context, name string) (*api.Package, error) {
+	pkg, err := q.Client.GetPackage(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("get package %q: %v", name, err)
+	}
+	sort.Slice(pkg.Channels, func(i, j int) bool { return pkg.Channels[i].GetName() < pkg.Channels[j].GetName() })
+	return pkg, nil
+}
+
+// GetBundle returns the bundle csvName in a package channel, or the channel head if
+// csvName is empty.
+func (q Query) GetBundle(ctx context.Context, packageName, channelName, csvName string) (*api.Bundle, error) {
+	var (
+		bundle *api.Bundle
+		err    error
+	)
+	if csvName == "" {
+		bundle, err = q.Client.GetBundleInPackageChannel(ctx, packageName, channelName)
+	} else {
+		bundle, err = q.Client.GetBundle(ctx, packageName, channelName, csvName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get bundle: %v", err)
+	}
+	return bundle, nil
+}
+
+// WalkReplacements returns the channel entries that replace csvName, followed by those
+// that replace them in turn, up to the heads of their channels. Entries are ordered by
+// their distance from csvName, then by package, channel, bundle and replaced bundle name.
+func (q Query) WalkReplacements(ctx context.Context, csvName string) ([]*api.ChannelEntry, error) {
+	var (
+		walked  []*api.ChannelEntry
+		visited = map[string]struct{}{csvName: {}}
+		next    = []string{csvName}
+	)
+	for len(next) > 0 {
+		var level []*api.ChannelEntry
+		for _, name := range next {
+			entries, err := drainChannelEntries(func() (*client.ChannelEntryIterator, error) {
+				return q.Client.GetChannelEntriesThatReplace(ctx, name)
+			})
+			// Stores report that nothing replaces a bundle with an error, which ends the
+			// walk along that path once past the first bundle.
+			if status.Code(err) == codes.Unknown && name != csvName {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("get channel entries that replace %q: %v", name, err)
+			}
+			level = append(level, entries...)
+		}
+		sortChannelEntries(level)
+
+		next = nil
+		for _, e := range level {
+			if _, ok := visited[e.GetBundleName()]; !ok {
+				visited[e.GetBundleName()] = struct{}{}
+				next = append(next, e.GetBundleName())
+			}
+		}
+		walked = append(walked, level...)
+	}
+	return walked, nil
+}
+
+// Providers returns the channel entries of bundles that provide the given group, version
+// and kind, sorted by package, channel, bundle and replaced bundle name. If latest is set,
+// only the latest entry in each channel is returned.
+func (q Query) Providers(ctx context.Context, group, version, kind string, latest bool) ([]*api.ChannelEntry, error) {
+	entries, err := drainChannelEntries(func() (*client.ChannelEntryIterator, error) {
+		if latest {
+			return q.Client.GetLatestChannelEntriesThatProvide(ctx, group, version, kind)
+		}
+		return q.Client.GetChannelEntriesThatProvide(ctx, group, version, kind)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get channel entries that provide %s/%s/%s: %v", group, version, kind, err)
+	}
+	sortChannelEntries(entries)
+	return entries, nil
+}
+
+func drainChannelEntries(call func() (*client.ChannelEntryIterator, error)) ([]*api.ChannelEntry, error) {
+	it, err := call()
+	if err != nil {
+		return nil, err
+	}
+	var entries []*api.ChannelEntry
+	for e := it.Next(); e != nil; e = it.Next() {
+		entries = append(entries, e)
+	}
+	return entries, it.Error()
+}
+
+func sortChannelEntries(entries []*api.ChannelEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.GetPackageName() != b.GetPackageName() {
+			return a.GetPackageName() < b.GetPackageName()
+		}
+		if a.GetChannelName() != b.GetChannelName() {
+			return a.GetChannelName() < b.GetChannelName()
+		}
+		if a.GetBundleName() != b.GetBundleName() {
+			return a.GetBundleName() < b.GetBundleName()
+		}
+		return a.GetReplaces() < b.GetReplaces()
+	})
+}
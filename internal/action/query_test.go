@@ -0,0 +1,132 @@
+package action_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/operator-framework/operator-registry/internal/action"
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/client"
+	"github.com/operator-framework/operator-registry/pkg/client/clientfakes"
+	"github.com/operator-framework/operator-registry/pkg/client/local"
+)
+
+func TestQuery(t *testing.T) {
+	c, err := local.NewClientFromDeclcfg("testdata/foo-index-v0.2.0-declcfg")
+	require.NoError(t, err)
+	defer c.Close()
+	q := action.Query{Client: c}
+	ctx := context.Background()
+
+	pkgs, err := q.ListPackages(ctx)
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1)
+	require.Equal(t, "foo", pkgs[0].GetName())
+	require.Equal(t, "beta", pkgs[0].GetDefaultChannelName())
+	require.Len(t, pkgs[0].GetChannels(), 1)
+	require.Equal(t, "foo.v0.2.0", pkgs[0].GetChannels()[0].GetCsvName())
+
+	_, err = q.GetPackage(ctx, "bar")
+	require.Error(t, err)
+
+	head, err := q.GetBundle(ctx, "foo", "beta", "")
+	require.NoError(t, err)
+	require.Equal(t, "foo.v0.2.0", head.GetCsvName())
+	bundle, err := q.GetBundle(ctx, "foo", "beta", "foo.v0.1.0")
+	require.NoError(t, err)
+	require.Equal(t, "foo.v0.1.0", bundle.GetCsvName())
+
+	replacements, err := q.WalkReplacements(ctx, "foo.v0.1.0")
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo.v0.2.0"}, bundleNames(replacements))
+	_, err = q.WalkReplacements(ctx, "foo.v0.2.0")
+	require.Error(t, err)
+
+	providers, err := q.Providers(ctx, "test.foo", "v1", "Foo", false)
+	require.NoError(t, err)
+	// There's an entry for each bundle that foo.v0.2.0 replaces or skips.
+	require.Equal(t, []string{"foo.v0.1.0", "foo.v0.2.0", "foo.v0.2.0", "foo.v0.2.0"}, bundleNames(providers))
+	require.Equal(t, "foo.v0.1.2", providers[3].GetReplaces())
+	providers, err = q.Providers(ctx, "test.foo", "v1", "Foo", true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo.v0.2.0"}, bundleNames(providers))
+}
+
+func TestQueryWalkReplacements(t *testing.T) {
+	// a is replaced by b in two channels, and by c in one; b is replaced by d.
+	replacedBy := map[string][]*api.ChannelEntry{
+		"a": {
+			{PackageName: "p", ChannelName: "stable", BundleName: "b", Replaces: "a"},
+			{PackageName: "p", ChannelName: "fast", BundleName: "c", Replaces: "a"},
+			{PackageName: "p", ChannelName: "fast", BundleName: "b", Replaces: "a"},
+		},
+		"b": {
+			{PackageName: "p", ChannelName: "stable", BundleName: "d", Replaces: "b"},
+		},
+	}
+	newFake := func(failOn string, code codes.Code) *clientfakes.FakeInterface {
+		fake := &clientfakes.FakeInterface{}
+		fake.GetChannelEntriesThatReplaceStub = func(_ context.Context, name string) (*client.ChannelEntryIterator, error) {
+			if name == failOn {
+				return client.NewChannelEntryIterator(&entryStream{err: status.Error(code, "failed")}), nil
+			}
+			entries, ok := replacedBy[name]
+			if !ok {
+				return client.NewChannelEntryIterator(&entryStream{err: fmt.Errorf("no channel entries found that replace %s", name)}), nil
+			}
+			return client.NewChannelEntryIterator(&entryStream{entries: entries}), nil
+		}
+		return fake
+	}
+
+	fake := newFake("", codes.OK)
+	entries, err := action.Query{Client: fake}.WalkReplacements(context.Background(), "a")
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "c", "b", "d"}, bundleNames(entries))
+	require.Equal(t, []string{"fast", "fast", "stable", "stable"}, channelNames(entries))
+	// Each bundle is only queried once.
+	require.Equal(t, 4, fake.GetChannelEntriesThatReplaceCallCount())
+
+	_, err = action.Query{Client: newFake("b", codes.Unavailable)}.WalkReplacements(context.Background(), "a")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `replace "b"`)
+}
+
+type entryStream struct {
+	entries []*api.ChannelEntry
+	err     error
+}
+
+func (s *entryStream) Recv() (*api.ChannelEntry, error) {
+	if len(s.entries) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	e := s.entries[0]
+	s.entries = s.entries[1:]
+	return e, nil
+}
+
+func bundleNames(entries []*api.ChannelEntry) []string {
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.GetBundleName())
+	}
+	return names
+}
+
+func channelNames(entries []*api.ChannelEntry) []string {
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.GetChannelName())
+	}
+	return names
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/operator-framework/operator-registry/pkg/containertools"
 	"github.com/operator-framework/operator-registry/pkg/image"
 	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+	"github.com/operator-framework/operator-registry/pkg/image/signature"
 	"github.com/operator-framework/operator-registry/pkg/lib/bundle"
 	"github.com/operator-framework/operator-registry/pkg/registry"
 	"github.com/operator-framework/operator-registry/pkg/sqlite"
@@ -28,6 +29,18 @@ import (
 type Render struct {
 	Refs     []string
 	Registry image.Registry
+
+	// CacheDir is the directory of an image cache shared with other invocations, used when Registry is unset.
+	// If empty, images are pulled into a temporary directory that is removed once rendered.
+	CacheDir string
+	// MaxCacheSize is the size in bytes the cache is pruned to after rendering, or zero for no limit.
+	MaxCacheSize int64
+
+	// Verifier verifies the signatures of images before they're pulled, if set.
+	Verifier signature.Verifier
+	// Platform is the platform of the images rendered from multi-platform images, such as linux/arm64,
+	// used when Registry is unset. The host platform is rendered if empty.
+	Platform string
 }
 
 func nullLogger() *logrus.Entry {
@@ -77,19 +90,27 @@ func (r Render) Run(ctx context.Context) (*declcfg.DeclarativeConfig, error) {
 }
 
 func (r Render) createRegistry() (*containerdregistry.Registry, error) {
-	cacheDir, err := os.MkdirTemp("", "render-registry-")
-	if err != nil {
-		return nil, fmt.Errorf("create tempdir: %v", err)
-	}
-
-	reg, err := containerdregistry.NewRegistry(
-		containerdregistry.WithCacheDir(cacheDir),
-
+	opts := []containerdregistry.RegistryOption{
 		// The containerd registry impl is somewhat verbose, even on the happy path,
 		// so discard all logger logs. Any important failures will be returned from
 		// registry methods and eventually logged as fatal errors.
 		containerdregistry.WithLog(nullLogger()),
-	)
+		containerdregistry.WithPlatform(r.Platform),
+	}
+	if r.CacheDir != "" {
+		opts = append(opts,
+			containerdregistry.WithSharedCacheDir(r.CacheDir),
+			containerdregistry.WithMaxCacheSize(r.MaxCacheSize),
+		)
+	} else {
+		cacheDir, err := os.MkdirTemp("", "render-registry-")
+		if err != nil {
+			return nil, fmt.Errorf("create tempdir: %v", err)
+		}
+		opts = append(opts, containerdregistry.WithCacheDir(cacheDir))
+	}
+
+	reg, err := containerdregistry.NewRegistry(opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -98,10 +119,19 @@ func (r Render) createRegistry() (*containerdregistry.Registry, error) {
 
 func (r Render) imageToDeclcfg(ctx context.Context, imageRef string) (*declcfg.DeclarativeConfig, error) {
 	ref := image.SimpleReference(imageRef)
-	if err := r.Registry.Pull(ctx, ref); err != nil {
+	// The verified image is the one pulled, but it's still rendered by the reference it was given.
+	var pull image.Reference = ref
+	if r.Verifier != nil {
+		verified, err := r.Verifier.Verify(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("verify signature: %v", err)
+		}
+		pull = verified
+	}
+	if err := r.Registry.Pull(ctx, pull); err != nil {
 		return nil, err
 	}
-	labels, err := r.Registry.Labels(ctx, ref)
+	labels, err := r.Registry.Labels(ctx, pull)
 	if err != nil {
 		return nil, err
 	}
@@ -110,7 +140,7 @@ func (r Render) imageToDeclcfg(ctx context.Context, imageRef string) (*declcfg.D
 		return nil, err
 	}
 	defer os.RemoveAll(tmpDir)
-	if err := r.Registry.Unpack(ctx, ref, tmpDir); err != nil {
+	if err := r.Registry.Unpack(ctx, pull, tmpDir); err != nil {
 		return nil, err
 	}
 
@@ -190,12 +220,34 @@ func sqliteToDeclcfg(ctx context.Context, dbFile string) (*declcfg.DeclarativeCo
 
 	cfg := declcfg.ConvertFromModel(m)
 
+	if err := applyPinnedImages(&cfg); err != nil {
+		return nil, err
+	}
 	if err := populateDBRelatedImages(ctx, &cfg, db); err != nil {
 		return nil, err
 	}
 	return &cfg, nil
 }
 
+// applyPinnedImages replaces the related images of bundles read from their CSVs with the images they were pinned to,
+// since pinning a database leaves the CSVs it stores unchanged.
+func applyPinnedImages(cfg *declcfg.DeclarativeConfig) error {
+	for i, b := range cfg.Bundles {
+		props, err := property.Parse(b.Properties)
+		if err != nil {
+			return fmt.Errorf("parse properties for bundle %q: %v", b.Name, err)
+		}
+		for _, pin := range props.PinnedImages {
+			for j, ri := range b.RelatedImages {
+				if ri.Image == pin.Tag {
+					cfg.Bundles[i].RelatedImages[j].Image = pin.Image
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func populateDBRelatedImages(ctx context.Context, cfg *declcfg.DeclarativeConfig, db *sql.DB) error {
 	rows, err := db.QueryContext(ctx, "SELECT image, operatorbundle_name FROM related_image")
 	if err != nil {
@@ -3,6 +3,7 @@ package action_test
 import (
 	"context"
 	"embed"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -354,6 +355,58 @@ func TestRenderDBFile(t *testing.T) {
 	assert.Equal(t, expectCfg, actualCfg)
 }
 
+// fakeVerifier verifies the images it has references by digest for.
+type fakeVerifier map[string]string
+
+func (v fakeVerifier) Verify(_ context.Context, ref image.Reference) (image.Reference, error) {
+	verified, ok := v[ref.String()]
+	if !ok {
+		return nil, fmt.Errorf("no valid signature found for %s", ref)
+	}
+	return image.SimpleReference(verified), nil
+}
+
+func TestRenderVerifier(t *testing.T) {
+	const (
+		tag      = "test.registry/foo-operator/foo-bundle:v0.2.0"
+		verified = "test.registry/foo-operator/foo-bundle@sha256:8a5d5ba1f8bd2b4fa6b1aa1d5e8a1c7e6f6b4c63c0cf1a2c8a1b4b9d6d2e3f40"
+	)
+	reg, err := newRegistry()
+	require.NoError(t, err)
+	expectCfg, err := action.Render{Refs: []string{tag}, Registry: reg}.Run(context.Background())
+	require.NoError(t, err)
+
+	// The verified image is the one pulled, and it's rendered by the reference it was given.
+	mock := reg.(*image.MockRegistry)
+	mock.RemoteImages[image.SimpleReference(verified)] = mock.RemoteImages[image.SimpleReference(tag)]
+	delete(mock.RemoteImages, image.SimpleReference(tag))
+	cfg, err := action.Render{
+		Refs:     []string{tag},
+		Registry: reg,
+		Verifier: fakeVerifier{tag: verified},
+	}.Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, expectCfg, cfg)
+
+	_, err = action.Render{
+		Refs:     []string{tag},
+		Registry: reg,
+		Verifier: fakeVerifier{},
+	}.Run(context.Background())
+	require.EqualError(t, err, fmt.Sprintf("render reference %q: verify signature: no valid signature found for %s", tag, tag))
+}
+
+func TestRenderPlatform(t *testing.T) {
+	// The platform selects the images pulled by the registry created for rendering
+	_, err := action.Render{
+		Refs:     []string{"test.registry/foo-operator/foo-bundle:v0.2.0"},
+		CacheDir: t.TempDir(),
+		Platform: "linux/amd64/v1/extra",
+	}.Run(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid platform "linux/amd64/v1/extra"`)
+}
+
 //go:embed testdata/foo-bundle-v0.1.0/manifests/*
 //go:embed testdata/foo-bundle-v0.1.0/metadata/*
 var bundleImageV1 embed.FS
@@ -9,6 +9,10 @@ import (
 const (
 	schemaPackage = "olm.package"
 	schemaBundle  = "olm.bundle"
+
+	// SchemaVersion identifies the schemas of the declarative configs loaded into models,
+	// and is reported as the schema version of catalogs served from them.
+	SchemaVersion = schemaPackage + "," + schemaBundle
 )
 
 type DeclarativeConfig struct {
@@ -28,12 +28,35 @@ func LoadFS(root fs.FS) (*DeclarativeConfig, error) {
 	}
 	cfg := &DeclarativeConfig{}
 
+	if err := WalkFS(root, func(path string, fileCfg *DeclarativeConfig) error {
+		cfg.Packages = append(cfg.Packages, fileCfg.Packages...)
+		cfg.Bundles = append(cfg.Bundles, fileCfg.Bundles...)
+		cfg.Others = append(cfg.Others, fileCfg.Others...)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to read declarative configs dir: %v", err)
+	}
+	return cfg, nil
+}
+
+// WalkFunc is called by WalkFS with the declarative config read from each file.
+// If it returns an error, the walk stops and WalkFS returns that error.
+type WalkFunc func(path string, cfg *DeclarativeConfig) error
+
+// WalkFS walks the filesystem from root like LoadFS, calling walkFn with the
+// declarative config of each file rather than merging them into one, so that
+// callers can tell which file each object comes from.
+func WalkFS(root fs.FS, walkFn WalkFunc) error {
+	if root == nil {
+		return fmt.Errorf("no declarative config filesystem provided")
+	}
+
 	matcher, err := ignore.NewMatcher(root, ".indexignore")
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if err := walkFiles(root, func(path string, r io.Reader) error {
+	return walkFiles(root, func(path string, r io.Reader) error {
 		if matcher.Match(path, false) {
 			return nil
 		}
@@ -44,15 +67,8 @@ func LoadFS(root fs.FS) (*DeclarativeConfig, error) {
 		if err := readBundleObjects(fileCfg.Bundles, root, path); err != nil {
 			return fmt.Errorf("read bundle objects: %v", err)
 		}
-		cfg.Packages = append(cfg.Packages, fileCfg.Packages...)
-		cfg.Bundles = append(cfg.Bundles, fileCfg.Bundles...)
-		cfg.Others = append(cfg.Others, fileCfg.Others...)
-
-		return nil
-	}); err != nil {
-		return nil, fmt.Errorf("failed to read declarative configs dir: %v", err)
-	}
-	return cfg, nil
+		return walkFn(path, fileCfg)
+	})
 }
 
 func readBundleObjects(bundles []Bundle, root fs.FS, path string) error {
@@ -2,6 +2,7 @@ package declcfg
 
 import (
 	"encoding/json"
+	"errors"
 	"io/fs"
 	"os"
 	"testing"
@@ -280,6 +281,27 @@ func TestLoadFS(t *testing.T) {
 	}
 }
 
+func TestWalkFS(t *testing.T) {
+	require.Error(t, WalkFS(nil, func(string, *DeclarativeConfig) error { return nil }))
+	require.Error(t, WalkFS(invalidFS, func(string, *DeclarativeConfig) error { return nil }))
+
+	bundles := map[string][]string{}
+	require.NoError(t, WalkFS(validFS, func(path string, cfg *DeclarativeConfig) error {
+		for _, b := range cfg.Bundles {
+			bundles[path] = append(bundles[path], b.Name)
+		}
+		return nil
+	}))
+	require.Equal(t, map[string][]string{
+		"cockroachdb.json":         {"cockroachdb.v2.0.9", "cockroachdb.v2.1.11", "cockroachdb.v2.1.1", "cockroachdb.v3.0.7", "cockroachdb.v5.0.3"},
+		"etcd.yaml":                {"etcdoperator-community.v0.6.1", "etcdoperator.v0.9.0", "etcdoperator.v0.9.2", "etcdoperator.v0.9.2-clusterwide", "etcdoperator.v0.9.4", "etcdoperator.v0.9.4-clusterwide"},
+		"unrecognized-schema.json": {""},
+	}, bundles)
+
+	stop := errors.New("stop")
+	require.Equal(t, stop, WalkFS(validFS, func(string, *DeclarativeConfig) error { return stop }))
+}
+
 var (
 	invalidBundle = &fstest.MapFile{
 		Data: []byte(`{"schema": "olm.bundle","relatedImages": {}}`),
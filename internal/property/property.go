@@ -64,6 +64,12 @@ type GVKRequired struct {
 type Skips string
 type SkipRange string
 
+// PinnedImage records the reference by tag of an image that a bundle now references by digest.
+type PinnedImage struct {
+	Image string `json:"image"`
+	Tag   string `json:"tag"`
+}
+
 type BundleObject struct {
 	File `json:",inline"`
 }
@@ -129,6 +135,7 @@ type Properties struct {
 	Skips            []Skips
 	SkipRanges       []SkipRange
 	BundleObjects    []BundleObject
+	PinnedImages     []PinnedImage
 
 	Others []Property
 }
@@ -142,6 +149,8 @@ const (
 	TypeSkips           = "olm.skips"
 	TypeSkipRange       = "olm.skipRange"
 	TypeBundleObject    = "olm.bundle.object"
+	TypePinnedImage     = "olm.pinnedImage"
+	TypeDeprecated      = "olm.deprecated"
 )
 
 func Parse(in []Property) (*Properties, error) {
@@ -196,6 +205,12 @@ func Parse(in []Property) (*Properties, error) {
 				return nil, ParseError{Idx: i, Typ: prop.Type, Err: err}
 			}
 			out.BundleObjects = append(out.BundleObjects, p)
+		case TypePinnedImage:
+			var p PinnedImage
+			if err := json.Unmarshal(prop.Value, &p); err != nil {
+				return nil, ParseError{Idx: i, Typ: prop.Type, Err: err}
+			}
+			out.PinnedImages = append(out.PinnedImages, p)
 		default:
 			var p json.RawMessage
 			if err := json.Unmarshal(prop.Value, &p); err != nil {
@@ -308,3 +323,6 @@ func MustBuildBundleObjectRef(ref string) Property {
 func MustBuildBundleObjectData(data []byte) Property {
 	return MustBuild(&BundleObject{File: File{data: data}})
 }
+func MustBuildPinnedImage(image, tag string) Property {
+	return MustBuild(&PinnedImage{Image: image, Tag: tag})
+}
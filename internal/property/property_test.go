@@ -288,6 +288,13 @@ func TestParse(t *testing.T) {
 			},
 			assertion: assert.Error,
 		},
+		{
+			name: "Error/InvalidPinnedImage",
+			input: []Property{
+				{Type: TypePinnedImage, Value: json.RawMessage(`{`)},
+			},
+			assertion: assert.Error,
+		},
 		{
 			name: "Error/InvalidOther",
 			input: []Property{
@@ -314,6 +321,7 @@ func TestParse(t *testing.T) {
 				MustBuildSkipRange("<0.2.0-0"),
 				MustBuildBundleObjectRef("testref1"),
 				MustBuildBundleObjectData([]byte("testdata2")),
+				MustBuildPinnedImage("quay.io/foo/bar@sha256:abcd", "quay.io/foo/bar:v1"),
 				{Type: "otherType1", Value: json.RawMessage(`{"v":"otherValue1"}`)},
 				{Type: "otherType2", Value: json.RawMessage(`["otherValue2"]`)},
 			},
@@ -350,6 +358,9 @@ func TestParse(t *testing.T) {
 					{File: File{ref: "testref1"}},
 					{File: File{data: []byte("testdata2")}},
 				},
+				PinnedImages: []PinnedImage{
+					{"quay.io/foo/bar@sha256:abcd", "quay.io/foo/bar:v1"},
+				},
 				Others: []Property{
 					{Type: "otherType1", Value: json.RawMessage(`{"v":"otherValue1"}`)},
 					{Type: "otherType2", Value: json.RawMessage(`["otherValue2"]`)},
@@ -492,6 +503,12 @@ func TestBuild(t *testing.T) {
 			assertion:        require.NoError,
 			expectedProperty: propPtr(MustBuildBundleObjectRef("test")),
 		},
+		{
+			name:             "Success/PinnedImage",
+			input:            &PinnedImage{"quay.io/foo/bar@sha256:abcd", "quay.io/foo/bar:v1"},
+			assertion:        require.NoError,
+			expectedProperty: propPtr(MustBuildPinnedImage("quay.io/foo/bar@sha256:abcd", "quay.io/foo/bar:v1")),
+		},
 		{
 			name:             "Success/Property",
 			input:            &Property{Type: "foo", Value: json.RawMessage(`"bar"`)},
@@ -18,6 +18,7 @@ func init() {
 		reflect.TypeOf(&skips):             TypeSkips,
 		reflect.TypeOf(&skipRange):         TypeSkipRange,
 		reflect.TypeOf(&BundleObject{}):    TypeBundleObject,
+		reflect.TypeOf(&PinnedImage{}):     TypePinnedImage,
 	}
 }
 
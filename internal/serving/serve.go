@@ -0,0 +1,208 @@
+package serving
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	health "github.com/operator-framework/operator-registry/pkg/api/grpc_health_v1"
+	v2 "github.com/operator-framework/operator-registry/pkg/api/v2"
+	"github.com/operator-framework/operator-registry/pkg/lib/dns"
+	"github.com/operator-framework/operator-registry/pkg/lib/graceful"
+	"github.com/operator-framework/operator-registry/pkg/lib/log"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+	"github.com/operator-framework/operator-registry/pkg/server"
+)
+
+// Options configure how the registry API is served. The fields up to Limits are set
+// by the flags added by AddFlags; the rest describe the catalog served, and are set by
+// the command that loads it.
+type Options struct {
+	Port           string
+	HTTPPort       string
+	MetricsPort    string
+	TLSCert        string
+	TLSKey         string
+	ClientCA       string
+	TerminationLog string
+	AccessLog      bool
+	ResponseCache  bool
+	Limits         server.Limits
+
+	// CatalogInfo is returned by GetCatalogInfo and recorded in metrics, if set.
+	CatalogInfo *api.CatalogInfo
+	// LoadDuration is how long the catalog took to load, recorded in metrics.
+	LoadDuration time.Duration
+	// Watcher streams the changes to the catalog to Watch callers. Watch is
+	// unimplemented if it's nil. It's closed when the server shuts down.
+	Watcher *server.ModelWatcher
+	// Reload, if set, runs in the background for as long as the catalog is served,
+	// and may replace it through the registry server. Metrics is nil if disabled.
+	Reload func(ctx context.Context, s *server.RegistryServer, metrics *server.Metrics)
+}
+
+// AddFlags adds the flags read by OptionsFromFlags to fs.
+func AddFlags(fs *pflag.FlagSet) {
+	fs.StringP("port", "p", "50051", "port number to serve on")
+	fs.String("http-port", "", "port number to serve the REST/JSON API on, disabled if empty")
+	fs.String("metrics-port", "", "port number to serve Prometheus metrics on at /metrics, disabled if empty")
+	fs.Bool("access-log", true, "log every request handled")
+	fs.Bool("response-cache", false, "precompute the responses to ListPackages, ListBundles and GetPackage when the catalog is loaded")
+	server.AddLimitFlags(fs)
+	fs.String("tls-cert", "", "path to a PEM encoded certificate to serve TLS with, reloaded when it changes")
+	fs.String("tls-key", "", "path to the PEM encoded private key of the TLS certificate, reloaded when it changes")
+	fs.String("client-ca", "", "path to a PEM encoded CA bundle used to verify client certificates, which are required if set")
+	fs.StringP("termination-log", "t", "/dev/termination-log", "path to a container termination log file")
+}
+
+// OptionsFromFlags returns the options set by the flags added by AddFlags.
+func OptionsFromFlags(fs *pflag.FlagSet) (Options, error) {
+	var (
+		o   Options
+		err error
+	)
+	for flag, value := range map[string]*string{
+		"port":            &o.Port,
+		"http-port":       &o.HTTPPort,
+		"metrics-port":    &o.MetricsPort,
+		"tls-cert":        &o.TLSCert,
+		"tls-key":         &o.TLSKey,
+		"client-ca":       &o.ClientCA,
+		"termination-log": &o.TerminationLog,
+	} {
+		if *value, err = fs.GetString(flag); err != nil {
+			return o, err
+		}
+	}
+	if o.AccessLog, err = fs.GetBool("access-log"); err != nil {
+		return o, err
+	}
+	if o.ResponseCache, err = fs.GetBool("response-cache"); err != nil {
+		return o, err
+	}
+	if o.Limits, err = server.LimitsFromFlags(fs); err != nil {
+		return o, err
+	}
+	return o, nil
+}
+
+// Prepare sets up the process before the catalog is loaded: errors are written to the
+// termination log, and a default nsswitch config is written if there is none.
+func Prepare(logger logrus.FieldLogger, opts Options) {
+	if err := log.AddDefaultWriterHooks(opts.TerminationLog); err != nil {
+		logger.WithError(err).Warn("unable to set termination log path")
+	}
+	if err := dns.EnsureNsswitch(); err != nil {
+		logger.WithError(err).Warn("unable to write default nsswitch config")
+	}
+}
+
+// Serve serves store over gRPC, and over HTTP and metrics if enabled by opts, until
+// the process is interrupted or ctx is done. Servers are then stopped gracefully.
+func Serve(ctx context.Context, logger logrus.FieldLogger, store registry.GRPCQuery, opts Options) error {
+	tlsConfig, metricsTLSConfig, err := TLSConfigs(opts.TLSCert, opts.TLSKey, opts.ClientCA)
+	if err != nil {
+		return err
+	}
+
+	var metrics *server.Metrics
+	if opts.MetricsPort != "" {
+		metrics = server.NewMetrics()
+		metrics.ObserveLoad(opts.CatalogInfo, opts.LoadDuration, nil)
+	}
+
+	var accessLogger logrus.FieldLogger
+	if opts.AccessLog {
+		accessLogger = logger
+	}
+	limiterOpts := []server.LimiterOption{server.WithRejectLogger(logger)}
+	if metrics != nil {
+		limiterOpts = append(limiterOpts, server.WithRejectObserver(metrics.ObserveRejected))
+	}
+	limiter, err := server.NewLimiter(opts.Limits, limiterOpts...)
+	if err != nil {
+		return err
+	}
+	interceptors := server.NewInterceptors(accessLogger, metrics, limiter)
+
+	var serverOpts []server.RegistryServerOption
+	if opts.CatalogInfo != nil {
+		serverOpts = append(serverOpts, server.WithCatalogInfo(opts.CatalogInfo))
+	}
+	if opts.Watcher != nil {
+		serverOpts = append(serverOpts, server.WithModelWatcher(opts.Watcher))
+	}
+	if opts.ResponseCache {
+		serverOpts = append(serverOpts, server.WithResponseCache(), server.WithLogger(logger))
+	}
+	registryServer := server.NewRegistryServer(store, serverOpts...)
+
+	lis, err := net.Listen("tcp", ":"+opts.Port)
+	if err != nil {
+		return fmt.Errorf("failed to listen on port %s: %v", opts.Port, err)
+	}
+	grpcOpts := interceptors.ServerOptions()
+	if tlsConfig != nil {
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	grpcServer := grpc.NewServer(grpcOpts...)
+	api.RegisterRegistryServer(grpcServer, registryServer)
+	v2.RegisterRegistryServer(grpcServer, server.NewRegistryV2Server(registryServer))
+	health.RegisterHealthServer(grpcServer, server.NewHealthServer())
+	reflection.Register(grpcServer)
+	logger.Info("serving registry")
+
+	var httpServers []*http.Server
+	stop := func() {
+		// Watch streams only end once the watcher is closed, which graceful stops wait for
+		if opts.Watcher != nil {
+			opts.Watcher.Close()
+		}
+		for _, httpServer := range httpServers {
+			httpServer.Close()
+		}
+		grpcServer.GracefulStop()
+	}
+	if opts.HTTPPort != "" {
+		httpServer, err := ServeHTTP(logger, opts.HTTPPort, tlsConfig, server.NewHTTPHandler(registryServer, server.WithHTTPInterceptors(interceptors)))
+		if err != nil {
+			stop()
+			return err
+		}
+		httpServers = append(httpServers, httpServer)
+		logger.WithField("http-port", opts.HTTPPort).Info("serving REST/JSON API")
+	}
+	if metrics != nil {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsServer, err := ServeHTTP(logger, opts.MetricsPort, metricsTLSConfig, mux)
+		if err != nil {
+			stop()
+			return err
+		}
+		httpServers = append(httpServers, metricsServer)
+		logger.WithField("metrics-port", opts.MetricsPort).Info("serving metrics")
+	}
+
+	if opts.Reload != nil {
+		reloadCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go opts.Reload(reloadCtx, registryServer, metrics)
+	}
+	return graceful.ShutdownContext(ctx, logger, func() error {
+		if err := grpcServer.Serve(lis); err != grpc.ErrServerStopped {
+			return err
+		}
+		// ctx was done before the server started
+		return nil
+	}, stop)
+}
@@ -0,0 +1,135 @@
+// Package serving holds the setup shared by the commands that serve the registry API.
+package serving
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/lib/certs"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+	"github.com/operator-framework/operator-registry/pkg/server"
+	"github.com/operator-framework/operator-registry/pkg/sqlite"
+)
+
+const (
+	// ServeModeSQL answers requests with queries against the db.
+	ServeModeSQL = "sql"
+	// ServeModeMemory loads the db into an in-memory model at startup and answers requests from it.
+	ServeModeMemory = "memory"
+)
+
+// TLSConfigs returns the TLS config for the registry API, presenting the key pair in
+// certFile and keyFile and requiring client certificates signed by clientCAFile if it's
+// set, along with the TLS config for metrics. Metrics are scraped by monitoring rather
+// than registry clients, so they're served with the same key pair but without requiring
+// client certificates. Both configs are nil if TLS is disabled.
+func TLSConfigs(certFile, keyFile, clientCAFile string) (*tls.Config, *tls.Config, error) {
+	apiConfig, err := certs.ServerTLSConfig(certFile, keyFile, clientCAFile)
+	if err != nil || apiConfig == nil {
+		return nil, nil, err
+	}
+	metricsConfig := &tls.Config{
+		MinVersion:     apiConfig.MinVersion,
+		GetCertificate: apiConfig.GetCertificate,
+	}
+	return apiConfig, metricsConfig, nil
+}
+
+// ServeHTTP serves handler on port in the background, over TLS if tlsConfig is set.
+func ServeHTTP(logger logrus.FieldLogger, port string, tlsConfig *tls.Config, handler http.Handler) (*http.Server, error) {
+	lis, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on port %s: %v", port, err)
+	}
+	httpServer := &http.Server{Handler: handler, TLSConfig: tlsConfig}
+	go func() {
+		serve := httpServer.Serve
+		if tlsConfig != nil {
+			// The key pair is provided by the TLS config
+			serve = func(lis net.Listener) error { return httpServer.ServeTLS(lis, "", "") }
+		}
+		if err := serve(lis); err != nil && err != http.ErrServerClosed {
+			logger.WithError(err).WithField("port", port).Error("http server failed")
+		}
+	}()
+	return httpServer, nil
+}
+
+// OpenDB opens the db read-only, without writing to disk. Unless skipMigrate is set,
+// dbs that aren't at the latest migration are migrated in memory, and dbs that fail to
+// migrate aren't served.
+func OpenDB(dbName string, skipMigrate bool) (*sql.DB, error) {
+	// fail rather than serve an empty db if it doesn't exist
+	if _, err := os.Stat(dbName); err != nil {
+		return nil, err
+	}
+	if skipMigrate {
+		return sqlite.OpenReadOnly(dbName)
+	}
+	db, err := sqlite.OpenReadOnlyMigrated(context.TODO(), dbName)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't migrate db, pass --skip-migrate to serve it as is: %v", err)
+	}
+	return db, nil
+}
+
+// CatalogInfo returns the catalog info of the db, loaded now.
+func CatalogInfo(dbName string, store *sqlite.SQLQuerier) (*api.CatalogInfo, error) {
+	digest, err := server.DigestFile(dbName)
+	if err != nil {
+		return nil, err
+	}
+	return server.NewSQLCatalogInfo(context.TODO(), server.SourceTypeSqlite, digest, time.Now(), store)
+}
+
+// LoadDB opens the db to be served in serveMode, returning the store to serve it from
+// along with its catalog info, which is nil if it couldn't be determined.
+func LoadDB(ctx context.Context, logger logrus.FieldLogger, dbName string, skipMigrate bool, serveMode string) (registry.GRPCQuery, *api.CatalogInfo, error) {
+	if serveMode != ServeModeSQL && serveMode != ServeModeMemory {
+		return nil, nil, fmt.Errorf("invalid --serve-mode value %q, expected (%s|%s)", serveMode, ServeModeSQL, ServeModeMemory)
+	}
+	db, err := OpenDB(dbName, skipMigrate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sqlStore := sqlite.NewSQLLiteQuerierFromDb(db)
+
+	// sanity check that the db is available
+	tables, err := sqlStore.ListTables(ctx)
+	if err != nil {
+		logger.WithError(err).Warnf("couldn't list tables in db")
+	}
+	if len(tables) == 0 {
+		logger.Warn("no tables found in db")
+	}
+
+	info, err := CatalogInfo(dbName, sqlStore)
+	if err != nil {
+		logger.WithError(err).Warn("couldn't determine catalog info")
+	}
+
+	if serveMode == ServeModeSQL {
+		return sqlStore, info, nil
+	}
+	store, err := sqlite.NewMemoryQuerier(ctx, sqlStore)
+	if err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("failed to load db into memory: %v", err)
+	}
+
+	// Nothing reads from the db once it has been loaded
+	if err := db.Close(); err != nil {
+		logger.WithError(err).Warn("couldn't close db")
+	}
+	return store, info, nil
+}
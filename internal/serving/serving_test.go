@@ -0,0 +1,146 @@
+package serving
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/internal/model"
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+	"github.com/operator-framework/operator-registry/pkg/server"
+)
+
+// writeSelfSigned writes a self-signed certificate and its key to dir, returning their paths.
+func writeSelfSigned(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
+
+func TestTLSConfigs(t *testing.T) {
+	certFile, keyFile := writeSelfSigned(t, t.TempDir())
+
+	t.Run("Disabled", func(t *testing.T) {
+		apiConfig, metricsConfig, err := TLSConfigs("", "", "")
+		require.NoError(t, err)
+		require.Nil(t, apiConfig)
+		require.Nil(t, metricsConfig)
+	})
+
+	t.Run("InvalidFlags", func(t *testing.T) {
+		_, _, err := TLSConfigs(certFile, "", "")
+		require.Error(t, err)
+	})
+
+	t.Run("MetricsWithoutClientCertificates", func(t *testing.T) {
+		apiConfig, metricsConfig, err := TLSConfigs(certFile, keyFile, certFile)
+		require.NoError(t, err)
+		require.Equal(t, tls.RequireAnyClientCert, apiConfig.ClientAuth)
+		require.NotNil(t, apiConfig.VerifyPeerCertificate)
+
+		require.Equal(t, tls.NoClientCert, metricsConfig.ClientAuth)
+		require.Nil(t, metricsConfig.VerifyPeerCertificate)
+		require.Equal(t, apiConfig.MinVersion, metricsConfig.MinVersion)
+		cert, err := metricsConfig.GetCertificate(&tls.ClientHelloInfo{})
+		require.NoError(t, err)
+		require.NotNil(t, cert)
+	})
+}
+
+func TestOptionsFromFlags(t *testing.T) {
+	fs := pflag.NewFlagSet("serve", pflag.ContinueOnError)
+	AddFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--port=50052", "--http-port=8080", "--access-log=false", "--max-in-flight=4", "--tls-cert=tls.crt"}))
+
+	opts, err := OptionsFromFlags(fs)
+	require.NoError(t, err)
+	require.Equal(t, "50052", opts.Port)
+	require.Equal(t, "8080", opts.HTTPPort)
+	require.Empty(t, opts.MetricsPort)
+	require.Equal(t, "tls.crt", opts.TLSCert)
+	require.Equal(t, "/dev/termination-log", opts.TerminationLog)
+	require.False(t, opts.AccessLog)
+	require.False(t, opts.ResponseCache)
+	require.Equal(t, 4, opts.Limits.MaxInFlight)
+}
+
+func TestServeStopsWithContext(t *testing.T) {
+	watcher, err := server.NewModelWatcher(model.Model{})
+	require.NoError(t, err)
+	reloading := make(chan struct{})
+	opts := Options{
+		Port:     "0",
+		HTTPPort: "0",
+		Watcher:  watcher,
+		Reload: func(ctx context.Context, _ *server.RegistryServer, _ *server.Metrics) {
+			close(reloading)
+			<-ctx.Done()
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- Serve(ctx, logrus.NewEntry(logrus.New()), registry.NewEmptyQuerier(), opts)
+	}()
+	<-reloading
+	cancel()
+
+	select {
+	case err := <-errs:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("serve didn't stop")
+	}
+	// Watch returns right away once the watcher is closed
+	require.NoError(t, watcher.Watch(context.Background(), "", func(*api.WatchEvent) error { return nil }))
+}
+
+func TestOpenDB(t *testing.T) {
+	dbName := filepath.Join(t.TempDir(), "index.db")
+
+	_, err := OpenDB(dbName, false)
+	require.True(t, os.IsNotExist(err))
+	_, err = OpenDB(dbName, true)
+	require.True(t, os.IsNotExist(err))
+
+	// Dbs that can't be migrated are only served when migrations are skipped
+	require.NoError(t, ioutil.WriteFile(dbName, []byte("not a db"), 0600))
+	_, err = OpenDB(dbName, false)
+	require.Error(t, err)
+	db, err := OpenDB(dbName, true)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+}
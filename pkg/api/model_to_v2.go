@@ -0,0 +1,178 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/operator-framework/operator-registry/internal/model"
+	"github.com/operator-framework/operator-registry/internal/property"
+	v2 "github.com/operator-framework/operator-registry/pkg/api/v2"
+)
+
+// ConvertModelPackageToV2Package converts a package and its channels to the registry.v2 API.
+// Channels are sorted by name.
+func ConvertModelPackageToV2Package(pkg model.Package) (*v2.Package, error) {
+	out := &v2.Package{
+		Name:        pkg.Name,
+		Description: pkg.Description,
+	}
+	if pkg.Icon != nil {
+		out.Icon = &v2.Icon{Data: pkg.Icon.Data, MediaType: pkg.Icon.MediaType}
+	}
+	if pkg.DefaultChannel != nil {
+		out.DefaultChannel = pkg.DefaultChannel.Name
+	}
+	for _, ch := range pkg.Channels {
+		channel, err := ConvertModelChannelToV2Channel(*ch)
+		if err != nil {
+			return nil, err
+		}
+		out.Channels = append(out.Channels, channel)
+	}
+	sort.Slice(out.Channels, func(i, j int) bool { return out.Channels[i].Name < out.Channels[j].Name })
+	return out, nil
+}
+
+// ConvertModelChannelToV2Channel converts a channel to the registry.v2 API. Its entries
+// are sorted by bundle name.
+func ConvertModelChannelToV2Channel(ch model.Channel) (*v2.Channel, error) {
+	head, err := ch.Head()
+	if err != nil {
+		return nil, fmt.Errorf("channel %q has invalid head: %v", ch.Name, err)
+	}
+	out := &v2.Channel{
+		Name: ch.Name,
+		Head: head.Name,
+	}
+	if ch.Package != nil {
+		out.Package = ch.Package.Name
+	}
+	for _, b := range ch.Bundles {
+		entry, err := convertModelBundleToV2ChannelEntry(*b, ch.Name)
+		if err != nil {
+			return nil, err
+		}
+		out.Entries = append(out.Entries, entry)
+	}
+	sort.Slice(out.Entries, func(i, j int) bool { return out.Entries[i].BundleName < out.Entries[j].BundleName })
+	return out, nil
+}
+
+// ConvertModelBundlesToV2Bundle converts a bundle to the registry.v2 API. The model holds
+// a copy of a bundle for every channel it is a member of; bundles must be all of those
+// copies, and are merged into a single bundle with one channel entry per copy.
+func ConvertModelBundlesToV2Bundle(bundles []*model.Bundle) (*v2.Bundle, error) {
+	if len(bundles) == 0 {
+		return nil, fmt.Errorf("no bundles to convert")
+	}
+	bundles = append([]*model.Bundle(nil), bundles...)
+	sort.Slice(bundles, func(i, j int) bool { return bundles[i].Channel.Name < bundles[j].Channel.Name })
+
+	first := bundles[0]
+	props, err := parseProperties(first.Properties)
+	if err != nil {
+		return nil, fmt.Errorf("parse properties of bundle %q: %v", first.Name, err)
+	}
+	out := &v2.Bundle{
+		Package: first.Package.Name,
+		Name:    first.Name,
+		Image:   first.Image,
+		Version: props.Packages[0].Version,
+		CsvJson: first.CsvJSON,
+		Objects: first.Objects,
+	}
+	for _, ri := range first.RelatedImages {
+		out.RelatedImages = append(out.RelatedImages, &v2.RelatedImage{Name: ri.Name, Image: ri.Image})
+	}
+
+	for _, b := range bundles {
+		if b.Name != first.Name || b.Package.Name != first.Package.Name {
+			return nil, fmt.Errorf("cannot merge bundle %q of package %q with bundle %q of package %q", b.Name, b.Package.Name, first.Name, first.Package.Name)
+		}
+		entry, err := convertModelBundleToV2ChannelEntry(*b, b.Channel.Name)
+		if err != nil {
+			return nil, err
+		}
+		out.Channels = append(out.Channels, entry)
+	}
+
+	// Copies of a bundle normally have the same properties, but collect those of all copies
+	// so that none are lost if they don't.
+	seen := map[string]struct{}{}
+	for _, b := range bundles {
+		for i, p := range b.Properties {
+			key := p.Type + "\x00" + string(p.Value)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			if p.Type == property.TypeDeprecated {
+				out.Deprecated = true
+			}
+			prop, err := convertModelPropertyToV2Property(p)
+			if err != nil {
+				return nil, property.ParseError{Idx: i, Typ: p.Type, Err: err}
+			}
+			if prop != nil {
+				out.Properties = append(out.Properties, prop)
+			}
+		}
+	}
+	return out, nil
+}
+
+// convertModelBundleToV2ChannelEntry returns the entry of a bundle in the named channel.
+func convertModelBundleToV2ChannelEntry(b model.Bundle, channel string) (*v2.ChannelEntry, error) {
+	props, err := parseProperties(b.Properties)
+	if err != nil {
+		return nil, fmt.Errorf("parse properties of bundle %q: %v", b.Name, err)
+	}
+	entry := &v2.ChannelEntry{
+		ChannelName: channel,
+		BundleName:  b.Name,
+		Replaces:    b.Replaces,
+		Skips:       b.Skips,
+	}
+	if len(props.SkipRanges) > 0 {
+		entry.SkipRange = string(props.SkipRanges[0])
+	}
+	return entry, nil
+}
+
+// convertModelPropertyToV2Property returns the typed form of a property, or nil if the
+// property is represented by a field of the bundle instead.
+func convertModelPropertyToV2Property(p property.Property) (*v2.Property, error) {
+	out := &v2.Property{Type: p.Type}
+	switch p.Type {
+	case property.TypeChannel, property.TypeSkips, property.TypeSkipRange, property.TypeBundleObject, property.TypeDeprecated:
+		return nil, nil
+	case property.TypePackage:
+		var v property.Package
+		if err := json.Unmarshal(p.Value, &v); err != nil {
+			return nil, err
+		}
+		out.Value = &v2.Property_Package{Package: &v2.PackageProperty{PackageName: v.PackageName, Version: v.Version}}
+	case property.TypePackageRequired:
+		var v property.PackageRequired
+		if err := json.Unmarshal(p.Value, &v); err != nil {
+			return nil, err
+		}
+		out.Value = &v2.Property_PackageRequired{PackageRequired: &v2.PackageRequiredProperty{PackageName: v.PackageName, VersionRange: v.VersionRange}}
+	case property.TypeGVK:
+		var v property.GVK
+		if err := json.Unmarshal(p.Value, &v); err != nil {
+			return nil, err
+		}
+		out.Value = &v2.Property_Gvk{Gvk: &v2.GroupVersionKind{Group: v.Group, Version: v.Version, Kind: v.Kind}}
+	case property.TypeGVKRequired:
+		var v property.GVKRequired
+		if err := json.Unmarshal(p.Value, &v); err != nil {
+			return nil, err
+		}
+		out.Value = &v2.Property_GvkRequired{GvkRequired: &v2.GroupVersionKind{Group: v.Group, Version: v.Version, Kind: v.Kind}}
+	default:
+		out.Value = &v2.Property_Json{Json: string(p.Value)}
+	}
+	return out, nil
+}
@@ -28,6 +28,8 @@ type RegistryClient interface {
 	GetLatestChannelEntriesThatProvide(ctx context.Context, in *GetLatestProvidersRequest, opts ...grpc.CallOption) (Registry_GetLatestChannelEntriesThatProvideClient, error)
 	GetDefaultBundleThatProvides(ctx context.Context, in *GetDefaultProviderRequest, opts ...grpc.CallOption) (*Bundle, error)
 	ListBundles(ctx context.Context, in *ListBundlesRequest, opts ...grpc.CallOption) (Registry_ListBundlesClient, error)
+	GetCatalogInfo(ctx context.Context, in *GetCatalogInfoRequest, opts ...grpc.CallOption) (*CatalogInfo, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Registry_WatchClient, error)
 }
 
 type registryClient struct {
@@ -243,6 +245,47 @@ func (x *registryListBundlesClient) Recv() (*Bundle, error) {
 	return m, nil
 }
 
+func (c *registryClient) GetCatalogInfo(ctx context.Context, in *GetCatalogInfoRequest, opts ...grpc.CallOption) (*CatalogInfo, error) {
+	out := new(CatalogInfo)
+	err := c.cc.Invoke(ctx, "/api.Registry/GetCatalogInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Registry_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Registry_serviceDesc.Streams[5], "/api.Registry/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &registryWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Registry_WatchClient interface {
+	Recv() (*WatchEvent, error)
+	grpc.ClientStream
+}
+
+type registryWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *registryWatchClient) Recv() (*WatchEvent, error) {
+	m := new(WatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // RegistryServer is the server API for Registry service.
 // All implementations must embed UnimplementedRegistryServer
 // for forward compatibility
@@ -257,6 +300,8 @@ type RegistryServer interface {
 	GetLatestChannelEntriesThatProvide(*GetLatestProvidersRequest, Registry_GetLatestChannelEntriesThatProvideServer) error
 	GetDefaultBundleThatProvides(context.Context, *GetDefaultProviderRequest) (*Bundle, error)
 	ListBundles(*ListBundlesRequest, Registry_ListBundlesServer) error
+	GetCatalogInfo(context.Context, *GetCatalogInfoRequest) (*CatalogInfo, error)
+	Watch(*WatchRequest, Registry_WatchServer) error
 	mustEmbedUnimplementedRegistryServer()
 }
 
@@ -294,6 +339,12 @@ func (*UnimplementedRegistryServer) GetDefaultBundleThatProvides(context.Context
 func (*UnimplementedRegistryServer) ListBundles(*ListBundlesRequest, Registry_ListBundlesServer) error {
 	return status.Errorf(codes.Unimplemented, "method ListBundles not implemented")
 }
+func (*UnimplementedRegistryServer) GetCatalogInfo(context.Context, *GetCatalogInfoRequest) (*CatalogInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCatalogInfo not implemented")
+}
+func (*UnimplementedRegistryServer) Watch(*WatchRequest, Registry_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (*UnimplementedRegistryServer) mustEmbedUnimplementedRegistryServer() {}
 
 func RegisterRegistryServer(s *grpc.Server, srv RegistryServer) {
@@ -495,6 +546,45 @@ func (x *registryListBundlesServer) Send(m *Bundle) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Registry_GetCatalogInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCatalogInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).GetCatalogInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Registry/GetCatalogInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).GetCatalogInfo(ctx, req.(*GetCatalogInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RegistryServer).Watch(m, &registryWatchServer{stream})
+}
+
+type Registry_WatchServer interface {
+	Send(*WatchEvent) error
+	grpc.ServerStream
+}
+
+type registryWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *registryWatchServer) Send(m *WatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Registry_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "api.Registry",
 	HandlerType: (*RegistryServer)(nil),
@@ -519,6 +609,10 @@ var _Registry_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetDefaultBundleThatProvides",
 			Handler:    _Registry_GetDefaultBundleThatProvides_Handler,
 		},
+		{
+			MethodName: "GetCatalogInfo",
+			Handler:    _Registry_GetCatalogInfo_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -546,6 +640,11 @@ var _Registry_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Registry_ListBundles_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Watch",
+			Handler:       _Registry_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "registry.proto",
 }
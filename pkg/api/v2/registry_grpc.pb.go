@@ -0,0 +1,290 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v2
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// RegistryClient is the client API for Registry service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RegistryClient interface {
+	ListPackages(ctx context.Context, in *ListPackagesRequest, opts ...grpc.CallOption) (Registry_ListPackagesClient, error)
+	GetPackage(ctx context.Context, in *GetPackageRequest, opts ...grpc.CallOption) (*Package, error)
+	GetChannel(ctx context.Context, in *GetChannelRequest, opts ...grpc.CallOption) (*Channel, error)
+	ListBundles(ctx context.Context, in *ListBundlesRequest, opts ...grpc.CallOption) (Registry_ListBundlesClient, error)
+	GetBundle(ctx context.Context, in *GetBundleRequest, opts ...grpc.CallOption) (*Bundle, error)
+}
+
+type registryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRegistryClient(cc grpc.ClientConnInterface) RegistryClient {
+	return &registryClient{cc}
+}
+
+func (c *registryClient) ListPackages(ctx context.Context, in *ListPackagesRequest, opts ...grpc.CallOption) (Registry_ListPackagesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Registry_serviceDesc.Streams[0], "/registry.v2.Registry/ListPackages", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &registryListPackagesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Registry_ListPackagesClient interface {
+	Recv() (*Package, error)
+	grpc.ClientStream
+}
+
+type registryListPackagesClient struct {
+	grpc.ClientStream
+}
+
+func (x *registryListPackagesClient) Recv() (*Package, error) {
+	m := new(Package)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *registryClient) GetPackage(ctx context.Context, in *GetPackageRequest, opts ...grpc.CallOption) (*Package, error) {
+	out := new(Package)
+	err := c.cc.Invoke(ctx, "/registry.v2.Registry/GetPackage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) GetChannel(ctx context.Context, in *GetChannelRequest, opts ...grpc.CallOption) (*Channel, error) {
+	out := new(Channel)
+	err := c.cc.Invoke(ctx, "/registry.v2.Registry/GetChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) ListBundles(ctx context.Context, in *ListBundlesRequest, opts ...grpc.CallOption) (Registry_ListBundlesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Registry_serviceDesc.Streams[1], "/registry.v2.Registry/ListBundles", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &registryListBundlesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Registry_ListBundlesClient interface {
+	Recv() (*Bundle, error)
+	grpc.ClientStream
+}
+
+type registryListBundlesClient struct {
+	grpc.ClientStream
+}
+
+func (x *registryListBundlesClient) Recv() (*Bundle, error) {
+	m := new(Bundle)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *registryClient) GetBundle(ctx context.Context, in *GetBundleRequest, opts ...grpc.CallOption) (*Bundle, error) {
+	out := new(Bundle)
+	err := c.cc.Invoke(ctx, "/registry.v2.Registry/GetBundle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RegistryServer is the server API for Registry service.
+// All implementations must embed UnimplementedRegistryServer
+// for forward compatibility
+type RegistryServer interface {
+	ListPackages(*ListPackagesRequest, Registry_ListPackagesServer) error
+	GetPackage(context.Context, *GetPackageRequest) (*Package, error)
+	GetChannel(context.Context, *GetChannelRequest) (*Channel, error)
+	ListBundles(*ListBundlesRequest, Registry_ListBundlesServer) error
+	GetBundle(context.Context, *GetBundleRequest) (*Bundle, error)
+	mustEmbedUnimplementedRegistryServer()
+}
+
+// UnimplementedRegistryServer must be embedded to have forward compatible implementations.
+type UnimplementedRegistryServer struct {
+}
+
+func (*UnimplementedRegistryServer) ListPackages(*ListPackagesRequest, Registry_ListPackagesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListPackages not implemented")
+}
+func (*UnimplementedRegistryServer) GetPackage(context.Context, *GetPackageRequest) (*Package, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPackage not implemented")
+}
+func (*UnimplementedRegistryServer) GetChannel(context.Context, *GetChannelRequest) (*Channel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChannel not implemented")
+}
+func (*UnimplementedRegistryServer) ListBundles(*ListBundlesRequest, Registry_ListBundlesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListBundles not implemented")
+}
+func (*UnimplementedRegistryServer) GetBundle(context.Context, *GetBundleRequest) (*Bundle, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBundle not implemented")
+}
+func (*UnimplementedRegistryServer) mustEmbedUnimplementedRegistryServer() {}
+
+func RegisterRegistryServer(s *grpc.Server, srv RegistryServer) {
+	s.RegisterService(&_Registry_serviceDesc, srv)
+}
+
+func _Registry_ListPackages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListPackagesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RegistryServer).ListPackages(m, &registryListPackagesServer{stream})
+}
+
+type Registry_ListPackagesServer interface {
+	Send(*Package) error
+	grpc.ServerStream
+}
+
+type registryListPackagesServer struct {
+	grpc.ServerStream
+}
+
+func (x *registryListPackagesServer) Send(m *Package) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Registry_GetPackage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPackageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).GetPackage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.v2.Registry/GetPackage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).GetPackage(ctx, req.(*GetPackageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_GetChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).GetChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.v2.Registry/GetChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).GetChannel(ctx, req.(*GetChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_ListBundles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListBundlesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RegistryServer).ListBundles(m, &registryListBundlesServer{stream})
+}
+
+type Registry_ListBundlesServer interface {
+	Send(*Bundle) error
+	grpc.ServerStream
+}
+
+type registryListBundlesServer struct {
+	grpc.ServerStream
+}
+
+func (x *registryListBundlesServer) Send(m *Bundle) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Registry_GetBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBundleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).GetBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.v2.Registry/GetBundle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).GetBundle(ctx, req.(*GetBundleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Registry_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "registry.v2.Registry",
+	HandlerType: (*RegistryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPackage",
+			Handler:    _Registry_GetPackage_Handler,
+		},
+		{
+			MethodName: "GetChannel",
+			Handler:    _Registry_GetChannel_Handler,
+		},
+		{
+			MethodName: "GetBundle",
+			Handler:    _Registry_GetBundle_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListPackages",
+			Handler:       _Registry_ListPackages_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListBundles",
+			Handler:       _Registry_ListBundles_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "v2/registry.proto",
+}
@@ -12,13 +12,23 @@ import (
 	"github.com/operator-framework/operator-registry/pkg/api/grpc_health_v1"
 )
 
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -o clientfakes/fake_interface.go . Interface
+
+// Interface is a client for the registry API. Stream results are returned as iterators,
+// which must be drained or closed to release the stream.
 type Interface interface {
 	GetBundle(ctx context.Context, packageName, channelName, csvName string) (*api.Bundle, error)
 	GetBundleInPackageChannel(ctx context.Context, packageName, channelName string) (*api.Bundle, error)
 	GetReplacementBundleInPackageChannel(ctx context.Context, currentName, packageName, channelName string) (*api.Bundle, error)
 	GetBundleThatProvides(ctx context.Context, group, version, kind string) (*api.Bundle, error)
 	ListBundles(ctx context.Context) (*BundleIterator, error)
+	ListPackages(ctx context.Context) (*PackageNameIterator, error)
 	GetPackage(ctx context.Context, packageName string) (*api.Package, error)
+	GetChannelEntriesThatReplace(ctx context.Context, csvName string) (*ChannelEntryIterator, error)
+	GetChannelEntriesThatProvide(ctx context.Context, group, version, kind string) (*ChannelEntryIterator, error)
+	GetLatestChannelEntriesThatProvide(ctx context.Context, group, version, kind string) (*ChannelEntryIterator, error)
+	GetCatalogInfo(ctx context.Context) (*api.CatalogInfo, error)
+	Watch(ctx context.Context, resumeToken string) (*WatchEventIterator, error)
 	HealthCheck(ctx context.Context, reconnectTimeout time.Duration) (bool, error)
 	Close() error
 }
@@ -27,15 +37,32 @@ type Client struct {
 	Registry api.RegistryClient
 	Health   grpc_health_v1.HealthClient
 	Conn     *grpc.ClientConn
+
+	// closer releases resources other than Conn, such as those held by local clients.
+	closer func() error
 }
 
 var _ Interface = &Client{}
 
+// streamCloser ends the stream an iterator reads from.
+type streamCloser struct {
+	cancel context.CancelFunc
+}
+
+// Close ends the stream, releasing its resources. Streams are closed once they're
+// drained or fail, so only iterators that are abandoned before then need to be closed.
+func (c streamCloser) Close() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
 type BundleStream interface {
 	Recv() (*api.Bundle, error)
 }
 
 type BundleIterator struct {
+	streamCloser
 	stream BundleStream
 	error  error
 }
@@ -50,10 +77,12 @@ func (it *BundleIterator) Next() *api.Bundle {
 	}
 	next, err := it.stream.Recv()
 	if err == io.EOF {
+		it.Close()
 		return nil
 	}
 	if err != nil {
 		it.error = err
+		it.Close()
 	}
 	return next
 }
@@ -62,6 +91,110 @@ func (it *BundleIterator) Error() error {
 	return it.error
 }
 
+type PackageNameStream interface {
+	Recv() (*api.PackageName, error)
+}
+
+type PackageNameIterator struct {
+	streamCloser
+	stream PackageNameStream
+	error  error
+}
+
+func NewPackageNameIterator(stream PackageNameStream) *PackageNameIterator {
+	return &PackageNameIterator{stream: stream}
+}
+
+func (it *PackageNameIterator) Next() *api.PackageName {
+	if it.error != nil {
+		return nil
+	}
+	next, err := it.stream.Recv()
+	if err == io.EOF {
+		it.Close()
+		return nil
+	}
+	if err != nil {
+		it.error = err
+		it.Close()
+	}
+	return next
+}
+
+func (it *PackageNameIterator) Error() error {
+	return it.error
+}
+
+type ChannelEntryStream interface {
+	Recv() (*api.ChannelEntry, error)
+}
+
+type ChannelEntryIterator struct {
+	streamCloser
+	stream ChannelEntryStream
+	error  error
+}
+
+func NewChannelEntryIterator(stream ChannelEntryStream) *ChannelEntryIterator {
+	return &ChannelEntryIterator{stream: stream}
+}
+
+func (it *ChannelEntryIterator) Next() *api.ChannelEntry {
+	if it.error != nil {
+		return nil
+	}
+	next, err := it.stream.Recv()
+	if err == io.EOF {
+		it.Close()
+		return nil
+	}
+	if err != nil {
+		it.error = err
+		it.Close()
+	}
+	return next
+}
+
+func (it *ChannelEntryIterator) Error() error {
+	return it.error
+}
+
+type WatchEventStream interface {
+	Recv() (*api.WatchEvent, error)
+}
+
+// WatchEventIterator iterates over catalog change events. Next blocks until the next
+// event is received, and only returns nil once the watch has ended.
+type WatchEventIterator struct {
+	streamCloser
+	stream WatchEventStream
+	error  error
+}
+
+func NewWatchEventIterator(stream WatchEventStream) *WatchEventIterator {
+	return &WatchEventIterator{stream: stream}
+}
+
+func (it *WatchEventIterator) Next() *api.WatchEvent {
+	if it.error != nil {
+		return nil
+	}
+	next, err := it.stream.Recv()
+	if err == io.EOF {
+		it.Close()
+		return nil
+	}
+	if err != nil {
+		it.error = err
+		it.Close()
+	}
+	return next
+}
+
+func (it *WatchEventIterator) Error() error {
+	return it.error
+}
+
 func (c *Client) GetBundle(ctx context.Context, packageName, channelName, csvName string) (*api.Bundle, error) {
 	return c.Registry.GetBundle(ctx, &api.GetBundleRequest{PkgName: packageName, ChannelName: channelName, CsvName: csvName})
 }
@@ -79,18 +212,91 @@ func (c *Client) GetBundleThatProvides(ctx context.Context, group, version, kind
 }
 
 func (c *Client) ListBundles(ctx context.Context) (*BundleIterator, error) {
+	ctx, cancel := context.WithCancel(ctx)
 	stream, err := c.Registry.ListBundles(ctx, &api.ListBundlesRequest{})
 	if err != nil {
+		cancel()
+		return nil, err
+	}
+	it := NewBundleIterator(stream)
+	it.cancel = cancel
+	return it, nil
+}
+
+func (c *Client) ListPackages(ctx context.Context) (*PackageNameIterator, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.Registry.ListPackages(ctx, &api.ListPackageRequest{})
+	if err != nil {
+		cancel()
 		return nil, err
 	}
-	return NewBundleIterator(stream), nil
+	it := NewPackageNameIterator(stream)
+	it.cancel = cancel
+	return it, nil
 }
 
 func (c *Client) GetPackage(ctx context.Context, packageName string) (*api.Package, error) {
 	return c.Registry.GetPackage(ctx, &api.GetPackageRequest{Name: packageName})
 }
 
+func (c *Client) GetChannelEntriesThatReplace(ctx context.Context, csvName string) (*ChannelEntryIterator, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.Registry.GetChannelEntriesThatReplace(ctx, &api.GetAllReplacementsRequest{CsvName: csvName})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	it := NewChannelEntryIterator(stream)
+	it.cancel = cancel
+	return it, nil
+}
+
+func (c *Client) GetChannelEntriesThatProvide(ctx context.Context, group, version, kind string) (*ChannelEntryIterator, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.Registry.GetChannelEntriesThatProvide(ctx, &api.GetAllProvidersRequest{Group: group, Version: version, Kind: kind})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	it := NewChannelEntryIterator(stream)
+	it.cancel = cancel
+	return it, nil
+}
+
+func (c *Client) GetLatestChannelEntriesThatProvide(ctx context.Context, group, version, kind string) (*ChannelEntryIterator, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.Registry.GetLatestChannelEntriesThatProvide(ctx, &api.GetLatestProvidersRequest{Group: group, Version: version, Kind: kind})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	it := NewChannelEntryIterator(stream)
+	it.cancel = cancel
+	return it, nil
+}
+
+func (c *Client) GetCatalogInfo(ctx context.Context) (*api.CatalogInfo, error) {
+	return c.Registry.GetCatalogInfo(ctx, &api.GetCatalogInfoRequest{})
+}
+
+func (c *Client) Watch(ctx context.Context, resumeToken string) (*WatchEventIterator, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.Registry.Watch(ctx, &api.WatchRequest{ResumeToken: resumeToken})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	it := NewWatchEventIterator(stream)
+	it.cancel = cancel
+	return it, nil
+}
+
 func (c *Client) Close() error {
+	if c.closer != nil {
+		if err := c.closer(); err != nil {
+			return err
+		}
+	}
 	if c.Conn == nil {
 		return nil
 	}
@@ -100,6 +306,9 @@ func (c *Client) Close() error {
 func (c *Client) HealthCheck(ctx context.Context, reconnectTimeout time.Duration) (bool, error) {
 	res, err := c.Health.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "Registry"})
 	if err != nil {
+		if c.Conn == nil {
+			return false, err
+		}
 		if c.Conn.GetState() == connectivity.TransientFailure {
 			ctx, cancel := context.WithTimeout(ctx, reconnectTimeout)
 			defer cancel()
@@ -115,14 +324,35 @@ func (c *Client) HealthCheck(ctx context.Context, reconnectTimeout time.Duration
 	return true, nil
 }
 
-func NewClient(address string) (*Client, error) {
-	conn, err := grpc.Dial(address, grpc.WithInsecure())
+// NewClient returns a client for the registry API served at address. By default, it
+// connects without TLS and doesn't retry failed calls.
+func NewClient(address string, opts ...ClientOption) (*Client, error) {
+	config := &clientConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	dialOpts, err := config.dialOptions()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.Dial(address, dialOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return NewClientFromConn(conn), nil
 }
 
+// NewClientFromServers returns a client calling registry and health directly rather than
+// over a connection. Closing the client calls closer, if it's set.
+func NewClientFromServers(registry api.RegistryClient, health grpc_health_v1.HealthClient, closer func() error) *Client {
+	return &Client{
+		Registry: registry,
+		Health:   health,
+		closer:   closer,
+	}
+}
+
 func NewClientFromConn(conn *grpc.ClientConn) *Client {
 	return &Client{
 		Registry: api.NewRegistryClient(conn),
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
 
 	"github.com/operator-framework/operator-registry/pkg/api"
@@ -14,10 +15,12 @@ import (
 )
 
 type RegistryClientStub struct {
-	ListBundlesClient api.Registry_ListBundlesClient
-	PackageName       string
-	Package           *api.Package
-	Error             error
+	ListBundlesClient  api.Registry_ListBundlesClient
+	ChannelEntryClient *ChannelEntryReceiverStub
+	PackageName        string
+	Package            *api.Package
+	ProvidersRequest   *api.GetAllProvidersRequest
+	Error              error
 }
 
 func (s *RegistryClientStub) ListPackages(ctx context.Context, in *api.ListPackageRequest, opts ...grpc.CallOption) (api.Registry_ListPackagesClient, error) {
@@ -46,7 +49,8 @@ func (s *RegistryClientStub) GetBundleThatReplaces(ctx context.Context, in *api.
 }
 
 func (s *RegistryClientStub) GetChannelEntriesThatProvide(ctx context.Context, in *api.GetAllProvidersRequest, opts ...grpc.CallOption) (api.Registry_GetChannelEntriesThatProvideClient, error) {
-	return nil, nil
+	s.ProvidersRequest = in
+	return s.ChannelEntryClient, s.Error
 }
 
 func (s *RegistryClientStub) GetLatestChannelEntriesThatProvide(ctx context.Context, in *api.GetLatestProvidersRequest, opts ...grpc.CallOption) (api.Registry_GetLatestChannelEntriesThatProvideClient, error) {
@@ -61,6 +65,14 @@ func (s *RegistryClientStub) ListBundles(ctx context.Context, in *api.ListBundle
 	return s.ListBundlesClient, s.Error
 }
 
+func (s *RegistryClientStub) GetCatalogInfo(ctx context.Context, in *api.GetCatalogInfoRequest, opts ...grpc.CallOption) (*api.CatalogInfo, error) {
+	return nil, nil
+}
+
+func (s *RegistryClientStub) Watch(ctx context.Context, in *api.WatchRequest, opts ...grpc.CallOption) (api.Registry_WatchClient, error) {
+	return nil, nil
+}
+
 func (s *RegistryClientStub) Check(ctx context.Context, in *grpc_health_v1.HealthCheckRequest, opts ...grpc.CallOption) (*grpc_health_v1.HealthCheckResponse, error) {
 	return nil, nil
 }
@@ -75,6 +87,24 @@ func (s *BundleReceiverStub) Recv() (*api.Bundle, error) {
 	return s.Bundle, s.Error
 }
 
+type ChannelEntryReceiverStub struct {
+	Entries []*api.ChannelEntry
+	Error   error
+	grpc.ClientStream
+}
+
+func (s *ChannelEntryReceiverStub) Recv() (*api.ChannelEntry, error) {
+	if len(s.Entries) == 0 {
+		if s.Error != nil {
+			return nil, s.Error
+		}
+		return nil, io.EOF
+	}
+	next := s.Entries[0]
+	s.Entries = s.Entries[1:]
+	return next, nil
+}
+
 func TestListBundlesError(t *testing.T) {
 	expected := errors.New("test error")
 	stub := &RegistryClientStub{
@@ -170,3 +200,44 @@ func TestGetPackage(t *testing.T) {
 		})
 	}
 }
+
+func TestNewClientTLS(t *testing.T) {
+	c, err := NewClient("localhost:50051", WithTLS(""))
+	require.NoError(t, err)
+	require.NoError(t, c.Close())
+
+	_, err = NewClient("localhost:50051", WithTLS("missing-ca.crt"))
+	require.Error(t, err)
+
+	_, err = NewClient("localhost:50051", WithClientCertificate("missing.crt", "missing.key"))
+	require.Error(t, err)
+}
+
+func TestGetChannelEntriesThatProvide(t *testing.T) {
+	expected := []*api.ChannelEntry{
+		{PackageName: "etcd", ChannelName: "alpha", BundleName: "etcdoperator.v0.9.2"},
+		{PackageName: "etcd", ChannelName: "beta", BundleName: "etcdoperator.v0.9.0"},
+	}
+	rstub := &ChannelEntryReceiverStub{
+		Entries: append([]*api.ChannelEntry(nil), expected...),
+		Error:   errors.New("test error"),
+	}
+	cstub := &RegistryClientStub{
+		ChannelEntryClient: rstub,
+	}
+	c := Client{
+		Registry: cstub,
+		Health:   cstub,
+	}
+
+	it, err := c.GetChannelEntriesThatProvide(context.TODO(), "etcd.database.coreos.com", "v1beta2", "EtcdCluster")
+	require.NoError(t, err)
+	require.Equal(t, &api.GetAllProvidersRequest{Group: "etcd.database.coreos.com", Version: "v1beta2", Kind: "EtcdCluster"}, cstub.ProvidersRequest)
+
+	var actual []*api.ChannelEntry
+	for entry := it.Next(); entry != nil; entry = it.Next() {
+		actual = append(actual, entry)
+	}
+	require.Equal(t, expected, actual)
+	require.EqualError(t, it.Error(), "test error")
+}
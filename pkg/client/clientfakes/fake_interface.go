@@ -0,0 +1,1160 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package clientfakes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/client"
+)
+
+type FakeInterface struct {
+	CloseStub        func() error
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct {
+	}
+	closeReturns struct {
+		result1 error
+	}
+	closeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetBundleStub        func(context.Context, string, string, string) (*api.Bundle, error)
+	getBundleMutex       sync.RWMutex
+	getBundleArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	getBundleReturns struct {
+		result1 *api.Bundle
+		result2 error
+	}
+	getBundleReturnsOnCall map[int]struct {
+		result1 *api.Bundle
+		result2 error
+	}
+	GetBundleInPackageChannelStub        func(context.Context, string, string) (*api.Bundle, error)
+	getBundleInPackageChannelMutex       sync.RWMutex
+	getBundleInPackageChannelArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	getBundleInPackageChannelReturns struct {
+		result1 *api.Bundle
+		result2 error
+	}
+	getBundleInPackageChannelReturnsOnCall map[int]struct {
+		result1 *api.Bundle
+		result2 error
+	}
+	GetBundleThatProvidesStub        func(context.Context, string, string, string) (*api.Bundle, error)
+	getBundleThatProvidesMutex       sync.RWMutex
+	getBundleThatProvidesArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	getBundleThatProvidesReturns struct {
+		result1 *api.Bundle
+		result2 error
+	}
+	getBundleThatProvidesReturnsOnCall map[int]struct {
+		result1 *api.Bundle
+		result2 error
+	}
+	GetCatalogInfoStub        func(context.Context) (*api.CatalogInfo, error)
+	getCatalogInfoMutex       sync.RWMutex
+	getCatalogInfoArgsForCall []struct {
+		arg1 context.Context
+	}
+	getCatalogInfoReturns struct {
+		result1 *api.CatalogInfo
+		result2 error
+	}
+	getCatalogInfoReturnsOnCall map[int]struct {
+		result1 *api.CatalogInfo
+		result2 error
+	}
+	GetChannelEntriesThatProvideStub        func(context.Context, string, string, string) (*client.ChannelEntryIterator, error)
+	getChannelEntriesThatProvideMutex       sync.RWMutex
+	getChannelEntriesThatProvideArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	getChannelEntriesThatProvideReturns struct {
+		result1 *client.ChannelEntryIterator
+		result2 error
+	}
+	getChannelEntriesThatProvideReturnsOnCall map[int]struct {
+		result1 *client.ChannelEntryIterator
+		result2 error
+	}
+	GetChannelEntriesThatReplaceStub        func(context.Context, string) (*client.ChannelEntryIterator, error)
+	getChannelEntriesThatReplaceMutex       sync.RWMutex
+	getChannelEntriesThatReplaceArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getChannelEntriesThatReplaceReturns struct {
+		result1 *client.ChannelEntryIterator
+		result2 error
+	}
+	getChannelEntriesThatReplaceReturnsOnCall map[int]struct {
+		result1 *client.ChannelEntryIterator
+		result2 error
+	}
+	GetLatestChannelEntriesThatProvideStub        func(context.Context, string, string, string) (*client.ChannelEntryIterator, error)
+	getLatestChannelEntriesThatProvideMutex       sync.RWMutex
+	getLatestChannelEntriesThatProvideArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	getLatestChannelEntriesThatProvideReturns struct {
+		result1 *client.ChannelEntryIterator
+		result2 error
+	}
+	getLatestChannelEntriesThatProvideReturnsOnCall map[int]struct {
+		result1 *client.ChannelEntryIterator
+		result2 error
+	}
+	GetPackageStub        func(context.Context, string) (*api.Package, error)
+	getPackageMutex       sync.RWMutex
+	getPackageArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getPackageReturns struct {
+		result1 *api.Package
+		result2 error
+	}
+	getPackageReturnsOnCall map[int]struct {
+		result1 *api.Package
+		result2 error
+	}
+	GetReplacementBundleInPackageChannelStub        func(context.Context, string, string, string) (*api.Bundle, error)
+	getReplacementBundleInPackageChannelMutex       sync.RWMutex
+	getReplacementBundleInPackageChannelArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	getReplacementBundleInPackageChannelReturns struct {
+		result1 *api.Bundle
+		result2 error
+	}
+	getReplacementBundleInPackageChannelReturnsOnCall map[int]struct {
+		result1 *api.Bundle
+		result2 error
+	}
+	HealthCheckStub        func(context.Context, time.Duration) (bool, error)
+	healthCheckMutex       sync.RWMutex
+	healthCheckArgsForCall []struct {
+		arg1 context.Context
+		arg2 time.Duration
+	}
+	healthCheckReturns struct {
+		result1 bool
+		result2 error
+	}
+	healthCheckReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	ListBundlesStub        func(context.Context) (*client.BundleIterator, error)
+	listBundlesMutex       sync.RWMutex
+	listBundlesArgsForCall []struct {
+		arg1 context.Context
+	}
+	listBundlesReturns struct {
+		result1 *client.BundleIterator
+		result2 error
+	}
+	listBundlesReturnsOnCall map[int]struct {
+		result1 *client.BundleIterator
+		result2 error
+	}
+	ListPackagesStub        func(context.Context) (*client.PackageNameIterator, error)
+	listPackagesMutex       sync.RWMutex
+	listPackagesArgsForCall []struct {
+		arg1 context.Context
+	}
+	listPackagesReturns struct {
+		result1 *client.PackageNameIterator
+		result2 error
+	}
+	listPackagesReturnsOnCall map[int]struct {
+		result1 *client.PackageNameIterator
+		result2 error
+	}
+	WatchStub        func(context.Context, string) (*client.WatchEventIterator, error)
+	watchMutex       sync.RWMutex
+	watchArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	watchReturns struct {
+		result1 *client.WatchEventIterator
+		result2 error
+	}
+	watchReturnsOnCall map[int]struct {
+		result1 *client.WatchEventIterator
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeInterface) Close() error {
+	fake.closeMutex.Lock()
+	ret, specificReturn := fake.closeReturnsOnCall[len(fake.closeArgsForCall)]
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct {
+	}{})
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if fake.CloseStub != nil {
+		return fake.CloseStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.closeReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeInterface) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *FakeInterface) CloseCalls(stub func() error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = stub
+}
+
+func (fake *FakeInterface) CloseReturns(result1 error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = nil
+	fake.closeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeInterface) CloseReturnsOnCall(i int, result1 error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = nil
+	if fake.closeReturnsOnCall == nil {
+		fake.closeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.closeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeInterface) GetBundle(arg1 context.Context, arg2 string, arg3 string, arg4 string) (*api.Bundle, error) {
+	fake.getBundleMutex.Lock()
+	ret, specificReturn := fake.getBundleReturnsOnCall[len(fake.getBundleArgsForCall)]
+	fake.getBundleArgsForCall = append(fake.getBundleArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("GetBundle", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getBundleMutex.Unlock()
+	if fake.GetBundleStub != nil {
+		return fake.GetBundleStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getBundleReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInterface) GetBundleCallCount() int {
+	fake.getBundleMutex.RLock()
+	defer fake.getBundleMutex.RUnlock()
+	return len(fake.getBundleArgsForCall)
+}
+
+func (fake *FakeInterface) GetBundleCalls(stub func(context.Context, string, string, string) (*api.Bundle, error)) {
+	fake.getBundleMutex.Lock()
+	defer fake.getBundleMutex.Unlock()
+	fake.GetBundleStub = stub
+}
+
+func (fake *FakeInterface) GetBundleArgsForCall(i int) (context.Context, string, string, string) {
+	fake.getBundleMutex.RLock()
+	defer fake.getBundleMutex.RUnlock()
+	argsForCall := fake.getBundleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeInterface) GetBundleReturns(result1 *api.Bundle, result2 error) {
+	fake.getBundleMutex.Lock()
+	defer fake.getBundleMutex.Unlock()
+	fake.GetBundleStub = nil
+	fake.getBundleReturns = struct {
+		result1 *api.Bundle
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetBundleReturnsOnCall(i int, result1 *api.Bundle, result2 error) {
+	fake.getBundleMutex.Lock()
+	defer fake.getBundleMutex.Unlock()
+	fake.GetBundleStub = nil
+	if fake.getBundleReturnsOnCall == nil {
+		fake.getBundleReturnsOnCall = make(map[int]struct {
+			result1 *api.Bundle
+			result2 error
+		})
+	}
+	fake.getBundleReturnsOnCall[i] = struct {
+		result1 *api.Bundle
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetBundleInPackageChannel(arg1 context.Context, arg2 string, arg3 string) (*api.Bundle, error) {
+	fake.getBundleInPackageChannelMutex.Lock()
+	ret, specificReturn := fake.getBundleInPackageChannelReturnsOnCall[len(fake.getBundleInPackageChannelArgsForCall)]
+	fake.getBundleInPackageChannelArgsForCall = append(fake.getBundleInPackageChannelArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetBundleInPackageChannel", []interface{}{arg1, arg2, arg3})
+	fake.getBundleInPackageChannelMutex.Unlock()
+	if fake.GetBundleInPackageChannelStub != nil {
+		return fake.GetBundleInPackageChannelStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getBundleInPackageChannelReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInterface) GetBundleInPackageChannelCallCount() int {
+	fake.getBundleInPackageChannelMutex.RLock()
+	defer fake.getBundleInPackageChannelMutex.RUnlock()
+	return len(fake.getBundleInPackageChannelArgsForCall)
+}
+
+func (fake *FakeInterface) GetBundleInPackageChannelCalls(stub func(context.Context, string, string) (*api.Bundle, error)) {
+	fake.getBundleInPackageChannelMutex.Lock()
+	defer fake.getBundleInPackageChannelMutex.Unlock()
+	fake.GetBundleInPackageChannelStub = stub
+}
+
+func (fake *FakeInterface) GetBundleInPackageChannelArgsForCall(i int) (context.Context, string, string) {
+	fake.getBundleInPackageChannelMutex.RLock()
+	defer fake.getBundleInPackageChannelMutex.RUnlock()
+	argsForCall := fake.getBundleInPackageChannelArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeInterface) GetBundleInPackageChannelReturns(result1 *api.Bundle, result2 error) {
+	fake.getBundleInPackageChannelMutex.Lock()
+	defer fake.getBundleInPackageChannelMutex.Unlock()
+	fake.GetBundleInPackageChannelStub = nil
+	fake.getBundleInPackageChannelReturns = struct {
+		result1 *api.Bundle
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetBundleInPackageChannelReturnsOnCall(i int, result1 *api.Bundle, result2 error) {
+	fake.getBundleInPackageChannelMutex.Lock()
+	defer fake.getBundleInPackageChannelMutex.Unlock()
+	fake.GetBundleInPackageChannelStub = nil
+	if fake.getBundleInPackageChannelReturnsOnCall == nil {
+		fake.getBundleInPackageChannelReturnsOnCall = make(map[int]struct {
+			result1 *api.Bundle
+			result2 error
+		})
+	}
+	fake.getBundleInPackageChannelReturnsOnCall[i] = struct {
+		result1 *api.Bundle
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetBundleThatProvides(arg1 context.Context, arg2 string, arg3 string, arg4 string) (*api.Bundle, error) {
+	fake.getBundleThatProvidesMutex.Lock()
+	ret, specificReturn := fake.getBundleThatProvidesReturnsOnCall[len(fake.getBundleThatProvidesArgsForCall)]
+	fake.getBundleThatProvidesArgsForCall = append(fake.getBundleThatProvidesArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("GetBundleThatProvides", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getBundleThatProvidesMutex.Unlock()
+	if fake.GetBundleThatProvidesStub != nil {
+		return fake.GetBundleThatProvidesStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getBundleThatProvidesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInterface) GetBundleThatProvidesCallCount() int {
+	fake.getBundleThatProvidesMutex.RLock()
+	defer fake.getBundleThatProvidesMutex.RUnlock()
+	return len(fake.getBundleThatProvidesArgsForCall)
+}
+
+func (fake *FakeInterface) GetBundleThatProvidesCalls(stub func(context.Context, string, string, string) (*api.Bundle, error)) {
+	fake.getBundleThatProvidesMutex.Lock()
+	defer fake.getBundleThatProvidesMutex.Unlock()
+	fake.GetBundleThatProvidesStub = stub
+}
+
+func (fake *FakeInterface) GetBundleThatProvidesArgsForCall(i int) (context.Context, string, string, string) {
+	fake.getBundleThatProvidesMutex.RLock()
+	defer fake.getBundleThatProvidesMutex.RUnlock()
+	argsForCall := fake.getBundleThatProvidesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeInterface) GetBundleThatProvidesReturns(result1 *api.Bundle, result2 error) {
+	fake.getBundleThatProvidesMutex.Lock()
+	defer fake.getBundleThatProvidesMutex.Unlock()
+	fake.GetBundleThatProvidesStub = nil
+	fake.getBundleThatProvidesReturns = struct {
+		result1 *api.Bundle
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetBundleThatProvidesReturnsOnCall(i int, result1 *api.Bundle, result2 error) {
+	fake.getBundleThatProvidesMutex.Lock()
+	defer fake.getBundleThatProvidesMutex.Unlock()
+	fake.GetBundleThatProvidesStub = nil
+	if fake.getBundleThatProvidesReturnsOnCall == nil {
+		fake.getBundleThatProvidesReturnsOnCall = make(map[int]struct {
+			result1 *api.Bundle
+			result2 error
+		})
+	}
+	fake.getBundleThatProvidesReturnsOnCall[i] = struct {
+		result1 *api.Bundle
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetCatalogInfo(arg1 context.Context) (*api.CatalogInfo, error) {
+	fake.getCatalogInfoMutex.Lock()
+	ret, specificReturn := fake.getCatalogInfoReturnsOnCall[len(fake.getCatalogInfoArgsForCall)]
+	fake.getCatalogInfoArgsForCall = append(fake.getCatalogInfoArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("GetCatalogInfo", []interface{}{arg1})
+	fake.getCatalogInfoMutex.Unlock()
+	if fake.GetCatalogInfoStub != nil {
+		return fake.GetCatalogInfoStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getCatalogInfoReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInterface) GetCatalogInfoCallCount() int {
+	fake.getCatalogInfoMutex.RLock()
+	defer fake.getCatalogInfoMutex.RUnlock()
+	return len(fake.getCatalogInfoArgsForCall)
+}
+
+func (fake *FakeInterface) GetCatalogInfoCalls(stub func(context.Context) (*api.CatalogInfo, error)) {
+	fake.getCatalogInfoMutex.Lock()
+	defer fake.getCatalogInfoMutex.Unlock()
+	fake.GetCatalogInfoStub = stub
+}
+
+func (fake *FakeInterface) GetCatalogInfoArgsForCall(i int) context.Context {
+	fake.getCatalogInfoMutex.RLock()
+	defer fake.getCatalogInfoMutex.RUnlock()
+	argsForCall := fake.getCatalogInfoArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeInterface) GetCatalogInfoReturns(result1 *api.CatalogInfo, result2 error) {
+	fake.getCatalogInfoMutex.Lock()
+	defer fake.getCatalogInfoMutex.Unlock()
+	fake.GetCatalogInfoStub = nil
+	fake.getCatalogInfoReturns = struct {
+		result1 *api.CatalogInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetCatalogInfoReturnsOnCall(i int, result1 *api.CatalogInfo, result2 error) {
+	fake.getCatalogInfoMutex.Lock()
+	defer fake.getCatalogInfoMutex.Unlock()
+	fake.GetCatalogInfoStub = nil
+	if fake.getCatalogInfoReturnsOnCall == nil {
+		fake.getCatalogInfoReturnsOnCall = make(map[int]struct {
+			result1 *api.CatalogInfo
+			result2 error
+		})
+	}
+	fake.getCatalogInfoReturnsOnCall[i] = struct {
+		result1 *api.CatalogInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetChannelEntriesThatProvide(arg1 context.Context, arg2 string, arg3 string, arg4 string) (*client.ChannelEntryIterator, error) {
+	fake.getChannelEntriesThatProvideMutex.Lock()
+	ret, specificReturn := fake.getChannelEntriesThatProvideReturnsOnCall[len(fake.getChannelEntriesThatProvideArgsForCall)]
+	fake.getChannelEntriesThatProvideArgsForCall = append(fake.getChannelEntriesThatProvideArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("GetChannelEntriesThatProvide", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getChannelEntriesThatProvideMutex.Unlock()
+	if fake.GetChannelEntriesThatProvideStub != nil {
+		return fake.GetChannelEntriesThatProvideStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getChannelEntriesThatProvideReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInterface) GetChannelEntriesThatProvideCallCount() int {
+	fake.getChannelEntriesThatProvideMutex.RLock()
+	defer fake.getChannelEntriesThatProvideMutex.RUnlock()
+	return len(fake.getChannelEntriesThatProvideArgsForCall)
+}
+
+func (fake *FakeInterface) GetChannelEntriesThatProvideCalls(stub func(context.Context, string, string, string) (*client.ChannelEntryIterator, error)) {
+	fake.getChannelEntriesThatProvideMutex.Lock()
+	defer fake.getChannelEntriesThatProvideMutex.Unlock()
+	fake.GetChannelEntriesThatProvideStub = stub
+}
+
+func (fake *FakeInterface) GetChannelEntriesThatProvideArgsForCall(i int) (context.Context, string, string, string) {
+	fake.getChannelEntriesThatProvideMutex.RLock()
+	defer fake.getChannelEntriesThatProvideMutex.RUnlock()
+	argsForCall := fake.getChannelEntriesThatProvideArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeInterface) GetChannelEntriesThatProvideReturns(result1 *client.ChannelEntryIterator, result2 error) {
+	fake.getChannelEntriesThatProvideMutex.Lock()
+	defer fake.getChannelEntriesThatProvideMutex.Unlock()
+	fake.GetChannelEntriesThatProvideStub = nil
+	fake.getChannelEntriesThatProvideReturns = struct {
+		result1 *client.ChannelEntryIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetChannelEntriesThatProvideReturnsOnCall(i int, result1 *client.ChannelEntryIterator, result2 error) {
+	fake.getChannelEntriesThatProvideMutex.Lock()
+	defer fake.getChannelEntriesThatProvideMutex.Unlock()
+	fake.GetChannelEntriesThatProvideStub = nil
+	if fake.getChannelEntriesThatProvideReturnsOnCall == nil {
+		fake.getChannelEntriesThatProvideReturnsOnCall = make(map[int]struct {
+			result1 *client.ChannelEntryIterator
+			result2 error
+		})
+	}
+	fake.getChannelEntriesThatProvideReturnsOnCall[i] = struct {
+		result1 *client.ChannelEntryIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetChannelEntriesThatReplace(arg1 context.Context, arg2 string) (*client.ChannelEntryIterator, error) {
+	fake.getChannelEntriesThatReplaceMutex.Lock()
+	ret, specificReturn := fake.getChannelEntriesThatReplaceReturnsOnCall[len(fake.getChannelEntriesThatReplaceArgsForCall)]
+	fake.getChannelEntriesThatReplaceArgsForCall = append(fake.getChannelEntriesThatReplaceArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetChannelEntriesThatReplace", []interface{}{arg1, arg2})
+	fake.getChannelEntriesThatReplaceMutex.Unlock()
+	if fake.GetChannelEntriesThatReplaceStub != nil {
+		return fake.GetChannelEntriesThatReplaceStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getChannelEntriesThatReplaceReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInterface) GetChannelEntriesThatReplaceCallCount() int {
+	fake.getChannelEntriesThatReplaceMutex.RLock()
+	defer fake.getChannelEntriesThatReplaceMutex.RUnlock()
+	return len(fake.getChannelEntriesThatReplaceArgsForCall)
+}
+
+func (fake *FakeInterface) GetChannelEntriesThatReplaceCalls(stub func(context.Context, string) (*client.ChannelEntryIterator, error)) {
+	fake.getChannelEntriesThatReplaceMutex.Lock()
+	defer fake.getChannelEntriesThatReplaceMutex.Unlock()
+	fake.GetChannelEntriesThatReplaceStub = stub
+}
+
+func (fake *FakeInterface) GetChannelEntriesThatReplaceArgsForCall(i int) (context.Context, string) {
+	fake.getChannelEntriesThatReplaceMutex.RLock()
+	defer fake.getChannelEntriesThatReplaceMutex.RUnlock()
+	argsForCall := fake.getChannelEntriesThatReplaceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeInterface) GetChannelEntriesThatReplaceReturns(result1 *client.ChannelEntryIterator, result2 error) {
+	fake.getChannelEntriesThatReplaceMutex.Lock()
+	defer fake.getChannelEntriesThatReplaceMutex.Unlock()
+	fake.GetChannelEntriesThatReplaceStub = nil
+	fake.getChannelEntriesThatReplaceReturns = struct {
+		result1 *client.ChannelEntryIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetChannelEntriesThatReplaceReturnsOnCall(i int, result1 *client.ChannelEntryIterator, result2 error) {
+	fake.getChannelEntriesThatReplaceMutex.Lock()
+	defer fake.getChannelEntriesThatReplaceMutex.Unlock()
+	fake.GetChannelEntriesThatReplaceStub = nil
+	if fake.getChannelEntriesThatReplaceReturnsOnCall == nil {
+		fake.getChannelEntriesThatReplaceReturnsOnCall = make(map[int]struct {
+			result1 *client.ChannelEntryIterator
+			result2 error
+		})
+	}
+	fake.getChannelEntriesThatReplaceReturnsOnCall[i] = struct {
+		result1 *client.ChannelEntryIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetLatestChannelEntriesThatProvide(arg1 context.Context, arg2 string, arg3 string, arg4 string) (*client.ChannelEntryIterator, error) {
+	fake.getLatestChannelEntriesThatProvideMutex.Lock()
+	ret, specificReturn := fake.getLatestChannelEntriesThatProvideReturnsOnCall[len(fake.getLatestChannelEntriesThatProvideArgsForCall)]
+	fake.getLatestChannelEntriesThatProvideArgsForCall = append(fake.getLatestChannelEntriesThatProvideArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("GetLatestChannelEntriesThatProvide", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getLatestChannelEntriesThatProvideMutex.Unlock()
+	if fake.GetLatestChannelEntriesThatProvideStub != nil {
+		return fake.GetLatestChannelEntriesThatProvideStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getLatestChannelEntriesThatProvideReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInterface) GetLatestChannelEntriesThatProvideCallCount() int {
+	fake.getLatestChannelEntriesThatProvideMutex.RLock()
+	defer fake.getLatestChannelEntriesThatProvideMutex.RUnlock()
+	return len(fake.getLatestChannelEntriesThatProvideArgsForCall)
+}
+
+func (fake *FakeInterface) GetLatestChannelEntriesThatProvideCalls(stub func(context.Context, string, string, string) (*client.ChannelEntryIterator, error)) {
+	fake.getLatestChannelEntriesThatProvideMutex.Lock()
+	defer fake.getLatestChannelEntriesThatProvideMutex.Unlock()
+	fake.GetLatestChannelEntriesThatProvideStub = stub
+}
+
+func (fake *FakeInterface) GetLatestChannelEntriesThatProvideArgsForCall(i int) (context.Context, string, string, string) {
+	fake.getLatestChannelEntriesThatProvideMutex.RLock()
+	defer fake.getLatestChannelEntriesThatProvideMutex.RUnlock()
+	argsForCall := fake.getLatestChannelEntriesThatProvideArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeInterface) GetLatestChannelEntriesThatProvideReturns(result1 *client.ChannelEntryIterator, result2 error) {
+	fake.getLatestChannelEntriesThatProvideMutex.Lock()
+	defer fake.getLatestChannelEntriesThatProvideMutex.Unlock()
+	fake.GetLatestChannelEntriesThatProvideStub = nil
+	fake.getLatestChannelEntriesThatProvideReturns = struct {
+		result1 *client.ChannelEntryIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetLatestChannelEntriesThatProvideReturnsOnCall(i int, result1 *client.ChannelEntryIterator, result2 error) {
+	fake.getLatestChannelEntriesThatProvideMutex.Lock()
+	defer fake.getLatestChannelEntriesThatProvideMutex.Unlock()
+	fake.GetLatestChannelEntriesThatProvideStub = nil
+	if fake.getLatestChannelEntriesThatProvideReturnsOnCall == nil {
+		fake.getLatestChannelEntriesThatProvideReturnsOnCall = make(map[int]struct {
+			result1 *client.ChannelEntryIterator
+			result2 error
+		})
+	}
+	fake.getLatestChannelEntriesThatProvideReturnsOnCall[i] = struct {
+		result1 *client.ChannelEntryIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetPackage(arg1 context.Context, arg2 string) (*api.Package, error) {
+	fake.getPackageMutex.Lock()
+	ret, specificReturn := fake.getPackageReturnsOnCall[len(fake.getPackageArgsForCall)]
+	fake.getPackageArgsForCall = append(fake.getPackageArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetPackage", []interface{}{arg1, arg2})
+	fake.getPackageMutex.Unlock()
+	if fake.GetPackageStub != nil {
+		return fake.GetPackageStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getPackageReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInterface) GetPackageCallCount() int {
+	fake.getPackageMutex.RLock()
+	defer fake.getPackageMutex.RUnlock()
+	return len(fake.getPackageArgsForCall)
+}
+
+func (fake *FakeInterface) GetPackageCalls(stub func(context.Context, string) (*api.Package, error)) {
+	fake.getPackageMutex.Lock()
+	defer fake.getPackageMutex.Unlock()
+	fake.GetPackageStub = stub
+}
+
+func (fake *FakeInterface) GetPackageArgsForCall(i int) (context.Context, string) {
+	fake.getPackageMutex.RLock()
+	defer fake.getPackageMutex.RUnlock()
+	argsForCall := fake.getPackageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeInterface) GetPackageReturns(result1 *api.Package, result2 error) {
+	fake.getPackageMutex.Lock()
+	defer fake.getPackageMutex.Unlock()
+	fake.GetPackageStub = nil
+	fake.getPackageReturns = struct {
+		result1 *api.Package
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetPackageReturnsOnCall(i int, result1 *api.Package, result2 error) {
+	fake.getPackageMutex.Lock()
+	defer fake.getPackageMutex.Unlock()
+	fake.GetPackageStub = nil
+	if fake.getPackageReturnsOnCall == nil {
+		fake.getPackageReturnsOnCall = make(map[int]struct {
+			result1 *api.Package
+			result2 error
+		})
+	}
+	fake.getPackageReturnsOnCall[i] = struct {
+		result1 *api.Package
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetReplacementBundleInPackageChannel(arg1 context.Context, arg2 string, arg3 string, arg4 string) (*api.Bundle, error) {
+	fake.getReplacementBundleInPackageChannelMutex.Lock()
+	ret, specificReturn := fake.getReplacementBundleInPackageChannelReturnsOnCall[len(fake.getReplacementBundleInPackageChannelArgsForCall)]
+	fake.getReplacementBundleInPackageChannelArgsForCall = append(fake.getReplacementBundleInPackageChannelArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("GetReplacementBundleInPackageChannel", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getReplacementBundleInPackageChannelMutex.Unlock()
+	if fake.GetReplacementBundleInPackageChannelStub != nil {
+		return fake.GetReplacementBundleInPackageChannelStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getReplacementBundleInPackageChannelReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInterface) GetReplacementBundleInPackageChannelCallCount() int {
+	fake.getReplacementBundleInPackageChannelMutex.RLock()
+	defer fake.getReplacementBundleInPackageChannelMutex.RUnlock()
+	return len(fake.getReplacementBundleInPackageChannelArgsForCall)
+}
+
+func (fake *FakeInterface) GetReplacementBundleInPackageChannelCalls(stub func(context.Context, string, string, string) (*api.Bundle, error)) {
+	fake.getReplacementBundleInPackageChannelMutex.Lock()
+	defer fake.getReplacementBundleInPackageChannelMutex.Unlock()
+	fake.GetReplacementBundleInPackageChannelStub = stub
+}
+
+func (fake *FakeInterface) GetReplacementBundleInPackageChannelArgsForCall(i int) (context.Context, string, string, string) {
+	fake.getReplacementBundleInPackageChannelMutex.RLock()
+	defer fake.getReplacementBundleInPackageChannelMutex.RUnlock()
+	argsForCall := fake.getReplacementBundleInPackageChannelArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeInterface) GetReplacementBundleInPackageChannelReturns(result1 *api.Bundle, result2 error) {
+	fake.getReplacementBundleInPackageChannelMutex.Lock()
+	defer fake.getReplacementBundleInPackageChannelMutex.Unlock()
+	fake.GetReplacementBundleInPackageChannelStub = nil
+	fake.getReplacementBundleInPackageChannelReturns = struct {
+		result1 *api.Bundle
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) GetReplacementBundleInPackageChannelReturnsOnCall(i int, result1 *api.Bundle, result2 error) {
+	fake.getReplacementBundleInPackageChannelMutex.Lock()
+	defer fake.getReplacementBundleInPackageChannelMutex.Unlock()
+	fake.GetReplacementBundleInPackageChannelStub = nil
+	if fake.getReplacementBundleInPackageChannelReturnsOnCall == nil {
+		fake.getReplacementBundleInPackageChannelReturnsOnCall = make(map[int]struct {
+			result1 *api.Bundle
+			result2 error
+		})
+	}
+	fake.getReplacementBundleInPackageChannelReturnsOnCall[i] = struct {
+		result1 *api.Bundle
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) HealthCheck(arg1 context.Context, arg2 time.Duration) (bool, error) {
+	fake.healthCheckMutex.Lock()
+	ret, specificReturn := fake.healthCheckReturnsOnCall[len(fake.healthCheckArgsForCall)]
+	fake.healthCheckArgsForCall = append(fake.healthCheckArgsForCall, struct {
+		arg1 context.Context
+		arg2 time.Duration
+	}{arg1, arg2})
+	fake.recordInvocation("HealthCheck", []interface{}{arg1, arg2})
+	fake.healthCheckMutex.Unlock()
+	if fake.HealthCheckStub != nil {
+		return fake.HealthCheckStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.healthCheckReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInterface) HealthCheckCallCount() int {
+	fake.healthCheckMutex.RLock()
+	defer fake.healthCheckMutex.RUnlock()
+	return len(fake.healthCheckArgsForCall)
+}
+
+func (fake *FakeInterface) HealthCheckCalls(stub func(context.Context, time.Duration) (bool, error)) {
+	fake.healthCheckMutex.Lock()
+	defer fake.healthCheckMutex.Unlock()
+	fake.HealthCheckStub = stub
+}
+
+func (fake *FakeInterface) HealthCheckArgsForCall(i int) (context.Context, time.Duration) {
+	fake.healthCheckMutex.RLock()
+	defer fake.healthCheckMutex.RUnlock()
+	argsForCall := fake.healthCheckArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeInterface) HealthCheckReturns(result1 bool, result2 error) {
+	fake.healthCheckMutex.Lock()
+	defer fake.healthCheckMutex.Unlock()
+	fake.HealthCheckStub = nil
+	fake.healthCheckReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) HealthCheckReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.healthCheckMutex.Lock()
+	defer fake.healthCheckMutex.Unlock()
+	fake.HealthCheckStub = nil
+	if fake.healthCheckReturnsOnCall == nil {
+		fake.healthCheckReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.healthCheckReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) ListBundles(arg1 context.Context) (*client.BundleIterator, error) {
+	fake.listBundlesMutex.Lock()
+	ret, specificReturn := fake.listBundlesReturnsOnCall[len(fake.listBundlesArgsForCall)]
+	fake.listBundlesArgsForCall = append(fake.listBundlesArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("ListBundles", []interface{}{arg1})
+	fake.listBundlesMutex.Unlock()
+	if fake.ListBundlesStub != nil {
+		return fake.ListBundlesStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listBundlesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInterface) ListBundlesCallCount() int {
+	fake.listBundlesMutex.RLock()
+	defer fake.listBundlesMutex.RUnlock()
+	return len(fake.listBundlesArgsForCall)
+}
+
+func (fake *FakeInterface) ListBundlesCalls(stub func(context.Context) (*client.BundleIterator, error)) {
+	fake.listBundlesMutex.Lock()
+	defer fake.listBundlesMutex.Unlock()
+	fake.ListBundlesStub = stub
+}
+
+func (fake *FakeInterface) ListBundlesArgsForCall(i int) context.Context {
+	fake.listBundlesMutex.RLock()
+	defer fake.listBundlesMutex.RUnlock()
+	argsForCall := fake.listBundlesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeInterface) ListBundlesReturns(result1 *client.BundleIterator, result2 error) {
+	fake.listBundlesMutex.Lock()
+	defer fake.listBundlesMutex.Unlock()
+	fake.ListBundlesStub = nil
+	fake.listBundlesReturns = struct {
+		result1 *client.BundleIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) ListBundlesReturnsOnCall(i int, result1 *client.BundleIterator, result2 error) {
+	fake.listBundlesMutex.Lock()
+	defer fake.listBundlesMutex.Unlock()
+	fake.ListBundlesStub = nil
+	if fake.listBundlesReturnsOnCall == nil {
+		fake.listBundlesReturnsOnCall = make(map[int]struct {
+			result1 *client.BundleIterator
+			result2 error
+		})
+	}
+	fake.listBundlesReturnsOnCall[i] = struct {
+		result1 *client.BundleIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) ListPackages(arg1 context.Context) (*client.PackageNameIterator, error) {
+	fake.listPackagesMutex.Lock()
+	ret, specificReturn := fake.listPackagesReturnsOnCall[len(fake.listPackagesArgsForCall)]
+	fake.listPackagesArgsForCall = append(fake.listPackagesArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("ListPackages", []interface{}{arg1})
+	fake.listPackagesMutex.Unlock()
+	if fake.ListPackagesStub != nil {
+		return fake.ListPackagesStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listPackagesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInterface) ListPackagesCallCount() int {
+	fake.listPackagesMutex.RLock()
+	defer fake.listPackagesMutex.RUnlock()
+	return len(fake.listPackagesArgsForCall)
+}
+
+func (fake *FakeInterface) ListPackagesCalls(stub func(context.Context) (*client.PackageNameIterator, error)) {
+	fake.listPackagesMutex.Lock()
+	defer fake.listPackagesMutex.Unlock()
+	fake.ListPackagesStub = stub
+}
+
+func (fake *FakeInterface) ListPackagesArgsForCall(i int) context.Context {
+	fake.listPackagesMutex.RLock()
+	defer fake.listPackagesMutex.RUnlock()
+	argsForCall := fake.listPackagesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeInterface) ListPackagesReturns(result1 *client.PackageNameIterator, result2 error) {
+	fake.listPackagesMutex.Lock()
+	defer fake.listPackagesMutex.Unlock()
+	fake.ListPackagesStub = nil
+	fake.listPackagesReturns = struct {
+		result1 *client.PackageNameIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) ListPackagesReturnsOnCall(i int, result1 *client.PackageNameIterator, result2 error) {
+	fake.listPackagesMutex.Lock()
+	defer fake.listPackagesMutex.Unlock()
+	fake.ListPackagesStub = nil
+	if fake.listPackagesReturnsOnCall == nil {
+		fake.listPackagesReturnsOnCall = make(map[int]struct {
+			result1 *client.PackageNameIterator
+			result2 error
+		})
+	}
+	fake.listPackagesReturnsOnCall[i] = struct {
+		result1 *client.PackageNameIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) Watch(arg1 context.Context, arg2 string) (*client.WatchEventIterator, error) {
+	fake.watchMutex.Lock()
+	ret, specificReturn := fake.watchReturnsOnCall[len(fake.watchArgsForCall)]
+	fake.watchArgsForCall = append(fake.watchArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("Watch", []interface{}{arg1, arg2})
+	fake.watchMutex.Unlock()
+	if fake.WatchStub != nil {
+		return fake.WatchStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.watchReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInterface) WatchCallCount() int {
+	fake.watchMutex.RLock()
+	defer fake.watchMutex.RUnlock()
+	return len(fake.watchArgsForCall)
+}
+
+func (fake *FakeInterface) WatchCalls(stub func(context.Context, string) (*client.WatchEventIterator, error)) {
+	fake.watchMutex.Lock()
+	defer fake.watchMutex.Unlock()
+	fake.WatchStub = stub
+}
+
+func (fake *FakeInterface) WatchArgsForCall(i int) (context.Context, string) {
+	fake.watchMutex.RLock()
+	defer fake.watchMutex.RUnlock()
+	argsForCall := fake.watchArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeInterface) WatchReturns(result1 *client.WatchEventIterator, result2 error) {
+	fake.watchMutex.Lock()
+	defer fake.watchMutex.Unlock()
+	fake.WatchStub = nil
+	fake.watchReturns = struct {
+		result1 *client.WatchEventIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) WatchReturnsOnCall(i int, result1 *client.WatchEventIterator, result2 error) {
+	fake.watchMutex.Lock()
+	defer fake.watchMutex.Unlock()
+	fake.WatchStub = nil
+	if fake.watchReturnsOnCall == nil {
+		fake.watchReturnsOnCall = make(map[int]struct {
+			result1 *client.WatchEventIterator
+			result2 error
+		})
+	}
+	fake.watchReturnsOnCall[i] = struct {
+		result1 *client.WatchEventIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterface) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	fake.getBundleMutex.RLock()
+	defer fake.getBundleMutex.RUnlock()
+	fake.getBundleInPackageChannelMutex.RLock()
+	defer fake.getBundleInPackageChannelMutex.RUnlock()
+	fake.getBundleThatProvidesMutex.RLock()
+	defer fake.getBundleThatProvidesMutex.RUnlock()
+	fake.getCatalogInfoMutex.RLock()
+	defer fake.getCatalogInfoMutex.RUnlock()
+	fake.getChannelEntriesThatProvideMutex.RLock()
+	defer fake.getChannelEntriesThatProvideMutex.RUnlock()
+	fake.getChannelEntriesThatReplaceMutex.RLock()
+	defer fake.getChannelEntriesThatReplaceMutex.RUnlock()
+	fake.getLatestChannelEntriesThatProvideMutex.RLock()
+	defer fake.getLatestChannelEntriesThatProvideMutex.RUnlock()
+	fake.getPackageMutex.RLock()
+	defer fake.getPackageMutex.RUnlock()
+	fake.getReplacementBundleInPackageChannelMutex.RLock()
+	defer fake.getReplacementBundleInPackageChannelMutex.RUnlock()
+	fake.healthCheckMutex.RLock()
+	defer fake.healthCheckMutex.RUnlock()
+	fake.listBundlesMutex.RLock()
+	defer fake.listBundlesMutex.RUnlock()
+	fake.listPackagesMutex.RLock()
+	defer fake.listPackagesMutex.RUnlock()
+	fake.watchMutex.RLock()
+	defer fake.watchMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeInterface) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ client.Interface = new(FakeInterface)
@@ -0,0 +1,302 @@
+// Package local provides registry API clients served in-process, without a network
+// connection. It depends on the server and sqlite packages, so it's kept apart from the
+// remote client.
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/operator-framework/operator-registry/internal/declcfg"
+	"github.com/operator-framework/operator-registry/internal/model"
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/api/grpc_health_v1"
+	"github.com/operator-framework/operator-registry/pkg/client"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+	"github.com/operator-framework/operator-registry/pkg/server"
+	"github.com/operator-framework/operator-registry/pkg/sqlite"
+)
+
+// NewClient returns a client that serves the registry API for store in-process. Calls are
+// handled by the same server implementation as the registry servers, so they return the
+// same results and status codes. Results are copies, which callers are free to modify.
+func NewClient(store registry.GRPCQuery, opts ...server.RegistryServerOption) *client.Client {
+	return newLocalClient(server.NewRegistryServer(store, opts...), nil)
+}
+
+// NewClientFromModel returns a local client for an index model.
+func NewClientFromModel(m model.Model) *client.Client {
+	info := server.NewModelCatalogInfo(server.SourceTypeDeclcfg, "", time.Now(), m)
+	return NewClient(registry.NewQuerier(m), server.WithCatalogInfo(info))
+}
+
+// NewClientFromDeclcfg returns a local client for the declarative configs in configDir.
+func NewClientFromDeclcfg(configDir string) (*client.Client, error) {
+	configFS := os.DirFS(configDir)
+	digest, err := server.DigestFS(configFS)
+	if err != nil {
+		return nil, fmt.Errorf("digest declarative config directory: %v", err)
+	}
+	cfg, err := declcfg.LoadFS(configFS)
+	if err != nil {
+		return nil, fmt.Errorf("load declarative config directory: %v", err)
+	}
+	m, err := declcfg.ConvertToModel(*cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not build index model from declarative config: %v", err)
+	}
+	info := server.NewModelCatalogInfo(server.SourceTypeDeclcfg, digest, time.Now(), m)
+	return NewClient(registry.NewQuerier(m), server.WithCatalogInfo(info)), nil
+}
+
+// NewClientFromSqlite returns a local client for the sqlite database at dbFile, which
+// is opened read-only and migrated in memory if needed. Closing the client closes the database.
+func NewClientFromSqlite(dbFile string) (*client.Client, error) {
+	db, err := sqlite.OpenReadOnlyMigrated(context.TODO(), dbFile)
+	if err != nil {
+		return nil, err
+	}
+	store := sqlite.NewSQLLiteQuerierFromDb(db)
+
+	var opts []server.RegistryServerOption
+	if digest, err := server.DigestFile(dbFile); err == nil {
+		if info, err := server.NewSQLCatalogInfo(context.TODO(), server.SourceTypeSqlite, digest, time.Now(), store); err == nil {
+			opts = append(opts, server.WithCatalogInfo(info))
+		}
+	}
+	if _, err := store.ListTables(context.TODO()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to read database %s: %v", dbFile, err)
+	}
+	return newLocalClient(server.NewRegistryServer(store, opts...), db.Close), nil
+}
+
+func newLocalClient(srv api.RegistryServer, closer func() error) *client.Client {
+	return client.NewClientFromServers(&localRegistryClient{server: srv}, &localHealthClient{server: server.NewHealthServer()}, closer)
+}
+
+// localError converts errors returned by server methods the way gRPC does for remote calls.
+func localError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch err {
+	case context.Canceled:
+		return status.Error(codes.Canceled, err.Error())
+	case context.DeadlineExceeded:
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Convert(err).Err()
+}
+
+type localHealthClient struct {
+	server grpc_health_v1.HealthServer
+}
+
+func (c *localHealthClient) Check(ctx context.Context, in *grpc_health_v1.HealthCheckRequest, _ ...grpc.CallOption) (*grpc_health_v1.HealthCheckResponse, error) {
+	res, err := c.server.Check(ctx, in)
+	return res, localError(err)
+}
+
+// localRegistryClient calls the methods of a registry server directly. Streaming methods
+// run in the background, sending to the returned stream until it's drained or its
+// context is canceled.
+type localRegistryClient struct {
+	server api.RegistryServer
+}
+
+var _ api.RegistryClient = &localRegistryClient{}
+
+func (c *localRegistryClient) GetCatalogInfo(ctx context.Context, in *api.GetCatalogInfoRequest, _ ...grpc.CallOption) (*api.CatalogInfo, error) {
+	res, err := c.server.GetCatalogInfo(ctx, in)
+	return proto.Clone(res).(*api.CatalogInfo), localError(err)
+}
+
+func (c *localRegistryClient) ListPackages(ctx context.Context, in *api.ListPackageRequest, _ ...grpc.CallOption) (api.Registry_ListPackagesClient, error) {
+	stream := newLocalStream(ctx)
+	go stream.run(func() error { return c.server.ListPackages(in, &packageNameServer{stream}) })
+	return &packageNameClient{stream}, nil
+}
+
+func (c *localRegistryClient) GetPackage(ctx context.Context, in *api.GetPackageRequest, _ ...grpc.CallOption) (*api.Package, error) {
+	res, err := c.server.GetPackage(ctx, in)
+	return proto.Clone(res).(*api.Package), localError(err)
+}
+
+func (c *localRegistryClient) GetBundle(ctx context.Context, in *api.GetBundleRequest, _ ...grpc.CallOption) (*api.Bundle, error) {
+	res, err := c.server.GetBundle(ctx, in)
+	return proto.Clone(res).(*api.Bundle), localError(err)
+}
+
+func (c *localRegistryClient) GetBundleForChannel(ctx context.Context, in *api.GetBundleInChannelRequest, _ ...grpc.CallOption) (*api.Bundle, error) {
+	res, err := c.server.GetBundleForChannel(ctx, in)
+	return proto.Clone(res).(*api.Bundle), localError(err)
+}
+
+func (c *localRegistryClient) GetChannelEntriesThatReplace(ctx context.Context, in *api.GetAllReplacementsRequest, _ ...grpc.CallOption) (api.Registry_GetChannelEntriesThatReplaceClient, error) {
+	stream := newLocalStream(ctx)
+	go stream.run(func() error { return c.server.GetChannelEntriesThatReplace(in, &channelEntryServer{stream}) })
+	return &channelEntryClient{stream}, nil
+}
+
+func (c *localRegistryClient) GetBundleThatReplaces(ctx context.Context, in *api.GetReplacementRequest, _ ...grpc.CallOption) (*api.Bundle, error) {
+	res, err := c.server.GetBundleThatReplaces(ctx, in)
+	return proto.Clone(res).(*api.Bundle), localError(err)
+}
+
+func (c *localRegistryClient) GetChannelEntriesThatProvide(ctx context.Context, in *api.GetAllProvidersRequest, _ ...grpc.CallOption) (api.Registry_GetChannelEntriesThatProvideClient, error) {
+	stream := newLocalStream(ctx)
+	go stream.run(func() error { return c.server.GetChannelEntriesThatProvide(in, &channelEntryServer{stream}) })
+	return &channelEntryClient{stream}, nil
+}
+
+func (c *localRegistryClient) GetLatestChannelEntriesThatProvide(ctx context.Context, in *api.GetLatestProvidersRequest, _ ...grpc.CallOption) (api.Registry_GetLatestChannelEntriesThatProvideClient, error) {
+	stream := newLocalStream(ctx)
+	go stream.run(func() error { return c.server.GetLatestChannelEntriesThatProvide(in, &channelEntryServer{stream}) })
+	return &channelEntryClient{stream}, nil
+}
+
+func (c *localRegistryClient) GetDefaultBundleThatProvides(ctx context.Context, in *api.GetDefaultProviderRequest, _ ...grpc.CallOption) (*api.Bundle, error) {
+	res, err := c.server.GetDefaultBundleThatProvides(ctx, in)
+	return proto.Clone(res).(*api.Bundle), localError(err)
+}
+
+func (c *localRegistryClient) ListBundles(ctx context.Context, in *api.ListBundlesRequest, _ ...grpc.CallOption) (api.Registry_ListBundlesClient, error) {
+	stream := newLocalStream(ctx)
+	go stream.run(func() error { return c.server.ListBundles(in, &bundleServer{stream}) })
+	return &bundleClient{stream}, nil
+}
+
+func (c *localRegistryClient) Watch(ctx context.Context, in *api.WatchRequest, _ ...grpc.CallOption) (api.Registry_WatchClient, error) {
+	stream := newLocalStream(ctx)
+	go stream.run(func() error { return c.server.Watch(in, &watchEventServer{stream}) })
+	return &watchEventClient{stream}, nil
+}
+
+// localStream connects the server and client ends of an in-process stream.
+type localStream struct {
+	ctx  context.Context
+	msgs chan proto.Message
+	// err is the result of the streaming method, set before msgs is closed.
+	err error
+}
+
+func newLocalStream(ctx context.Context) *localStream {
+	return &localStream{ctx: ctx, msgs: make(chan proto.Message)}
+}
+
+func (s *localStream) run(method func() error) {
+	s.err = localError(method())
+	close(s.msgs)
+}
+
+func (s *localStream) Context() context.Context { return s.ctx }
+
+// SendMsg implements grpc.ServerStream for the server end. Messages are copied, since
+// servers may send the same message to every caller.
+func (s *localStream) SendMsg(m interface{}) error {
+	select {
+	case s.msgs <- proto.Clone(m.(proto.Message)):
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+// recv receives the next message sent by the server end.
+func (s *localStream) recv() (proto.Message, error) {
+	select {
+	case m, ok := <-s.msgs:
+		if ok {
+			return m, nil
+		}
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	case <-s.ctx.Done():
+		return nil, localError(s.ctx.Err())
+	}
+}
+
+// RecvMsg implements grpc.ClientStream for the client end.
+func (s *localStream) RecvMsg(m interface{}) error {
+	next, err := s.recv()
+	if err != nil {
+		return err
+	}
+	proto.Merge(m.(proto.Message), next)
+	return nil
+}
+
+func (s *localStream) SetHeader(metadata.MD) error  { return nil }
+func (s *localStream) SendHeader(metadata.MD) error { return nil }
+func (s *localStream) SetTrailer(metadata.MD)       {}
+func (s *localStream) Header() (metadata.MD, error) { return metadata.MD{}, nil }
+func (s *localStream) Trailer() metadata.MD         { return metadata.MD{} }
+func (s *localStream) CloseSend() error             { return nil }
+
+type packageNameServer struct{ *localStream }
+
+func (s *packageNameServer) Send(m *api.PackageName) error { return s.SendMsg(m) }
+
+type packageNameClient struct{ *localStream }
+
+func (s *packageNameClient) Recv() (*api.PackageName, error) {
+	m, err := s.recv()
+	if err != nil {
+		return nil, err
+	}
+	return m.(*api.PackageName), nil
+}
+
+type channelEntryServer struct{ *localStream }
+
+func (s *channelEntryServer) Send(m *api.ChannelEntry) error { return s.SendMsg(m) }
+
+type channelEntryClient struct{ *localStream }
+
+func (s *channelEntryClient) Recv() (*api.ChannelEntry, error) {
+	m, err := s.recv()
+	if err != nil {
+		return nil, err
+	}
+	return m.(*api.ChannelEntry), nil
+}
+
+type bundleServer struct{ *localStream }
+
+func (s *bundleServer) Send(m *api.Bundle) error { return s.SendMsg(m) }
+
+type bundleClient struct{ *localStream }
+
+func (s *bundleClient) Recv() (*api.Bundle, error) {
+	m, err := s.recv()
+	if err != nil {
+		return nil, err
+	}
+	return m.(*api.Bundle), nil
+}
+
+type watchEventServer struct{ *localStream }
+
+func (s *watchEventServer) Send(m *api.WatchEvent) error { return s.SendMsg(m) }
+
+type watchEventClient struct{ *localStream }
+
+func (s *watchEventClient) Recv() (*api.WatchEvent, error) {
+	m, err := s.recv()
+	if err != nil {
+		return nil, err
+	}
+	return m.(*api.WatchEvent), nil
+}
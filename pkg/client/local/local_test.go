@@ -0,0 +1,239 @@
+package local
+
+import (
+	"context"
+	"net"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/operator-framework/operator-registry/internal/declcfg"
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/client"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+	"github.com/operator-framework/operator-registry/pkg/server"
+)
+
+const testDeclcfgDir = "../../../internal/action/testdata/foo-index-v0.2.0-declcfg"
+
+// requireEqualResults requires that two calls returned equal messages and status codes.
+func requireEqualResults(t *testing.T, expected, actual proto.Message, expectedErr, actualErr error) {
+	t.Helper()
+	require.Equal(t, status.Convert(expectedErr).Proto().String(), status.Convert(actualErr).Proto().String())
+	require.True(t, proto.Equal(expected, actual), "expected %v, got %v", expected, actual)
+}
+
+func drainChannelEntries(t *testing.T, call func() (*client.ChannelEntryIterator, error)) ([]*api.ChannelEntry, error) {
+	it, err := call()
+	require.NoError(t, err)
+	var entries []*api.ChannelEntry
+	for e := it.Next(); e != nil; e = it.Next() {
+		entries = append(entries, e)
+	}
+	// Streams of the model-backed querier aren't ordered.
+	sort.Slice(entries, func(i, j int) bool { return entries[i].String() < entries[j].String() })
+	return entries, it.Error()
+}
+
+func drainBundles(t *testing.T, c *client.Client) ([]*api.Bundle, error) {
+	it, err := c.ListBundles(context.Background())
+	require.NoError(t, err)
+	var bundles []*api.Bundle
+	for b := it.Next(); b != nil; b = it.Next() {
+		bundles = append(bundles, b)
+	}
+	sort.Slice(bundles, func(i, j int) bool {
+		if bundles[i].GetChannelName() != bundles[j].GetChannelName() {
+			return bundles[i].GetChannelName() < bundles[j].GetChannelName()
+		}
+		return bundles[i].GetCsvName() < bundles[j].GetCsvName()
+	})
+	return bundles, it.Error()
+}
+
+func TestLocalClientMatchesServer(t *testing.T) {
+	local, err := NewClientFromDeclcfg(testDeclcfgDir)
+	require.NoError(t, err)
+	defer local.Close()
+
+	// Serve the same catalog, with the same catalog info, from a real server.
+	info, err := local.GetCatalogInfo(context.Background())
+	require.NoError(t, err)
+	registryServer := local.Registry.(*localRegistryClient).server.(*server.RegistryServer)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := grpc.NewServer()
+	api.RegisterRegistryServer(s, registryServer)
+	go s.Serve(lis)
+	defer s.Stop()
+	remote, err := client.NewClient(lis.Addr().String())
+	require.NoError(t, err)
+	defer remote.Close()
+
+	ctx := context.Background()
+	remoteInfo, err := remote.GetCatalogInfo(ctx)
+	requireEqualResults(t, info, remoteInfo, nil, err)
+	require.Equal(t, int32(1), info.GetPackageCount())
+
+	for _, pkg := range []string{"foo", "missing"} {
+		expected, expectedErr := remote.GetPackage(ctx, pkg)
+		actual, actualErr := local.GetPackage(ctx, pkg)
+		requireEqualResults(t, expected, actual, expectedErr, actualErr)
+	}
+
+	for _, ch := range []string{"beta", "stable", "missing"} {
+		expected, expectedErr := remote.GetBundleInPackageChannel(ctx, "foo", ch)
+		actual, actualErr := local.GetBundleInPackageChannel(ctx, "foo", ch)
+		requireEqualResults(t, expected, actual, expectedErr, actualErr)
+
+		expected, expectedErr = remote.GetBundle(ctx, "foo", ch, "foo.v0.1.0")
+		actual, actualErr = local.GetBundle(ctx, "foo", ch, "foo.v0.1.0")
+		requireEqualResults(t, expected, actual, expectedErr, actualErr)
+
+		expected, expectedErr = remote.GetReplacementBundleInPackageChannel(ctx, "foo.v0.1.0", "foo", ch)
+		actual, actualErr = local.GetReplacementBundleInPackageChannel(ctx, "foo.v0.1.0", "foo", ch)
+		requireEqualResults(t, expected, actual, expectedErr, actualErr)
+	}
+
+	expected, expectedErr := remote.GetBundleThatProvides(ctx, "test.foo", "v1", "Foo")
+	actual, actualErr := local.GetBundleThatProvides(ctx, "test.foo", "v1", "Foo")
+	requireEqualResults(t, expected, actual, expectedErr, actualErr)
+
+	expectedEntries, expectedErr := drainChannelEntries(t, func() (*client.ChannelEntryIterator, error) {
+		return remote.GetChannelEntriesThatProvide(ctx, "test.foo", "v1", "Foo")
+	})
+	actualEntries, actualErr := drainChannelEntries(t, func() (*client.ChannelEntryIterator, error) {
+		return local.GetChannelEntriesThatProvide(ctx, "test.foo", "v1", "Foo")
+	})
+	require.NotEmpty(t, actualEntries)
+	requireEqualResults(t, &api.ChannelEntry{}, &api.ChannelEntry{}, expectedErr, actualErr)
+	require.Equal(t, len(expectedEntries), len(actualEntries))
+	for i := range expectedEntries {
+		requireEqualResults(t, expectedEntries[i], actualEntries[i], nil, nil)
+	}
+
+	expectedEntries, expectedErr = drainChannelEntries(t, func() (*client.ChannelEntryIterator, error) {
+		return remote.GetLatestChannelEntriesThatProvide(ctx, "test.foo", "v1", "Foo")
+	})
+	actualEntries, actualErr = drainChannelEntries(t, func() (*client.ChannelEntryIterator, error) {
+		return local.GetLatestChannelEntriesThatProvide(ctx, "test.foo", "v1", "Foo")
+	})
+	requireEqualResults(t, &api.ChannelEntry{}, &api.ChannelEntry{}, expectedErr, actualErr)
+	require.Equal(t, len(expectedEntries), len(actualEntries))
+	for i := range expectedEntries {
+		requireEqualResults(t, expectedEntries[i], actualEntries[i], nil, nil)
+	}
+
+	expectedEntries, expectedErr = drainChannelEntries(t, func() (*client.ChannelEntryIterator, error) {
+		return remote.GetChannelEntriesThatReplace(ctx, "foo.v0.1.0")
+	})
+	actualEntries, actualErr = drainChannelEntries(t, func() (*client.ChannelEntryIterator, error) {
+		return local.GetChannelEntriesThatReplace(ctx, "foo.v0.1.0")
+	})
+	requireEqualResults(t, &api.ChannelEntry{}, &api.ChannelEntry{}, expectedErr, actualErr)
+	require.Equal(t, len(expectedEntries), len(actualEntries))
+
+	remoteBundles, remoteErr := drainBundles(t, remote)
+	localBundles, localErr := drainBundles(t, local)
+	requireEqualResults(t, nil, nil, remoteErr, localErr)
+	require.Equal(t, len(remoteBundles), len(localBundles))
+	for i := range remoteBundles {
+		requireEqualResults(t, remoteBundles[i], localBundles[i], nil, nil)
+	}
+
+	packages, err := local.ListPackages(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "foo", packages.Next().GetName())
+	require.Nil(t, packages.Next())
+	require.NoError(t, packages.Error())
+
+	// Watches aren't enabled for the catalog.
+	_, remoteErr = drainWatch(t, remote)
+	_, localErr = drainWatch(t, local)
+	requireEqualResults(t, nil, nil, remoteErr, localErr)
+
+	healthy, err := local.HealthCheck(ctx, 0)
+	require.NoError(t, err)
+	require.True(t, healthy)
+}
+
+func drainWatch(t *testing.T, c *client.Client) (*api.WatchEvent, error) {
+	it, err := c.Watch(context.Background(), "")
+	require.NoError(t, err)
+	return it.Next(), it.Error()
+}
+
+func TestLocalClientCanceledStream(t *testing.T) {
+	c, err := NewClientFromDeclcfg(testDeclcfgDir)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it, err := c.ListBundles(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, it.Next())
+	cancel()
+	for it.Next() != nil {
+	}
+	require.Equal(t, "Canceled", status.Code(it.Error()).String())
+}
+
+func TestLocalClientFromSqlite(t *testing.T) {
+	c, err := NewClientFromSqlite("../../lib/indexer/testdata/bundles.db")
+	require.NoError(t, err)
+	defer c.Close()
+
+	info, err := c.GetCatalogInfo(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, server.SourceTypeSqlite, info.GetSourceType())
+
+	packages, err := c.ListPackages(context.Background())
+	require.NoError(t, err)
+	var count int32
+	for p := packages.Next(); p != nil; p = packages.Next() {
+		count++
+		_, err := c.GetPackage(context.Background(), p.GetName())
+		require.NoError(t, err)
+	}
+	require.NoError(t, packages.Error())
+	require.Equal(t, info.GetPackageCount(), count)
+
+	_, err = NewClientFromSqlite("missing.db")
+	require.Error(t, err)
+}
+
+func TestLocalClientReturnsCopies(t *testing.T) {
+	// Callers may modify results, e.g. sorting channels, without changing later results,
+	// even when the server returns the same cached messages to every call.
+	cfg, err := declcfg.LoadFS(os.DirFS(testDeclcfgDir))
+	require.NoError(t, err)
+	m, err := declcfg.ConvertToModel(*cfg)
+	require.NoError(t, err)
+	c := NewClient(registry.NewQuerier(m), server.WithResponseCache())
+	ctx := context.Background()
+
+	pkg, err := c.GetPackage(ctx, "foo")
+	require.NoError(t, err)
+	expected := proto.Clone(pkg)
+	pkg.Channels = nil
+	pkg.DefaultChannelName = "modified"
+	pkg, err = c.GetPackage(ctx, "foo")
+	require.NoError(t, err)
+	require.True(t, proto.Equal(expected, pkg))
+
+	bundles, err := drainBundles(t, c)
+	require.NoError(t, err)
+	require.NotEmpty(t, bundles)
+	for _, b := range bundles {
+		b.CsvName = "modified"
+	}
+	bundles, err = drainBundles(t, c)
+	require.NoError(t, err)
+	for _, b := range bundles {
+		require.NotEqual(t, "modified", b.GetCsvName())
+	}
+}
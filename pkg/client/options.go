@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/operator-framework/operator-registry/pkg/lib/certs"
+)
+
+// watchMethod is excluded from call timeouts, since watches are expected to outlive them.
+const watchMethod = "/api.Registry/Watch"
+
+type clientConfig struct {
+	tls                bool
+	caFile             string
+	certFile           string
+	keyFile            string
+	insecureSkipVerify bool
+
+	retryPolicy *RetryPolicy
+	callTimeout time.Duration
+}
+
+type ClientOption func(*clientConfig)
+
+// WithTLS connects over TLS, trusting the system root CAs and those in caFile, if set.
+func WithTLS(caFile string) ClientOption {
+	return func(c *clientConfig) {
+		c.tls = true
+		c.caFile = caFile
+	}
+}
+
+// WithClientCertificate connects over TLS, presenting the key pair in certFile and keyFile
+// to servers that require client authentication. The key pair is reloaded when it changes.
+func WithClientCertificate(certFile, keyFile string) ClientOption {
+	return func(c *clientConfig) {
+		c.tls = true
+		c.certFile = certFile
+		c.keyFile = keyFile
+	}
+}
+
+// WithInsecureSkipVerify connects over TLS without verifying the server's certificate.
+func WithInsecureSkipVerify() ClientOption {
+	return func(c *clientConfig) {
+		c.tls = true
+		c.insecureSkipVerify = true
+	}
+}
+
+// WithRetryPolicy retries calls that fail with codes.Unavailable according to policy.
+// For streaming calls, only establishing the stream is retried.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *clientConfig) {
+		c.retryPolicy = &policy
+	}
+}
+
+// WithCallTimeout sets a deadline of timeout on every call, including its retries.
+// Streaming calls must be drained before the deadline. Watch calls are exempt.
+func WithCallTimeout(timeout time.Duration) ClientOption {
+	return func(c *clientConfig) {
+		c.callTimeout = timeout
+	}
+}
+
+func (c *clientConfig) dialOptions() ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+	if c.tls {
+		tlsConfig, err := certs.ClientTLSConfig(c.caFile, c.certFile, c.keyFile, c.insecureSkipVerify)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	// The call timeout is applied first so that it bounds all retries.
+	var (
+		unary  []grpc.UnaryClientInterceptor
+		stream []grpc.StreamClientInterceptor
+	)
+	if c.callTimeout > 0 {
+		unary = append(unary, timeoutUnaryInterceptor(c.callTimeout))
+		stream = append(stream, timeoutStreamInterceptor(c.callTimeout))
+	}
+	if c.retryPolicy != nil {
+		unary = append(unary, c.retryPolicy.unaryInterceptor())
+		stream = append(stream, c.retryPolicy.streamInterceptor())
+	}
+	if len(unary) > 0 {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(unary...), grpc.WithChainStreamInterceptor(stream...))
+	}
+	return opts, nil
+}
+
+// RetryPolicy configures how calls that fail with codes.Unavailable are retried, waiting
+// an exponentially increasing backoff between attempts.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts per call, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the time to wait before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the time to wait between attempts.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the backoff grows by after each retry.
+	Multiplier float64
+}
+
+// DefaultRetryPolicy makes up to five attempts over roughly one and a half seconds.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     time.Second,
+	Multiplier:     2,
+}
+
+// backoff returns the time to wait after the given failed attempt, counting from zero.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := float64(p.InitialBackoff)
+	for i := 0; i < attempt && p.Multiplier > 1; i++ {
+		backoff *= p.Multiplier
+		if p.MaxBackoff > 0 && backoff >= float64(p.MaxBackoff) {
+			return p.MaxBackoff
+		}
+	}
+	return time.Duration(backoff)
+}
+
+// retry reports whether attempt should be retried after failing with err, having waited
+// for the backoff.
+func (p RetryPolicy) retry(ctx context.Context, attempt int, err error) bool {
+	if status.Code(err) != codes.Unavailable || attempt+1 >= p.MaxAttempts {
+		return false
+	}
+	timer := time.NewTimer(p.backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (p RetryPolicy) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		for attempt := 0; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if !p.retry(ctx, attempt, err) {
+				return err
+			}
+		}
+	}
+}
+
+func (p RetryPolicy) streamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		for attempt := 0; ; attempt++ {
+			stream, err := streamer(ctx, desc, cc, method, opts...)
+			if !p.retry(ctx, attempt, err) {
+				return stream, err
+			}
+		}
+	}
+}
+
+func timeoutUnaryInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func timeoutStreamInterceptor(timeout time.Duration) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if method == watchMethod {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		return &cancelingStream{ClientStream: stream, cancel: cancel}, nil
+	}
+}
+
+// cancelingStream releases the resources of a stream's context once the stream ends.
+// Streams that are abandoned before then are released by closing their iterator, which
+// cancels the context the stream's was derived from. CloseSend doesn't end streams, since
+// the generated clients of server streaming methods call it as soon as the request is sent.
+type cancelingStream struct {
+	grpc.ClientStream
+	cancel context.CancelFunc
+}
+
+func (s *cancelingStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.cancel()
+	}
+	return err
+}
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+)
+
+// flakyRegistryServer fails the first failures calls to GetPackage with codes.Unavailable,
+// and blocks GetBundle until the call is canceled. ListBundles and Watch send an item and
+// then block until the call is canceled, which is reported on canceled if it's set.
+type flakyRegistryServer struct {
+	api.UnimplementedRegistryServer
+	failures int32
+	calls    int32
+	canceled chan string
+}
+
+func (s *flakyRegistryServer) GetPackage(ctx context.Context, req *api.GetPackageRequest) (*api.Package, error) {
+	if atomic.AddInt32(&s.calls, 1) <= s.failures {
+		return nil, status.Error(codes.Unavailable, "try again")
+	}
+	return &api.Package{Name: req.GetName()}, nil
+}
+
+func (s *flakyRegistryServer) GetBundle(ctx context.Context, req *api.GetBundleRequest) (*api.Bundle, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *flakyRegistryServer) ListPackages(req *api.ListPackageRequest, stream api.Registry_ListPackagesServer) error {
+	return stream.Send(&api.PackageName{Name: "etcd"})
+}
+
+func (s *flakyRegistryServer) ListBundles(req *api.ListBundlesRequest, stream api.Registry_ListBundlesServer) error {
+	if err := stream.Send(&api.Bundle{CsvName: "etcdoperator.v0.9.2"}); err != nil {
+		return err
+	}
+	return s.blockStream(stream.Context(), "ListBundles")
+}
+
+func (s *flakyRegistryServer) Watch(req *api.WatchRequest, stream api.Registry_WatchServer) error {
+	if err := stream.Send(&api.WatchEvent{Type: api.WatchEvent_ADDED}); err != nil {
+		return err
+	}
+	return s.blockStream(stream.Context(), "Watch")
+}
+
+func (s *flakyRegistryServer) blockStream(ctx context.Context, method string) error {
+	<-ctx.Done()
+	if s.canceled != nil {
+		s.canceled <- method
+	}
+	return ctx.Err()
+}
+
+func serveFlaky(t *testing.T, lis net.Listener, srv *flakyRegistryServer) {
+	s := grpc.NewServer()
+	api.RegisterRegistryServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 3}
+	require.Equal(t, 100*time.Millisecond, p.backoff(0))
+	require.Equal(t, 300*time.Millisecond, p.backoff(1))
+	require.Equal(t, 900*time.Millisecond, p.backoff(2))
+	require.Equal(t, time.Second, p.backoff(3))
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Multiplier: 2}
+
+	t.Run("Unary", func(t *testing.T) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		srv := &flakyRegistryServer{failures: 2}
+		serveFlaky(t, lis, srv)
+
+		c, err := NewClient(lis.Addr().String(), WithRetryPolicy(policy))
+		require.NoError(t, err)
+		defer c.Close()
+
+		pkg, err := c.GetPackage(context.Background(), "etcd")
+		require.NoError(t, err)
+		require.Equal(t, "etcd", pkg.GetName())
+		require.Equal(t, int32(3), atomic.LoadInt32(&srv.calls))
+	})
+
+	t.Run("UnaryExhausted", func(t *testing.T) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		srv := &flakyRegistryServer{failures: 3}
+		serveFlaky(t, lis, srv)
+
+		c, err := NewClient(lis.Addr().String(), WithRetryPolicy(policy))
+		require.NoError(t, err)
+		defer c.Close()
+
+		_, err = c.GetPackage(context.Background(), "etcd")
+		require.Equal(t, codes.Unavailable, status.Code(err))
+		require.Equal(t, int32(3), atomic.LoadInt32(&srv.calls))
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		// Reserve an address, but only start serving on it after the client's first attempts have failed.
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := lis.Addr().String()
+		require.NoError(t, lis.Close())
+
+		c, err := NewClient(addr, WithRetryPolicy(RetryPolicy{MaxAttempts: 50, InitialBackoff: 20 * time.Millisecond, MaxBackoff: 100 * time.Millisecond, Multiplier: 2}))
+		require.NoError(t, err)
+		defer c.Close()
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			lis, err := net.Listen("tcp", addr)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			serveFlaky(t, lis, &flakyRegistryServer{})
+		}()
+
+		it, err := c.ListPackages(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "etcd", it.Next().GetName())
+		require.Nil(t, it.Next())
+		require.NoError(t, it.Error())
+	})
+}
+
+func TestWithCallTimeout(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	serveFlaky(t, lis, &flakyRegistryServer{})
+
+	c, err := NewClient(lis.Addr().String(), WithCallTimeout(100*time.Millisecond))
+	require.NoError(t, err)
+	defer c.Close()
+
+	start := time.Now()
+	_, err = c.GetBundle(context.Background(), "etcd", "alpha", "etcdoperator.v0.9.2")
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	require.Less(t, int64(time.Since(start)), int64(5*time.Second))
+
+	it, err := c.ListPackages(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "etcd", it.Next().GetName())
+	require.Nil(t, it.Next())
+	require.NoError(t, it.Error())
+}
+
+func TestAbandonedIterators(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts []ClientOption
+	}{
+		{name: "Default"},
+		{name: "CallTimeout", opts: []ClientOption{WithCallTimeout(time.Hour)}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			srv := &flakyRegistryServer{canceled: make(chan string, 1)}
+			serveFlaky(t, lis, srv)
+
+			c, err := NewClient(lis.Addr().String(), tt.opts...)
+			require.NoError(t, err)
+			defer c.Close()
+
+			requireCanceled := func(t *testing.T, method string) {
+				select {
+				case canceled := <-srv.canceled:
+					require.Equal(t, method, canceled)
+				case <-time.After(10 * time.Second):
+					t.Fatalf("%s stream wasn't canceled", method)
+				}
+			}
+
+			bundles, err := c.ListBundles(context.Background())
+			require.NoError(t, err)
+			require.NotNil(t, bundles.Next())
+			bundles.Close()
+			requireCanceled(t, "ListBundles")
+			require.Nil(t, bundles.Next())
+			require.Equal(t, codes.Canceled, status.Code(bundles.Error()))
+
+			events, err := c.Watch(context.Background(), "")
+			require.NoError(t, err)
+			require.NotNil(t, events.Next())
+			events.Close()
+			requireCanceled(t, "Watch")
+		})
+	}
+}
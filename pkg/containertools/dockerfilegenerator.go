@@ -8,7 +8,7 @@ import (
 )
 
 const (
-	defaultBinarySourceImage = "quay.io/operator-framework/upstream-opm-builder"
+	DefaultBinarySourceImage = "quay.io/operator-framework/upstream-opm-builder"
 	DefaultDbLocation        = "/database/index.db"
 	DbLocationLabel          = "operators.operatorframework.io.index.database.v1"
 )
@@ -36,7 +36,7 @@ func (g *IndexDockerfileGenerator) GenerateIndexDockerfile(binarySourceImage, da
 	var dockerfile string
 
 	if binarySourceImage == "" {
-		binarySourceImage = defaultBinarySourceImage
+		binarySourceImage = DefaultBinarySourceImage
 	}
 
 	g.Logger.Info("Generating dockerfile")
@@ -26,7 +26,8 @@ type ContainerCommandRunner struct {
 }
 
 type RunnerConfig struct {
-	SkipTLS bool
+	SkipTLS  bool
+	Platform string
 }
 
 type RunnerOption func(config *RunnerConfig)
@@ -37,6 +38,13 @@ func SkipTLS(skip bool) RunnerOption {
 	}
 }
 
+// WithPlatform pulls the image of the given platform, such as linux/arm64, from multi-platform images.
+func WithPlatform(platform string) RunnerOption {
+	return func(config *RunnerConfig) {
+		config.Platform = platform
+	}
+}
+
 func (r *RunnerConfig) apply(options []RunnerOption) {
 	for _, option := range options {
 		option(r)
@@ -56,6 +64,9 @@ func (r *ContainerCommandRunner) argsForCmd(cmd string, args ...string) []string
 		}
 	default:
 	}
+	if cmd == "pull" && r.config.Platform != "" {
+		cmdArgs = append(cmdArgs, "--platform", r.config.Platform)
+	}
 	cmdArgs = append(cmdArgs, args...)
 	return cmdArgs
 }
@@ -96,6 +107,24 @@ func (r *ContainerCommandRunner) Pull(image string) error {
 	return nil
 }
 
+// Push takes a container image path and runs the push command to upload it
+// to the container registry it's hosted on
+func (r *ContainerCommandRunner) Push(image string) error {
+	args := r.argsForCmd("push", image)
+
+	command := exec.Command(r.containerTool.String(), args...)
+
+	r.logger.Infof("running %s", command.String())
+
+	out, err := command.CombinedOutput()
+	if err != nil {
+		r.logger.Errorf(string(out))
+		return fmt.Errorf("error pushing image: %s. %v", string(out), err)
+	}
+
+	return nil
+}
+
 // Build takes a dockerfile and a tag and builds a container image
 func (r *ContainerCommandRunner) Build(dockerfile, tag string) error {
 	o := DefaultBuildOptions()
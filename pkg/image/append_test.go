@@ -0,0 +1,151 @@
+package image_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+	libimage "github.com/operator-framework/operator-registry/pkg/lib/image"
+)
+
+func layerWithFile(t *testing.T, name, contents string) io.Reader {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0644, Size: int64(len(contents))}))
+	_, err := tw.Write([]byte(contents))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	return &buf
+}
+
+func readJSON(t *testing.T, path string, v interface{}) {
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, v))
+}
+
+func blobPath(dir string, d digest.Digest) string {
+	return filepath.Join(dir, "blobs", d.Algorithm().String(), d.Encoded())
+}
+
+func TestAppendAndSave(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	host, cafile, err := libimage.RunDockerRegistry(ctx, "testdata/golden")
+	require.NoError(t, err)
+
+	tmp := t.TempDir()
+	r, err := containerdregistry.NewRegistry(
+		containerdregistry.WithLog(logrus.New().WithField("test", t.Name())),
+		containerdregistry.WithCacheDir(filepath.Join(tmp, "cache")),
+		containerdregistry.WithRootCAs(poolForCertFile(t, cafile)),
+	)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, r.Destroy()) }()
+
+	base := image.SimpleReference(host + "/olmtest/kiali:1.4.2")
+	require.NoError(t, r.Pull(ctx, base))
+	baseLabels, err := r.Labels(ctx, base)
+	require.NoError(t, err)
+
+	ref := image.SimpleReference("example.com/appended:v1")
+	require.NoError(t, r.Append(ctx, base, ref, layerWithFile(t, "appended/file.txt", "appended"), func(config *ocispec.ImageConfig) {
+		config.Labels["appended"] = "true"
+		config.Cmd = []string{"serve"}
+	}))
+
+	t.Run("Labels", func(t *testing.T) {
+		labels, err := r.Labels(ctx, ref)
+		require.NoError(t, err)
+		require.Equal(t, "true", labels["appended"])
+		for k, v := range baseLabels {
+			require.Equal(t, v, labels[k])
+		}
+	})
+
+	t.Run("Unpack", func(t *testing.T) {
+		dir := filepath.Join(tmp, "unpacked")
+		require.NoError(t, r.Unpack(ctx, ref, dir))
+		data, err := ioutil.ReadFile(filepath.Join(dir, "appended", "file.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "appended", string(data))
+		require.FileExists(t, filepath.Join(dir, "manifests", "kiali.crd.yaml"))
+	})
+
+	t.Run("OCILayout", func(t *testing.T) {
+		dir := filepath.Join(tmp, "layout")
+		require.NoError(t, r.SaveOCILayout(ctx, ref, dir))
+		// Saving again replaces the image of the same name
+		require.NoError(t, r.SaveOCILayout(ctx, ref, dir))
+		require.NoError(t, r.SaveOCILayout(ctx, base, dir))
+
+		var layout ocispec.ImageLayout
+		readJSON(t, filepath.Join(dir, ocispec.ImageLayoutFile), &layout)
+		require.Equal(t, ocispec.ImageLayoutVersion, layout.Version)
+
+		var index ocispec.Index
+		readJSON(t, filepath.Join(dir, "index.json"), &index)
+		require.Len(t, index.Manifests, 2)
+		desc := index.Manifests[0]
+		require.Equal(t, "v1", desc.Annotations[ocispec.AnnotationRefName])
+		require.Equal(t, "1.4.2", index.Manifests[1].Annotations[ocispec.AnnotationRefName])
+
+		var manifest ocispec.Manifest
+		readJSON(t, blobPath(dir, desc.Digest), &manifest)
+		var config ocispec.Image
+		readJSON(t, blobPath(dir, manifest.Config.Digest), &config)
+		require.Equal(t, []string{"serve"}, config.Config.Cmd)
+		require.Len(t, config.RootFS.DiffIDs, len(manifest.Layers))
+		for _, l := range manifest.Layers {
+			require.Equal(t, ocispec.MediaTypeImageLayerGzip, l.MediaType)
+			require.FileExists(t, blobPath(dir, l.Digest))
+		}
+	})
+
+	t.Run("DockerArchive", func(t *testing.T) {
+		file := filepath.Join(tmp, "archive.tar")
+		require.NoError(t, r.SaveDockerArchive(ctx, ref, file))
+
+		f, err := os.Open(file)
+		require.NoError(t, err)
+		defer f.Close()
+		files := map[string][]byte{}
+		tr := tar.NewReader(f)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			data, err := ioutil.ReadAll(tr)
+			require.NoError(t, err)
+			files[hdr.Name] = data
+		}
+
+		var manifests []struct {
+			Config   string
+			RepoTags []string
+			Layers   []string
+		}
+		require.NoError(t, json.Unmarshal(files["manifest.json"], &manifests))
+		require.Len(t, manifests, 1)
+		require.Equal(t, []string{"example.com/appended:v1"}, manifests[0].RepoTags)
+		require.Contains(t, files, manifests[0].Config)
+		for _, l := range manifests[0].Layers {
+			require.Contains(t, files, l)
+		}
+	})
+}
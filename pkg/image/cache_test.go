@@ -0,0 +1,188 @@
+package image_test
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+	libimage "github.com/operator-framework/operator-registry/pkg/lib/image"
+)
+
+func TestSharedCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	host, cafile, err := libimage.RunDockerRegistry(ctx, "")
+	require.NoError(t, err)
+	rootCAs := poolForCertFile(t, cafile)
+
+	// Serve the registry through a proxy counting blob downloads.
+	var fetched int32
+	target, err := url.Parse("https://" + host)
+	require.NoError(t, err)
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: rootCAs}}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/blobs/") {
+			atomic.AddInt32(&fetched, 1)
+		}
+		proxy.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	rootCAs.AddCert(server.Certificate())
+	proxied := strings.TrimPrefix(server.URL, "https://")
+
+	// Push two images with different content.
+	pusher, err := containerdregistry.NewRegistry(
+		containerdregistry.WithLog(logrus.New().WithField("test", t.Name())),
+		containerdregistry.WithCacheDir(filepath.Join(t.TempDir(), "cache")),
+		containerdregistry.WithRootCAs(rootCAs),
+	)
+	require.NoError(t, err)
+	defer pusher.Destroy()
+	first := image.SimpleReference(host + "/olmtest/cached:first")
+	second := image.SimpleReference(host + "/olmtest/cached:second")
+	for ref, contents := range map[image.Reference]string{first: "first", second: "second"} {
+		_, err := pusher.Pack(ctx, ref, layerWithFile(t, "file.txt", contents))
+		require.NoError(t, err)
+		require.NoError(t, pusher.Push(ctx, ref))
+	}
+	firstProxied := image.SimpleReference(proxied + "/olmtest/cached:first")
+	secondProxied := image.SimpleReference(proxied + "/olmtest/cached:second")
+
+	cache := containerdregistry.NewCache(filepath.Join(t.TempDir(), "shared"))
+	newRegistry := func(t *testing.T, options ...containerdregistry.RegistryOption) *containerdregistry.Registry {
+		r, err := containerdregistry.NewRegistry(append([]containerdregistry.RegistryOption{
+			containerdregistry.WithLog(logrus.New().WithField("test", t.Name())),
+			containerdregistry.WithSharedCacheDir(cache.Dir()),
+			containerdregistry.WithRootCAs(rootCAs),
+		}, options...)...)
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, r.Destroy()) })
+		return r
+	}
+	pullAndUnpack := func(t *testing.T, r *containerdregistry.Registry, ref image.Reference, contents string) {
+		require.NoError(t, r.Pull(ctx, ref))
+		dir := t.TempDir()
+		require.NoError(t, r.Unpack(ctx, ref, dir))
+		requireFileContents(t, filepath.Join(dir, "file.txt"), contents)
+	}
+	cacheSize := func(t *testing.T) (size int64) {
+		entries, err := cache.List()
+		require.NoError(t, err)
+		for _, entry := range entries {
+			size += entry.Size
+		}
+		return size
+	}
+
+	t.Run("ReusesContent", func(t *testing.T) {
+		r := newRegistry(t)
+		pullAndUnpack(t, r, firstProxied, "first")
+		require.NotZero(t, atomic.LoadInt32(&fetched))
+		require.NoError(t, r.Destroy())
+
+		// A registry without the image in its metadata links the cached content rather than fetching it.
+		before := atomic.LoadInt32(&fetched)
+		pullAndUnpack(t, newRegistry(t), firstProxied, "first")
+		require.Equal(t, before, atomic.LoadInt32(&fetched))
+
+		entries, err := cache.List()
+		require.NoError(t, err)
+		// A layer, a config and a manifest.
+		require.Len(t, entries, 3)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			r := newRegistry(t)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for _, ref := range []image.Reference{firstProxied, secondProxied} {
+					if err := r.Pull(ctx, ref); err != nil {
+						t.Error(err)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("PruneWaitsForRegistries", func(t *testing.T) {
+		newRegistry(t)
+		timeoutCtx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+		defer cancel()
+		_, err := cache.Prune(timeoutCtx)
+		require.Error(t, err)
+		require.NotZero(t, cacheSize(t))
+	})
+
+	t.Run("PruneLeastRecentlyUsed", func(t *testing.T) {
+		_, err := cache.Prune(ctx)
+		require.NoError(t, err)
+		require.Zero(t, cacheSize(t))
+
+		r := newRegistry(t)
+		pullAndUnpack(t, r, firstProxied, "first")
+		firstSize := cacheSize(t)
+		time.Sleep(10 * time.Millisecond)
+		pullAndUnpack(t, r, secondProxied, "second")
+		secondSize := cacheSize(t) - firstSize
+		require.NoError(t, r.Destroy())
+
+		// Only the content of the most recently used image fits.
+		pruned, err := cache.Prune(ctx, containerdregistry.PruneToSize(secondSize))
+		require.NoError(t, err)
+		require.Len(t, pruned, 3)
+		require.Equal(t, secondSize, cacheSize(t))
+
+		before := atomic.LoadInt32(&fetched)
+		r = newRegistry(t)
+		pullAndUnpack(t, r, secondProxied, "second")
+		require.Equal(t, before, atomic.LoadInt32(&fetched))
+		pullAndUnpack(t, r, firstProxied, "first")
+		require.Greater(t, atomic.LoadInt32(&fetched), before)
+	})
+
+	t.Run("PruneUnused", func(t *testing.T) {
+		pruned, err := cache.Prune(ctx, containerdregistry.PruneUnusedFor(time.Hour))
+		require.NoError(t, err)
+		require.Empty(t, pruned)
+
+		pruned, err = cache.Prune(ctx)
+		require.NoError(t, err)
+		require.Len(t, pruned, 6)
+		require.Zero(t, cacheSize(t))
+	})
+
+	t.Run("MaxCacheSize", func(t *testing.T) {
+		r := newRegistry(t, containerdregistry.WithMaxCacheSize(1))
+		pullAndUnpack(t, r, firstProxied, "first")
+		require.NotZero(t, cacheSize(t))
+
+		// Eviction is skipped while the cache is in use by other registries.
+		other := newRegistry(t)
+		require.NoError(t, r.Destroy())
+		require.NotZero(t, cacheSize(t))
+
+		require.NoError(t, other.Destroy())
+		r = newRegistry(t, containerdregistry.WithMaxCacheSize(1))
+		require.NoError(t, r.Destroy())
+		require.Zero(t, cacheSize(t))
+	})
+}
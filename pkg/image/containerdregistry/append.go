@@ -0,0 +1,366 @@
+package containerdregistry
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/containerd/containerd/archive/compression"
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/operator-framework/operator-registry/pkg/image"
+)
+
+// Pack creates and stores an image named ref with a layer read from the tar stream from, which may be compressed.
+// If an image named ref is already stored, it's used as the base image of the new one, which replaces it.
+// The returned reference names the new image by digest, so it's unaffected by later changes to ref.
+func (r *Registry) Pack(ctx context.Context, ref image.Reference, from io.Reader) (image.Reference, error) {
+	// Set the default namespace if unset
+	ctx = ensureNamespace(ctx)
+
+	var base image.Reference
+	if _, err := r.Images().Get(ctx, ref.String()); err == nil {
+		base = ref
+	} else if !errdefs.IsNotFound(err) {
+		return nil, err
+	}
+
+	layer, err := compression.DecompressStream(from)
+	if err != nil {
+		return nil, fmt.Errorf("error reading layer: %v", err)
+	}
+	defer layer.Close()
+
+	if err := r.Append(ctx, base, ref, layer, nil); err != nil {
+		return nil, err
+	}
+
+	img, err := r.Images().Get(ctx, ref.String())
+	if err != nil {
+		return nil, err
+	}
+	next, err := digestReference(ref, img.Target.Digest)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.storeImage(ctx, next, img.Target); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// Append stores a new image named ref, made of the layers of the stored image base followed by
+// a layer read from the uncompressed tar stream layer.
+// The new image's config is a copy of base's, changed by update if it isn't nil.
+// If base is nil, the new image is made of the layer alone.
+// If base is a multi-platform image, the layer is appended to the image of each of its stored platforms,
+// and the new image is a multi-platform image of the results.
+func (r *Registry) Append(ctx context.Context, base, ref image.Reference, layer io.Reader, update func(*ocispec.ImageConfig)) error {
+	// Set the default namespace if unset
+	ctx = ensureNamespace(ctx)
+
+	var bases []ocispec.Descriptor
+	if base != nil {
+		var err error
+		if bases, err = r.platformManifests(ctx, base); err != nil {
+			return fmt.Errorf("error getting base image %s: %v", base, err)
+		}
+	}
+
+	layerDesc, diffID, err := r.writeLayer(ctx, layer)
+	if err != nil {
+		return fmt.Errorf("error writing layer: %v", err)
+	}
+
+	if base == nil {
+		target, err := r.appendLayer(ctx, nil, layerDesc, diffID, update)
+		if err != nil {
+			return err
+		}
+		return r.storeImage(ctx, ref, target)
+	}
+
+	var appended []ocispec.Descriptor
+	for _, b := range bases {
+		target, err := r.appendLayer(ctx, &b, layerDesc, diffID, update)
+		if err != nil {
+			return fmt.Errorf("error appending to base image %s: %v", base, err)
+		}
+		appended = append(appended, target)
+	}
+	return r.storeManifests(ctx, ref, appended)
+}
+
+// StoreIndex stores a new multi-platform image named ref, made of the images of each stored platform of images.
+// If there's only one, the new image is that platform's image. Images must not share platforms.
+func (r *Registry) StoreIndex(ctx context.Context, ref image.Reference, images ...image.Reference) error {
+	// Set the default namespace if unset
+	ctx = ensureNamespace(ctx)
+
+	var manifests []ocispec.Descriptor
+	for _, img := range images {
+		m, err := r.platformManifests(ctx, img)
+		if err != nil {
+			return fmt.Errorf("error getting image %s: %v", img, err)
+		}
+		manifests = append(manifests, m...)
+	}
+	return r.storeManifests(ctx, ref, manifests)
+}
+
+// appendLayer writes the manifest and config of the image made of the layers of the image base followed by layer,
+// returning its descriptor with its platform.
+// The descriptor of base must have a platform. If base is nil, the image is made of the layer alone and has the scratch platform.
+func (r *Registry) appendLayer(ctx context.Context, base *ocispec.Descriptor, layer ocispec.Descriptor, diffID digest.Digest, update func(*ocispec.ImageConfig)) (ocispec.Descriptor, error) {
+	manifest := ocispec.Manifest{Versioned: specs.Versioned{SchemaVersion: 2}}
+	platform := r.scratchPlatform
+	config := ocispec.Image{
+		Architecture: r.scratchPlatform.Architecture,
+		OS:           r.scratchPlatform.OS,
+		RootFS:       ocispec.RootFS{Type: "layers"},
+	}
+	if base != nil {
+		var baseManifest ocispec.Manifest
+		if err := readJSON(ctx, r.Content(), *base, &baseManifest); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		baseConfig, err := r.getImage(ctx, baseManifest)
+		if err != nil {
+			return ocispec.Descriptor{}, fmt.Errorf("error getting config: %v", err)
+		}
+		config = *baseConfig
+		platform = *base.Platform
+		for _, l := range baseManifest.Layers {
+			l.MediaType = ociLayerMediaType(l.MediaType)
+			manifest.Layers = append(manifest.Layers, l)
+		}
+	}
+	manifest.Layers = append(manifest.Layers, layer)
+
+	created := time.Now().UTC()
+	config.Created = &created
+	config.RootFS.DiffIDs = append(config.RootFS.DiffIDs, diffID)
+	config.History = append(config.History, ocispec.History{Created: &created})
+	if update != nil {
+		update(&config.Config)
+	}
+
+	var err error
+	if manifest.Config, err = r.writeJSON(ctx, ocispec.MediaTypeImageConfig, config); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("error writing image config: %v", err)
+	}
+	target, err := r.writeJSON(ctx, ocispec.MediaTypeImageManifest, manifest)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("error writing image manifest: %v", err)
+	}
+	target.Platform = &platform
+	return target, nil
+}
+
+// platformManifests returns the descriptors of the image manifests of a stored image, with their platforms:
+// the image's own manifest, or those of the multi-platform image whose content is stored.
+func (r *Registry) platformManifests(ctx context.Context, ref image.Reference) ([]ocispec.Descriptor, error) {
+	img, err := r.Images().Get(ctx, ref.String())
+	if err != nil {
+		return nil, err
+	}
+
+	switch img.Target.MediaType {
+	case images.MediaTypeDockerSchema2ManifestList, ocispec.MediaTypeImageIndex:
+	default:
+		target := img.Target
+		if target.Platform, err = r.manifestPlatform(ctx, target); err != nil {
+			return nil, err
+		}
+		return []ocispec.Descriptor{target}, nil
+	}
+
+	var index ocispec.Index
+	if err := readJSON(ctx, r.Content(), img.Target, &index); err != nil {
+		return nil, err
+	}
+	var manifests []ocispec.Descriptor
+	for _, desc := range index.Manifests {
+		if _, err := r.Content().Info(ctx, desc.Digest); errdefs.IsNotFound(err) {
+			// Only the content of the selected platform is pulled
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if desc.Platform == nil {
+			if desc.Platform, err = r.manifestPlatform(ctx, desc); err != nil {
+				return nil, err
+			}
+		}
+		manifests = append(manifests, desc)
+	}
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("no image of any platform is stored")
+	}
+	return manifests, nil
+}
+
+// manifestPlatform returns the platform of an image manifest, from its config.
+func (r *Registry) manifestPlatform(ctx context.Context, desc ocispec.Descriptor) (*ocispec.Platform, error) {
+	var manifest ocispec.Manifest
+	if err := readJSON(ctx, r.Content(), desc, &manifest); err != nil {
+		return nil, err
+	}
+	config, err := r.getImage(ctx, manifest)
+	if err != nil {
+		return nil, err
+	}
+	return &ocispec.Platform{OS: config.OS, Architecture: config.Architecture}, nil
+}
+
+// storeManifests names the image with the given manifests, replacing any image of the same name.
+// The image is a multi-platform image, unless there's only one manifest.
+func (r *Registry) storeManifests(ctx context.Context, ref image.Reference, manifests []ocispec.Descriptor) error {
+	if len(manifests) == 1 {
+		target := manifests[0]
+		target.Platform = nil
+		return r.storeImage(ctx, ref, target)
+	}
+
+	seen := map[string]struct{}{}
+	for _, desc := range manifests {
+		if desc.Platform == nil {
+			return fmt.Errorf("image %s has no platform", desc.Digest)
+		}
+		platform := platforms.Format(platforms.Normalize(*desc.Platform))
+		if _, ok := seen[platform]; ok {
+			return fmt.Errorf("more than one image for platform %s", platform)
+		}
+		seen[platform] = struct{}{}
+	}
+
+	target, err := r.writeJSON(ctx, ocispec.MediaTypeImageIndex, ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Manifests: manifests,
+	})
+	if err != nil {
+		return fmt.Errorf("error writing image index: %v", err)
+	}
+	return r.storeImage(ctx, ref, target)
+}
+
+// readJSON decodes a stored blob.
+func readJSON(ctx context.Context, provider content.Provider, desc ocispec.Descriptor, v interface{}) error {
+	data, err := content.ReadBlob(ctx, provider, desc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeLayer compresses and stores an uncompressed tar stream, returning its descriptor and diff ID.
+func (r *Registry) writeLayer(ctx context.Context, layer io.Reader) (ocispec.Descriptor, digest.Digest, error) {
+	w, err := content.OpenWriter(ctx, r.Content(), content.WithRef(fmt.Sprintf("layer-%d", time.Now().UnixNano())))
+	if err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	defer w.Close()
+
+	var (
+		compressed = &countingWriter{w: w}
+		digester   = digest.Canonical.Digester()
+		diffIDs    = digest.Canonical.Digester()
+	)
+	gz := gzip.NewWriter(io.MultiWriter(compressed, digester.Hash()))
+	if _, err := io.Copy(io.MultiWriter(gz, diffIDs.Hash()), layer); err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	if err := gz.Close(); err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageLayerGzip,
+		Digest:    digester.Digest(),
+		Size:      compressed.n,
+	}
+	if err := w.Commit(ctx, desc.Size, desc.Digest); err != nil && !errdefs.IsAlreadyExists(err) {
+		return ocispec.Descriptor{}, "", err
+	}
+	return desc, diffIDs.Digest(), nil
+}
+
+// writeJSON stores the JSON encoding of v as a blob of the given media type.
+func (r *Registry) writeJSON(ctx context.Context, mediaType string, v interface{}) (ocispec.Descriptor, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc := ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(data),
+		Size:      int64(len(data)),
+	}
+	if err := content.WriteBlob(ctx, r.Content(), desc.Digest.String(), bytes.NewReader(data), desc); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return desc, nil
+}
+
+// storeImage names the image with the given target, replacing any image of the same name.
+func (r *Registry) storeImage(ctx context.Context, ref image.Reference, target ocispec.Descriptor) error {
+	img := images.Image{
+		Name:   ref.String(),
+		Target: target,
+	}
+	_, err := r.Images().Create(ctx, img)
+	if errdefs.IsAlreadyExists(err) {
+		_, err = r.Images().Update(ctx, img)
+	}
+	return err
+}
+
+// digestReference returns a reference to the image with the given manifest digest in the repository of ref.
+func digestReference(ref image.Reference, dgst digest.Digest) (image.Reference, error) {
+	named, err := reference.ParseNormalizedNamed(ref.String())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing reference %s: %v", ref, err)
+	}
+	digested, err := reference.WithDigest(reference.TrimNamed(named), dgst)
+	if err != nil {
+		return nil, err
+	}
+	return image.SimpleReference(reference.FamiliarString(digested)), nil
+}
+
+// ociLayerMediaType returns the OCI equivalent of a docker layer media type.
+func ociLayerMediaType(mediaType string) string {
+	switch mediaType {
+	case images.MediaTypeDockerSchema2Layer:
+		return ocispec.MediaTypeImageLayer
+	case images.MediaTypeDockerSchema2LayerGzip:
+		return ocispec.MediaTypeImageLayerGzip
+	case images.MediaTypeDockerSchema2LayerForeign:
+		return ocispec.MediaTypeImageLayerNonDistributable
+	case images.MediaTypeDockerSchema2LayerForeignGzip:
+		return ocispec.MediaTypeImageLayerNonDistributableGzip
+	}
+	return mediaType
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,347 @@
+package containerdregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	contentlocal "github.com/containerd/containerd/content/local"
+	"github.com/opencontainers/go-digest"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Cache is a content-addressed store of image content on disk, shared by registries across processes.
+//
+// Registries using a cache keep their image metadata to themselves and share only content,
+// so blobs already in the cache are not fetched again.
+// Registries hold a shared lock on the cache while they are open, and the cache is only pruned under an exclusive lock,
+// so content is never evicted while a registry may be using it.
+// The modification time of a blob records when it was last used, for least-recently-used eviction.
+type Cache struct {
+	dir string
+}
+
+// CacheEntry describes a blob stored in a Cache.
+type CacheEntry struct {
+	Digest   digest.Digest
+	Size     int64
+	LastUsed time.Time
+}
+
+// errLocked is returned when a lock can't be placed on a file without waiting for another holder to release it.
+var errLocked = errors.New("locked by another process")
+
+// NewCache returns the cache stored in a directory, which is created when the cache is first used.
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// DefaultCacheDir returns the directory of the image cache shared by the opm invocations of the current user.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "opm", "images"), nil
+}
+
+// Dir returns the directory the cache is stored in.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// List returns the blobs stored in the cache, most recently used first.
+func (c *Cache) List() ([]CacheEntry, error) {
+	entries, err := c.entries()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].LastUsed.After(entries[j].LastUsed)
+	})
+	return entries, nil
+}
+
+// PruneOption limits the blobs evicted by Prune.
+type PruneOption func(config *pruneConfig)
+
+type pruneConfig struct {
+	maxSize   int64
+	unusedFor time.Duration
+}
+
+// PruneToSize evicts the least recently used blobs until the cache is no larger than size bytes.
+func PruneToSize(size int64) PruneOption {
+	return func(config *pruneConfig) {
+		config.maxSize = size
+	}
+}
+
+// PruneUnusedFor evicts the blobs that haven't been used for at least d.
+func PruneUnusedFor(d time.Duration) PruneOption {
+	return func(config *pruneConfig) {
+		config.unusedFor = d
+	}
+}
+
+// Prune evicts blobs from the cache and returns them. Without options, every blob is evicted.
+// Prune waits for the registries using the cache to be destroyed, unless ctx is done first.
+func (c *Cache) Prune(ctx context.Context, options ...PruneOption) ([]CacheEntry, error) {
+	config := &pruneConfig{maxSize: -1}
+	for _, option := range options {
+		option(config)
+	}
+
+	var lock *os.File
+	err := wait.PollImmediateUntil(100*time.Millisecond, func() (bool, error) {
+		var err error
+		lock, err = c.lock(true, false)
+		if err == errLocked {
+			return false, nil
+		}
+		return err == nil, err
+	}, ctx.Done())
+	if err == wait.ErrWaitTimeout {
+		return nil, fmt.Errorf("error waiting for the cache to be unused: %v", ctx.Err())
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer unlock(lock)
+
+	return c.prune(config)
+}
+
+// tryPrune evicts the least recently used blobs until the cache is no larger than maxSize bytes,
+// unless the cache is in use, in which case errLocked is returned.
+func (c *Cache) tryPrune(maxSize int64) ([]CacheEntry, error) {
+	lock, err := c.lock(true, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock(lock)
+
+	return c.prune(&pruneConfig{maxSize: maxSize})
+}
+
+// prune evicts blobs from the cache. The caller must hold an exclusive lock on the cache.
+func (c *Cache) prune(config *pruneConfig) ([]CacheEntry, error) {
+	// Nothing else is using the cache, so the metadata of registries and ingests left behind are stale.
+	for _, dir := range []string{c.registriesDir(), filepath.Join(c.contentDir(), "ingest")} {
+		if err := os.RemoveAll(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := c.entries()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].LastUsed.Before(entries[j].LastUsed)
+	})
+
+	var size int64
+	for _, entry := range entries {
+		size += entry.Size
+	}
+	all := config.maxSize < 0 && config.unusedFor <= 0
+	cutoff := time.Now().Add(-config.unusedFor)
+
+	var pruned []CacheEntry
+	for _, entry := range entries {
+		unused := config.unusedFor > 0 && entry.LastUsed.Before(cutoff)
+		tooLarge := config.maxSize >= 0 && size > config.maxSize
+		if !all && !unused && !tooLarge {
+			continue
+		}
+		if err := os.Remove(c.blobPath(entry.Digest)); err != nil && !os.IsNotExist(err) {
+			return pruned, fmt.Errorf("error evicting %s: %v", entry.Digest, err)
+		}
+		size -= entry.Size
+		pruned = append(pruned, entry)
+	}
+
+	return pruned, nil
+}
+
+// entries returns the blobs stored in the cache.
+func (c *Cache) entries() ([]CacheEntry, error) {
+	root := filepath.Join(c.contentDir(), "blobs")
+	algs, err := ioutil.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []CacheEntry
+	for _, alg := range algs {
+		if !alg.IsDir() {
+			continue
+		}
+		blobs, err := ioutil.ReadDir(filepath.Join(root, alg.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, blob := range blobs {
+			dgst := digest.NewDigestFromEncoded(digest.Algorithm(alg.Name()), blob.Name())
+			if blob.IsDir() || dgst.Validate() != nil {
+				continue
+			}
+			entries = append(entries, CacheEntry{
+				Digest:   dgst,
+				Size:     blob.Size(),
+				LastUsed: blob.ModTime(),
+			})
+		}
+	}
+	return entries, nil
+}
+
+// open locks the cache for use by a registry, returning a content store backed by the cache,
+// a directory for the registry's metadata, and a function to release them when the registry is destroyed.
+func (c *Cache) open() (cs content.Store, dir string, release func() error, err error) {
+	lock, err := c.lock(false, true)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	defer func() {
+		if err != nil {
+			unlock(lock)
+		}
+	}()
+
+	if err = os.MkdirAll(c.registriesDir(), os.ModePerm); err != nil {
+		return
+	}
+	if dir, err = ioutil.TempDir(c.registriesDir(), "registry-"); err != nil {
+		return
+	}
+
+	local, err := contentlocal.NewStore(c.contentDir())
+	if err != nil {
+		os.RemoveAll(dir)
+		return
+	}
+
+	release = func() error {
+		err := os.RemoveAll(dir)
+		if uerr := unlock(lock); err == nil {
+			err = uerr
+		}
+		return err
+	}
+	return &prefixedStore{Store: local, prefix: filepath.Base(dir) + "/"}, dir, release, nil
+}
+
+// touch records that a blob was used.
+func (c *Cache) touch(dgst digest.Digest) error {
+	now := time.Now()
+	if err := os.Chtimes(c.blobPath(dgst), now, now); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// lock opens the lock file of the cache, creating the cache directory if needed, and places a lock on it.
+func (c *Cache) lock(exclusive, wait bool) (*os.File, error) {
+	if err := os.MkdirAll(c.dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(c.dir, "lock"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f, exclusive, wait); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func unlock(f *os.File) error {
+	err := unlockFile(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (c *Cache) contentDir() string {
+	return filepath.Join(c.dir, "content")
+}
+
+func (c *Cache) registriesDir() string {
+	return filepath.Join(c.dir, "registries")
+}
+
+func (c *Cache) blobPath(dgst digest.Digest) string {
+	return filepath.Join(c.contentDir(), "blobs", dgst.Algorithm().String(), dgst.Encoded())
+}
+
+// prefixedStore is a content store whose ingests are namespaced by a prefix.
+// Registries sharing a cache keep separate metadata, which would otherwise name their ingests alike,
+// so that concurrent writes of the same content by different processes would clash.
+type prefixedStore struct {
+	content.Store
+	prefix string
+}
+
+func (s *prefixedStore) Writer(ctx context.Context, opts ...content.WriterOpt) (content.Writer, error) {
+	var wOpts content.WriterOpts
+	for _, opt := range opts {
+		if err := opt(&wOpts); err != nil {
+			return nil, err
+		}
+	}
+	w, err := s.Store.Writer(ctx, content.WithRef(s.prefix+wOpts.Ref), content.WithDescriptor(wOpts.Desc))
+	if err != nil {
+		return nil, err
+	}
+	return &prefixedWriter{Writer: w, prefix: s.prefix}, nil
+}
+
+func (s *prefixedStore) Status(ctx context.Context, ref string) (content.Status, error) {
+	status, err := s.Store.Status(ctx, s.prefix+ref)
+	status.Ref = strings.TrimPrefix(status.Ref, s.prefix)
+	return status, err
+}
+
+func (s *prefixedStore) ListStatuses(ctx context.Context, filters ...string) ([]content.Status, error) {
+	all, err := s.Store.ListStatuses(ctx, filters...)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []content.Status
+	for _, status := range all {
+		if strings.HasPrefix(status.Ref, s.prefix) {
+			status.Ref = strings.TrimPrefix(status.Ref, s.prefix)
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, nil
+}
+
+func (s *prefixedStore) Abort(ctx context.Context, ref string) error {
+	return s.Store.Abort(ctx, s.prefix+ref)
+}
+
+type prefixedWriter struct {
+	content.Writer
+	prefix string
+}
+
+func (w *prefixedWriter) Status() (content.Status, error) {
+	status, err := w.Writer.Status()
+	status.Ref = strings.TrimPrefix(status.Ref, w.prefix)
+	return status, err
+}
@@ -0,0 +1,157 @@
+package containerdregistry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/images/archive"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/operator-framework/operator-registry/pkg/image"
+)
+
+// load stores an image from the local filesystem, referenced by oci:<dir>[:tag] or docker-archive:<file>.
+// The image is named by its full reference, so it can be unpacked and labeled like a pulled image.
+func (r *Registry) load(ctx context.Context, ref image.Reference) error {
+	var (
+		target ocispec.Descriptor
+		err    error
+	)
+	switch s := ref.String(); {
+	case strings.HasPrefix(s, image.OCILayoutPrefix):
+		dir, tag := splitOCILayoutReference(strings.TrimPrefix(s, image.OCILayoutPrefix))
+		target, err = r.loadOCILayout(ctx, dir, tag)
+	case strings.HasPrefix(s, image.DockerArchivePrefix):
+		target, err = r.loadDockerArchive(ctx, strings.TrimPrefix(s, image.DockerArchivePrefix))
+	default:
+		return fmt.Errorf("%s is not a reference to a local image", ref)
+	}
+	if err != nil {
+		return fmt.Errorf("error loading %s: %v", ref, err)
+	}
+	if err := r.markUsed(ctx, target); err != nil {
+		return err
+	}
+
+	return r.storeImage(ctx, ref, target)
+}
+
+// loadOCILayout copies an image and its content from an OCI image layout directory into the content store,
+// returning the image's descriptor.
+// The image is selected by its tag, or is the only image of the layout if tag is empty.
+func (r *Registry) loadOCILayout(ctx context.Context, dir, tag string) (ocispec.Descriptor, error) {
+	var index ocispec.Index
+	data, err := ioutil.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("error parsing index.json: %v", err)
+	}
+
+	target, err := selectManifest(index.Manifests, func(desc ocispec.Descriptor) bool {
+		return tag == "" || desc.Annotations[ocispec.AnnotationRefName] == tag
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	copyBlobs := images.HandlerFunc(func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		if err := r.loadBlob(ctx, desc, dir); err != nil {
+			return nil, fmt.Errorf("error loading blob %s: %v", desc.Digest, err)
+		}
+		return images.Children(ctx, r.Content(), desc)
+	})
+	if err := images.Dispatch(ctx, r.filterPlatforms(copyBlobs), nil, target); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return target, nil
+}
+
+// loadDockerArchive imports the content of a tarball written by `docker save` into the content store,
+// returning the descriptor of the only image it contains.
+func (r *Registry) loadDockerArchive(ctx context.Context, file string) (ocispec.Descriptor, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer f.Close()
+
+	desc, err := archive.ImportIndex(ctx, r.Content(), f)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	var index ocispec.Index
+	data, err := content.ReadBlob(ctx, r.Content(), desc)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	return selectManifest(index.Manifests, func(ocispec.Descriptor) bool { return true })
+}
+
+// loadBlob copies a blob from the blobs directory of an OCI image layout into the content store, unless it's already there.
+func (r *Registry) loadBlob(ctx context.Context, desc ocispec.Descriptor, dir string) error {
+	if _, err := r.Content().Info(ctx, desc.Digest); err == nil {
+		return nil
+	}
+
+	f, err := os.Open(filepath.Join(dir, "blobs", desc.Digest.Algorithm().String(), desc.Digest.Encoded()))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := content.WriteBlob(ctx, r.Content(), desc.Digest.String(), f, desc); err != nil && !errdefs.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// selectManifest returns the only image descriptor of manifests matching match.
+// Descriptors with the same digest, such as several tags of the same image, are the same image.
+func selectManifest(manifests []ocispec.Descriptor, match func(ocispec.Descriptor) bool) (ocispec.Descriptor, error) {
+	var selected []ocispec.Descriptor
+	seen := map[digest.Digest]struct{}{}
+	for _, desc := range manifests {
+		if _, ok := seen[desc.Digest]; ok || !match(desc) {
+			continue
+		}
+		seen[desc.Digest] = struct{}{}
+		selected = append(selected, desc)
+	}
+
+	switch len(selected) {
+	case 0:
+		return ocispec.Descriptor{}, fmt.Errorf("image not found")
+	case 1:
+		return selected[0], nil
+	}
+	var names []string
+	for _, desc := range selected {
+		names = append(names, desc.Annotations[ocispec.AnnotationRefName])
+	}
+	return ocispec.Descriptor{}, fmt.Errorf("found %d images %v, expected one", len(selected), names)
+}
+
+// splitOCILayoutReference splits the <dir>[:tag] part of an OCI image layout reference.
+// The tag is what follows the last colon, unless it contains a path separator, since the directory may contain colons.
+func splitOCILayoutReference(ref string) (dir, tag string) {
+	i := strings.LastIndex(ref, ":")
+	if i < 0 || strings.ContainsAny(ref[i+1:], `/\`) {
+		return ref, ""
+	}
+	return ref[:i], ref[i+1:]
+}
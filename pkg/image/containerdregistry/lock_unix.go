@@ -0,0 +1,39 @@
+// +build !windows
+
+package containerdregistry
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// lockFile places an advisory lock on a file, shared unless exclusive is set.
+// If wait is false and the lock is held elsewhere, errLocked is returned rather than waiting for it.
+func lockFile(f *os.File, exclusive, wait bool) error {
+	how := unix.LOCK_SH
+	if exclusive {
+		how = unix.LOCK_EX
+	}
+	if !wait {
+		how |= unix.LOCK_NB
+	}
+
+	for {
+		err := unix.Flock(int(f.Fd()), how)
+		switch err {
+		case nil:
+			return nil
+		case unix.EINTR:
+			continue
+		case unix.EWOULDBLOCK:
+			return errLocked
+		}
+		return err
+	}
+}
+
+// unlockFile releases a lock placed by lockFile.
+func unlockFile(f *os.File) error {
+	return unix.Flock(int(f.Fd()), unix.LOCK_UN)
+}
@@ -0,0 +1,32 @@
+// +build windows
+
+package containerdregistry
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFile places a lock on a file, shared unless exclusive is set.
+// If wait is false and the lock is held elsewhere, errLocked is returned rather than waiting for it.
+func lockFile(f *os.File, exclusive, wait bool) error {
+	var flags uint32
+	if exclusive {
+		flags |= windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+	if !wait {
+		flags |= windows.LOCKFILE_FAIL_IMMEDIATELY
+	}
+
+	err := windows.LockFileEx(windows.Handle(f.Fd()), flags, 0, 1, 0, &windows.Overlapped{})
+	if err == windows.ERROR_LOCK_VIOLATION {
+		return errLocked
+	}
+	return err
+}
+
+// unlockFile releases a lock placed by lockFile.
+func unlockFile(f *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, &windows.Overlapped{})
+}
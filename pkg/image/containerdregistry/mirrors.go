@@ -0,0 +1,245 @@
+package containerdregistry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/containerd/containerd/remotes"
+	"github.com/docker/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// RegistriesConfigEnv is the environment variable holding the path of the registries config,
+// as read by other tools of the containers ecosystem.
+const RegistriesConfigEnv = "CONTAINERS_REGISTRIES_CONF"
+
+// registriesConfig is the part of a containers-registries.conf(5) file, version 2, about where images are pulled from.
+type registriesConfig struct {
+	Registries []registryConfig `toml:"registry"`
+}
+
+// registryConfig configures the images whose names match a prefix.
+type registryConfig struct {
+	// Prefix is the name prefix of the images configured, such as quay.io/operator-framework.
+	// A prefix beginning with *. matches every subdomain of a host, such as *.example.com.
+	Prefix string `toml:"prefix"`
+	// Location replaces the prefix in the names images are pulled from, if set.
+	Location string `toml:"location"`
+	// Insecure allows unverified TLS and plain HTTP connections to the location.
+	Insecure bool `toml:"insecure"`
+	// Blocked rejects pulling and pushing the images.
+	Blocked bool `toml:"blocked"`
+	// MirrorByDigestOnly only pulls images referenced by digest from mirrors.
+	MirrorByDigestOnly bool `toml:"mirror-by-digest-only"`
+	// Mirrors are tried in order before the location.
+	Mirrors []mirrorConfig `toml:"mirror"`
+}
+
+// mirrorConfig is a location images are pulled from before the location of their registry.
+type mirrorConfig struct {
+	Location string `toml:"location"`
+	Insecure bool   `toml:"insecure"`
+}
+
+// defaultRegistriesConfigPath returns the path of the registries config used by default, or an empty path if there is none.
+// Like other tools of the containers ecosystem, this is the path set by CONTAINERS_REGISTRIES_CONF,
+// or the registries.conf file of the user's or of the system's containers config.
+func defaultRegistriesConfigPath() string {
+	if path, ok := os.LookupEnv(RegistriesConfigEnv); ok {
+		return path
+	}
+
+	var paths []string
+	if dir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, "containers", "registries.conf"))
+	}
+	paths = append(paths, "/etc/containers/registries.conf")
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// loadRegistriesConfig reads a registries config, or its default, if path is empty.
+func loadRegistriesConfig(path string) (*registriesConfig, error) {
+	config := &registriesConfig{}
+	if path == "" {
+		if path = defaultRegistriesConfigPath(); path == "" {
+			return config, nil
+		}
+	}
+
+	if _, err := toml.DecodeFile(path, config); err != nil {
+		return nil, fmt.Errorf("error reading registries config %s: %v", path, err)
+	}
+	if err := config.complete(); err != nil {
+		return nil, fmt.Errorf("invalid registries config %s: %v", path, err)
+	}
+	return config, nil
+}
+
+func (c *registriesConfig) complete() error {
+	seen := map[string]struct{}{}
+	for i := range c.Registries {
+		reg := &c.Registries[i]
+		if reg.Prefix == "" {
+			reg.Prefix = reg.Location
+		}
+		reg.Prefix = strings.TrimSuffix(reg.Prefix, "/")
+		reg.Location = strings.TrimSuffix(reg.Location, "/")
+		if reg.Prefix == "" {
+			return fmt.Errorf("registry %d has neither a prefix nor a location", i)
+		}
+		if strings.HasPrefix(reg.Prefix, "*.") && reg.Location != "" {
+			return fmt.Errorf("registry with wildcard prefix %s can't have a location", reg.Prefix)
+		}
+		if _, ok := seen[reg.Prefix]; ok {
+			return fmt.Errorf("registry prefix %s is configured more than once", reg.Prefix)
+		}
+		seen[reg.Prefix] = struct{}{}
+		for j, mirror := range reg.Mirrors {
+			if mirror.Location == "" {
+				return fmt.Errorf("mirror %d of registry %s has no location", j, reg.Prefix)
+			}
+			reg.Mirrors[j].Location = strings.TrimSuffix(mirror.Location, "/")
+		}
+	}
+
+	// Match the longest prefixes first.
+	sort.SliceStable(c.Registries, func(i, j int) bool {
+		return len(c.Registries[i].Prefix) > len(c.Registries[j].Prefix)
+	})
+	return nil
+}
+
+// find returns the config of the registry of an image name, or nil if the name isn't configured.
+func (c *registriesConfig) find(name string) *registryConfig {
+	for i, reg := range c.Registries {
+		if reg.matches(name) {
+			return &c.Registries[i]
+		}
+	}
+	return nil
+}
+
+// insecure returns true if a host is the location of an insecure registry or mirror.
+func (c *registriesConfig) insecure(host string) bool {
+	for _, reg := range c.Registries {
+		if reg.Insecure && (locationHost(reg.location()) == host || reg.matches(host)) {
+			return true
+		}
+		for _, mirror := range reg.Mirrors {
+			if mirror.Insecure && locationHost(mirror.Location) == host {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *registryConfig) matches(name string) bool {
+	if strings.HasPrefix(r.Prefix, "*.") {
+		return strings.HasSuffix(locationHost(name), r.Prefix[1:])
+	}
+	return name == r.Prefix || strings.HasPrefix(name, r.Prefix+"/")
+}
+
+// location returns the location images of the registry are pulled from, if not from a mirror.
+func (r *registryConfig) location() string {
+	if r.Location == "" {
+		return r.Prefix
+	}
+	return r.Location
+}
+
+// rewrite replaces the prefix of an image name matched by the registry with a location.
+func (r *registryConfig) rewrite(name, location string) string {
+	if strings.HasPrefix(r.Prefix, "*.") {
+		return name
+	}
+	return location + strings.TrimPrefix(name, r.Prefix)
+}
+
+// sources returns the references an image is pulled from, in order.
+func (c *registriesConfig) sources(ref string) ([]string, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		// Leave unparseable references to the resolver to reject.
+		return []string{ref}, nil
+	}
+	reg := c.find(named.Name())
+	if reg == nil {
+		return []string{ref}, nil
+	}
+	if reg.Blocked {
+		return nil, fmt.Errorf("registry %s is blocked", reg.Prefix)
+	}
+
+	var suffix string
+	if tagged, ok := named.(reference.Tagged); ok {
+		suffix += ":" + tagged.Tag()
+	}
+	digested, isDigested := named.(reference.Digested)
+	if isDigested {
+		suffix += "@" + digested.Digest().String()
+	}
+
+	var sources []string
+	if isDigested || !reg.MirrorByDigestOnly {
+		for _, mirror := range reg.Mirrors {
+			sources = append(sources, reg.rewrite(named.Name(), mirror.Location)+suffix)
+		}
+	}
+	return append(sources, reg.rewrite(named.Name(), reg.location())+suffix), nil
+}
+
+// locationHost returns the host of a location, such as quay.io for quay.io/operator-framework.
+func locationHost(location string) string {
+	return strings.SplitN(location, "/", 2)[0]
+}
+
+// mirrorResolver resolves images from the mirrors and locations of a registries config.
+type mirrorResolver struct {
+	remotes.Resolver
+	config *registriesConfig
+}
+
+// Resolve resolves an image from the first of its sources that has it,
+// returning the name of that source so the image is fetched from there.
+func (r *mirrorResolver) Resolve(ctx context.Context, ref string) (string, ocispec.Descriptor, error) {
+	sources, err := r.config.sources(ref)
+	if err != nil {
+		return "", ocispec.Descriptor{}, err
+	}
+	if len(sources) == 1 {
+		return r.Resolver.Resolve(ctx, sources[0])
+	}
+
+	var errs []error
+	for _, source := range sources {
+		name, desc, err := r.Resolver.Resolve(ctx, source)
+		if err == nil {
+			return name, desc, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %v", source, err))
+	}
+	return "", ocispec.Descriptor{}, utilerrors.NewAggregate(errs)
+}
+
+// Pusher returns a pusher for an image, unless its registry is blocked. Images are never pushed to mirrors.
+func (r *mirrorResolver) Pusher(ctx context.Context, ref string) (remotes.Pusher, error) {
+	if named, err := reference.ParseNormalizedNamed(ref); err == nil {
+		if reg := r.config.find(named.Name()); reg != nil && reg.Blocked {
+			return nil, fmt.Errorf("registry %s is blocked", reg.Prefix)
+		}
+	}
+	return r.Resolver.Pusher(ctx, ref)
+}
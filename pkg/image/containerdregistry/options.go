@@ -2,10 +2,13 @@ package containerdregistry
 
 import (
 	"crypto/x509"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sync"
 
+	"github.com/containerd/containerd/content"
 	contentlocal "github.com/containerd/containerd/content/local"
 	"github.com/containerd/containerd/metadata"
 	"github.com/containerd/containerd/platforms"
@@ -23,6 +26,12 @@ type RegistryConfig struct {
 	PreserveCache     bool
 	SkipTLS           bool
 	Roots             *x509.CertPool
+	Platform          string
+	AllPlatforms      bool
+	SharedCacheDir    string
+	MaxCacheSize      int64
+	RegistriesConfig  string
+	CertsDir          string
 }
 
 func (r *RegistryConfig) apply(options []RegistryOption) {
@@ -32,12 +41,24 @@ func (r *RegistryConfig) apply(options []RegistryOption) {
 }
 
 func (r *RegistryConfig) complete() error {
-	if err := os.Mkdir(r.CacheDir, os.ModePerm); err != nil && !os.IsExist(err) {
-		return err
+	if r.SharedCacheDir == "" {
+		if err := os.Mkdir(r.CacheDir, os.ModePerm); err != nil && !os.IsExist(err) {
+			return err
+		}
+
+		if r.DBPath == "" {
+			r.DBPath = filepath.Join(r.CacheDir, "metadata.db")
+		}
+	}
+
+	if r.MaxCacheSize < 0 {
+		return fmt.Errorf("invalid max cache size %d", r.MaxCacheSize)
 	}
 
-	if r.DBPath == "" {
-		r.DBPath = filepath.Join(r.CacheDir, "metadata.db")
+	if r.Platform != "" {
+		if _, err := platforms.Parse(r.Platform); err != nil {
+			return fmt.Errorf("invalid platform %q: %v", r.Platform, err)
+		}
 	}
 
 	return nil
@@ -62,14 +83,34 @@ func NewRegistry(options ...RegistryOption) (registry *Registry, err error) {
 		return
 	}
 
-	cs, err := contentlocal.NewStore(config.CacheDir)
+	var resolver remotes.Resolver
+	resolver, err = newResolver(config)
 	if err != nil {
 		return
 	}
 
+	var (
+		cs      content.Store
+		cache   *Cache
+		release = func() error { return nil }
+	)
+	if config.SharedCacheDir != "" {
+		cache = NewCache(config.SharedCacheDir)
+		var dir string
+		if cs, dir, release, err = cache.open(); err != nil {
+			return
+		}
+		if config.DBPath == "" {
+			config.DBPath = filepath.Join(dir, "metadata.db")
+		}
+	} else if cs, err = contentlocal.NewStore(config.CacheDir); err != nil {
+		return
+	}
+
 	var bdb *bolt.DB
 	bdb, err = bolt.Open(config.DBPath, 0644, nil)
 	if err != nil {
+		release()
 		return
 	}
 
@@ -79,6 +120,20 @@ func NewRegistry(options ...RegistryOption) (registry *Registry, err error) {
 			if destroyErr = bdb.Close(); destroyErr != nil {
 				return
 			}
+			if cache != nil {
+				if destroyErr = release(); destroyErr != nil || config.MaxCacheSize == 0 {
+					return
+				}
+				// Evict content beyond the size limit, unless other registries are still using the cache.
+				pruned, err := cache.tryPrune(config.MaxCacheSize)
+				if err == errLocked {
+					config.Log.Debugf("cache %s is in use, skipping eviction", cache.Dir())
+					return
+				}
+				config.Log.Debugf("evicted %d blobs from cache %s", len(pruned), cache.Dir())
+				destroyErr = err
+				return
+			}
 			if config.PreserveCache {
 				return
 			}
@@ -89,12 +144,6 @@ func NewRegistry(options ...RegistryOption) (registry *Registry, err error) {
 		return
 	}
 
-	var resolver remotes.Resolver
-	resolver, err = NewResolver(config.ResolverConfigDir, config.SkipTLS, config.Roots)
-	if err != nil {
-		return
-	}
-
 	registry = &Registry{
 		Store:    newStore(metadata.NewDB(bdb, cs, nil)),
 		destroy:  destroy,
@@ -104,6 +153,16 @@ func NewRegistry(options ...RegistryOption) (registry *Registry, err error) {
 			OS:           "linux",
 			Architecture: "amd64",
 		}),
+		scratchPlatform: specs.Platform{
+			OS:           "linux",
+			Architecture: runtime.GOARCH,
+		},
+		allPlatforms: config.AllPlatforms,
+		cache:        cache,
+	}
+	if config.Platform != "" {
+		registry.scratchPlatform = platforms.Normalize(platforms.MustParse(config.Platform))
+		registry.platform = platforms.Only(registry.scratchPlatform)
 	}
 	return
 }
@@ -145,3 +204,56 @@ func SkipTLS(skip bool) RegistryOption {
 		config.SkipTLS = skip
 	}
 }
+
+// WithPlatform selects the image of the given platform, such as linux/arm64, from multi-platform images,
+// and is the platform of images created from scratch.
+// By default, the platform of the host is selected, falling back to linux/amd64.
+func WithPlatform(platform string) RegistryOption {
+	return func(config *RegistryConfig) {
+		config.Platform = platform
+	}
+}
+
+// WithAllPlatforms keeps the images of every platform when multi-platform images are pulled, rather than those of the selected platform.
+func WithAllPlatforms() RegistryOption {
+	return func(config *RegistryConfig) {
+		config.AllPlatforms = true
+	}
+}
+
+// WithSharedCacheDir stores image content in a Cache in the given directory, rather than in the cache directory of the registry.
+// The cache persists after the registry is destroyed and can be shared by concurrent registries and processes,
+// so content pulled before isn't fetched again.
+func WithSharedCacheDir(dir string) RegistryOption {
+	return func(config *RegistryConfig) {
+		config.SharedCacheDir = dir
+	}
+}
+
+// WithMaxCacheSize limits the size of the shared cache, in bytes.
+// When the registry is destroyed, the least recently used content is evicted from the cache until it's within the limit,
+// unless the cache is still in use by other registries. By default, the size of the cache is unlimited.
+func WithMaxCacheSize(size int64) RegistryOption {
+	return func(config *RegistryConfig) {
+		config.MaxCacheSize = size
+	}
+}
+
+// WithRegistriesConfig pulls images from the mirrors and locations configured by a containers-registries.conf(5) file,
+// and rejects the images of blocked registries.
+// By default, the file set by the CONTAINERS_REGISTRIES_CONF environment variable is read,
+// or else the registries.conf file of the user's or the system's containers config, if any.
+func WithRegistriesConfig(path string) RegistryOption {
+	return func(config *RegistryConfig) {
+		config.RegistriesConfig = path
+	}
+}
+
+// WithCertsDir trusts the CA certificates in the *.crt files of the <host[:port]> subdirectories of dir
+// when connecting to the matching registry hosts, as in containers-certs.d(5).
+// By default, /etc/containers/certs.d and /etc/docker/certs.d are read.
+func WithCertsDir(dir string) RegistryOption {
+	return func(config *RegistryConfig) {
+		config.CertsDir = dir
+	}
+}
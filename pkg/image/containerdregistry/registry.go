@@ -34,6 +34,13 @@ type Registry struct {
 	log      *logrus.Entry
 	resolver remotes.Resolver
 	platform platforms.MatchComparer
+
+	// scratchPlatform is the platform of images created from scratch
+	scratchPlatform ocispec.Platform
+	// allPlatforms keeps the images of every platform of pulled multi-platform images
+	allPlatforms bool
+	// cache is the shared cache storing the registry's content, if any
+	cache *Cache
 }
 
 var _ image.Registry = &Registry{}
@@ -41,10 +48,15 @@ var _ image.Registry = &Registry{}
 var nonRetriablePullError = regexp.MustCompile("specified image is a docker schema v1 manifest, which is not supported")
 
 // Pull fetches and stores an image by reference.
+// References to images on the local filesystem, oci:<dir>[:tag] and docker-archive:<file>, are loaded from there.
 func (r *Registry) Pull(ctx context.Context, ref image.Reference) error {
 	// Set the default namespace if unset
 	ctx = ensureNamespace(ctx)
 
+	if image.IsLocal(ref) {
+		return r.load(ctx, ref)
+	}
+
 	name, root, err := r.resolver.Resolve(ctx, ref.String())
 	if err != nil {
 		return fmt.Errorf("error resolving name %s: %v", name, err)
@@ -75,18 +87,101 @@ func (r *Registry) Pull(ctx context.Context, ref image.Reference) error {
 	); err != nil {
 		return err
 	}
+	if err := r.markUsed(ctx, root); err != nil {
+		return err
+	}
+
+	return r.storeImage(ctx, ref, root)
+}
 
-	img := images.Image{
-		Name:   ref.String(),
-		Target: root,
+// Resolve returns a reference to an image by the digest of its manifest in the remote registry of its reference, without pulling it.
+// References that already have a digest are returned unchanged.
+func (r *Registry) Resolve(ctx context.Context, ref image.Reference) (image.Reference, error) {
+	// Set the default namespace if unset
+	ctx = ensureNamespace(ctx)
+
+	if image.IsLocal(ref) {
+		return nil, fmt.Errorf("can't resolve %s, which isn't in a remote registry", ref)
 	}
-	if _, err = r.Images().Create(ctx, img); err != nil {
-		if errdefs.IsAlreadyExists(err) {
-			_, err = r.Images().Update(ctx, img)
-		}
+	if strings.Contains(ref.String(), "@") {
+		return ref, nil
 	}
 
-	return err
+	_, desc, err := r.resolver.Resolve(ctx, ref.String())
+	if err != nil {
+		return nil, fmt.Errorf("error resolving name %s: %v", ref, err)
+	}
+	return digestReference(ref, desc.Digest)
+}
+
+// Push uploads a stored image to the remote registry of its reference.
+// If the referenced image does not exist in the registry, an error is returned.
+// A multi-platform image is pushed as a whole if the images of all of its platforms are stored,
+// and otherwise only the image of the selected platform is pushed.
+func (r *Registry) Push(ctx context.Context, ref image.Reference) error {
+	// Set the default namespace if unset
+	ctx = ensureNamespace(ctx)
+
+	img, err := r.Images().Get(ctx, ref.String())
+	if err != nil {
+		return err
+	}
+	target, err := r.pushTarget(ctx, img.Target)
+	if err != nil {
+		return fmt.Errorf("error pushing image %s: %v", ref, err)
+	}
+
+	pusher, err := r.resolver.Pusher(ctx, ref.String())
+	if err != nil {
+		return err
+	}
+
+	r.log.Debugf("pushing %s", ref)
+	if err := remotes.PushContent(ctx, pusher, target, r.Content(), nil, nil); err != nil {
+		return fmt.Errorf("error pushing image %s: %v", ref, err)
+	}
+	return nil
+}
+
+// pushTarget returns the descriptor of the content to push for a stored image: the image itself, unless it's
+// a multi-platform image whose images aren't all stored, in which case it's the image of the selected platform.
+// An index naming images that aren't stored can't be pushed, since registries require the images an index names.
+func (r *Registry) pushTarget(ctx context.Context, target ocispec.Descriptor) (ocispec.Descriptor, error) {
+	switch target.MediaType {
+	case images.MediaTypeDockerSchema2ManifestList, ocispec.MediaTypeImageIndex:
+	default:
+		return target, nil
+	}
+
+	var index ocispec.Index
+	if err := readJSON(ctx, r.Content(), target, &index); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	var selected *ocispec.Descriptor
+	complete := true
+	for _, desc := range index.Manifests {
+		if _, err := r.Content().Info(ctx, desc.Digest); errdefs.IsNotFound(err) {
+			// Only the content of the selected platform is pulled
+			complete = false
+			continue
+		} else if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		if desc.Platform == nil || !r.platform.Match(*desc.Platform) {
+			continue
+		}
+		if selected == nil || r.platform.Less(*desc.Platform, *selected.Platform) {
+			desc := desc
+			selected = &desc
+		}
+	}
+	if complete {
+		return target, nil
+	}
+	if selected == nil {
+		return ocispec.Descriptor{}, fmt.Errorf("no image of the selected platform is stored")
+	}
+	return *selected, nil
 }
 
 // Unpack writes the unpackaged content of an image to a directory.
@@ -131,7 +226,8 @@ func (r *Registry) Labels(ctx context.Context, ref image.Reference) (map[string]
 	return imageConfig.Config.Labels, nil
 }
 
-// Destroy cleans up the on-disk boltdb file and other cache files, unless preserve cache is true
+// Destroy cleans up the on-disk boltdb file and other cache files, unless preserve cache is true.
+// Content stored in a shared cache is kept, but may be evicted to keep the cache within its size limit.
 func (r *Registry) Destroy() (err error) {
 	return r.destroy()
 }
@@ -188,7 +284,7 @@ func (r *Registry) fetch(ctx context.Context, fetcher remotes.Fetcher, root ocis
 	handler := images.Handlers(
 		visitor,
 		remotes.FetchHandler(r.Content(), fetcher),
-		images.ChildrenHandler(r.Content()),
+		r.filterPlatforms(images.ChildrenHandler(r.Content())),
 	)
 
 	return images.Dispatch(ctx, handler, nil, root)
@@ -213,6 +309,29 @@ func (r *Registry) unpackLayer(ctx context.Context, layer ocispec.Descriptor, di
 	return err
 }
 
+// markUsed records that the content of an image was used, so that it's among the last to be evicted from a shared cache.
+func (r *Registry) markUsed(ctx context.Context, target ocispec.Descriptor) error {
+	if r.cache == nil {
+		return nil
+	}
+
+	touch := images.HandlerFunc(func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		if err := r.cache.touch(desc.Digest); err != nil {
+			return nil, fmt.Errorf("error marking %s as used: %v", desc.Digest, err)
+		}
+		return images.Children(ctx, r.Content(), desc)
+	})
+	return images.Walk(ctx, r.filterPlatforms(touch), target)
+}
+
+// filterPlatforms filters the children of multi-platform images to those of the selected platform, unless all platforms are kept.
+func (r *Registry) filterPlatforms(f images.HandlerFunc) images.HandlerFunc {
+	if r.allPlatforms {
+		return f
+	}
+	return images.FilterPlatforms(f, r.platform)
+}
+
 func ensureNamespace(ctx context.Context) context.Context {
 	if _, namespaced := namespaces.Namespace(ctx); !namespaced {
 		return namespaces.WithNamespace(ctx, namespaces.Default)
@@ -3,8 +3,13 @@ package containerdregistry
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/containerd/containerd/remotes"
@@ -15,8 +20,144 @@ import (
 	"github.com/docker/docker/registry"
 )
 
+// defaultCertsDirs are the directories holding the CA certificates of registries by default, as used by podman and docker.
+var defaultCertsDirs = []string{"/etc/containers/certs.d", "/etc/docker/certs.d"}
+
+// NewResolver returns a resolver of images in remote registries, authenticating with the docker config in configDir.
+// Images are pulled from the mirrors and locations of the default registries config, if any.
 func NewResolver(configDir string, insecure bool, roots *x509.CertPool) (remotes.Resolver, error) {
-	transport := &http.Transport{
+	return newResolver(&RegistryConfig{
+		ResolverConfigDir: configDir,
+		SkipTLS:           insecure,
+		Roots:             roots,
+	})
+}
+
+// NewResolverFromOptions returns the resolver a registry created with the same options resolves
+// images in remote registries with, honoring its registries config, certs dir and TLS settings.
+func NewResolverFromOptions(options ...RegistryOption) (remotes.Resolver, error) {
+	config := defaultConfig()
+	config.apply(options)
+	return newResolver(config)
+}
+
+func newResolver(config *RegistryConfig) (remotes.Resolver, error) {
+	cfg, err := loadConfig(config.ResolverConfigDir)
+	if err != nil {
+		return nil, err
+	}
+	registries, err := loadRegistriesConfig(config.RegistriesConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := http.Header{}
+	headers.Set("User-Agent", "opm/alpha")
+
+	hosts := &registryHosts{
+		skipTLS:    config.SkipTLS,
+		roots:      config.Roots,
+		certsDirs:  defaultCertsDirs,
+		registries: registries,
+		headers:    headers,
+		creds:      credential(cfg),
+		clients:    map[string]*hostClient{},
+	}
+	if config.CertsDir != "" {
+		hosts.certsDirs = []string{config.CertsDir}
+	}
+
+	opts := docker.ResolverOptions{
+		Hosts:   hosts.hosts,
+		Headers: headers,
+	}
+
+	return &mirrorResolver{Resolver: docker.NewResolver(opts), config: registries}, nil
+}
+
+// registryHosts configures the connections to registry hosts.
+type registryHosts struct {
+	skipTLS    bool
+	roots      *x509.CertPool
+	certsDirs  []string
+	registries *registriesConfig
+	headers    http.Header
+	creds      func(string) (string, string, error)
+
+	mu      sync.Mutex
+	clients map[string]*hostClient
+}
+
+// hostClient connects to a registry host, reusing its connections and tokens across requests.
+type hostClient struct {
+	client     *http.Client
+	authorizer docker.Authorizer
+}
+
+func (h *registryHosts) hosts(host string) ([]docker.RegistryHost, error) {
+	c, err := h.client(host)
+	if err != nil {
+		return nil, err
+	}
+	config := docker.RegistryHost{
+		Client:       c.client,
+		Authorizer:   c.authorizer,
+		Host:         host,
+		Scheme:       "https",
+		Path:         "/v2",
+		Capabilities: docker.HostCapabilityPull | docker.HostCapabilityResolve | docker.HostCapabilityPush,
+	}
+	if host == "docker.io" {
+		config.Host = "registry-1.docker.io"
+	}
+
+	switch {
+	case h.skipTLS:
+		config.Scheme = "http"
+		return []docker.RegistryHost{config}, nil
+	case h.registries.insecure(host):
+		// Insecure registries may serve either unverified TLS or plain HTTP, so try both.
+		plain := config
+		plain.Scheme = "http"
+		return []docker.RegistryHost{config, plain}, nil
+	}
+	return []docker.RegistryHost{config}, nil
+}
+
+// client returns the client connecting to a registry host, trusting the CA certificates configured for the host.
+func (h *registryHosts) client(host string) (*hostClient, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if c, ok := h.clients[host]; ok {
+		return c, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: false,
+		RootCAs:            h.roots,
+	}
+	if h.skipTLS || h.registries.insecure(host) {
+		tlsConfig = &tls.Config{
+			InsecureSkipVerify: true,
+		}
+	} else {
+		hostRoots, err := h.hostRoots(host)
+		if err != nil {
+			return nil, err
+		}
+		if hostRoots != nil {
+			// The certificates of a host are trusted in addition to the roots, which can't be copied to add them.
+			// Verify the certificates here instead.
+			serverName := host
+			if name, _, err := net.SplitHostPort(host); err == nil {
+				serverName = name
+			}
+			tlsConfig.InsecureSkipVerify = true
+			tlsConfig.VerifyConnection = verifyWithAny(serverName, h.roots, hostRoots)
+		}
+	}
+
+	client := &http.Client{Transport: &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
 		DialContext: (&net.Dialer{
 			Timeout:   30 * time.Second,
@@ -26,45 +167,72 @@ func NewResolver(configDir string, insecure bool, roots *x509.CertPool) (remotes
 		IdleConnTimeout:       30 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
 		ExpectContinueTimeout: 5 * time.Second,
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: false,
-			RootCAs:            roots,
-		},
+		TLSClientConfig:       tlsConfig,
+	}}
+	c := &hostClient{
+		client: client,
+		authorizer: docker.NewDockerAuthorizer(
+			docker.WithAuthClient(client),
+			docker.WithAuthHeader(h.headers),
+			docker.WithAuthCreds(h.creds),
+		),
 	}
+	h.clients[host] = c
+	return c, nil
+}
 
-	if insecure {
-		transport.TLSClientConfig = &tls.Config{
-			InsecureSkipVerify: insecure,
+// hostRoots returns the CA certificates of a registry host, read from the *.crt files of its certs.d directories,
+// or nil if there are none.
+func (h *registryHosts) hostRoots(host string) (*x509.CertPool, error) {
+	var pool *x509.CertPool
+	for _, dir := range h.certsDirs {
+		files, err := filepath.Glob(filepath.Join(dir, host, "*.crt"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			data, err := ioutil.ReadFile(file)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if pool == nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(data) {
+				return nil, fmt.Errorf("unable to add certs specified in %s", file)
+			}
 		}
 	}
-	headers := http.Header{}
-	headers.Set("User-Agent", "opm/alpha")
-
-	client := &http.Client{Transport: transport}
-
-	cfg, err := loadConfig(configDir)
-	if err != nil {
-		return nil, err
-	}
+	return pool, nil
+}
 
-	regopts := []docker.RegistryOpt{
-		docker.WithAuthorizer(docker.NewDockerAuthorizer(
-			docker.WithAuthClient(client),
-			docker.WithAuthHeader(headers),
-			docker.WithAuthCreds(credential(cfg)),
-		)),
-		docker.WithClient(client),
-	}
-	if insecure {
-		regopts = append(regopts, docker.WithPlainHTTP(docker.MatchAllHosts))
-	}
+// verifyWithAny verifies the certificates of a TLS connection to serverName with the first of the given roots that trusts them.
+// Nil roots are the system roots.
+func verifyWithAny(serverName string, roots ...*x509.CertPool) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("no certificates presented by %s", serverName)
+		}
+		opts := x509.VerifyOptions{
+			DNSName:       serverName,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
 
-	opts := docker.ResolverOptions{
-		Hosts:   docker.ConfigureDefaultRegistries(regopts...),
-		Headers: headers,
+		var err error
+		for _, pool := range roots {
+			opts.Roots = pool
+			if _, err = cs.PeerCertificates[0].Verify(opts); err == nil {
+				return nil
+			}
+		}
+		return err
 	}
-
-	return docker.NewResolver(opts), nil
 }
 
 func credential(cfg *configfile.ConfigFile) func(string) (string, string, error) {
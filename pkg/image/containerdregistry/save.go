@@ -0,0 +1,141 @@
+package containerdregistry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/images/archive"
+	"github.com/docker/distribution/reference"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/operator-framework/operator-registry/pkg/image"
+)
+
+// SaveOCILayout writes a stored image to an OCI image layout directory, which is created if it doesn't exist.
+// The image is named by the tag of its reference in the layout's index, replacing any image of the same name.
+func (r *Registry) SaveOCILayout(ctx context.Context, ref image.Reference, dir string) error {
+	// Set the default namespace if unset
+	ctx = ensureNamespace(ctx)
+
+	img, err := r.Images().Get(ctx, ref.String())
+	if err != nil {
+		return err
+	}
+
+	copyBlobs := images.HandlerFunc(func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		if err := r.saveBlob(ctx, desc, dir); err != nil {
+			return nil, fmt.Errorf("error saving blob %s: %v", desc.Digest, err)
+		}
+		return images.Children(ctx, r.Content(), desc)
+	})
+	if err := images.Dispatch(ctx, copyBlobs, nil, img.Target); err != nil {
+		return err
+	}
+
+	layout, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, ocispec.ImageLayoutFile), layout, 0644); err != nil {
+		return err
+	}
+
+	indexFile := filepath.Join(dir, "index.json")
+	index := ocispec.Index{Versioned: specs.Versioned{SchemaVersion: 2}}
+	if data, err := ioutil.ReadFile(indexFile); err == nil {
+		if err := json.Unmarshal(data, &index); err != nil {
+			return fmt.Errorf("error parsing %s: %v", indexFile, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	target := img.Target
+	target.Annotations = map[string]string{
+		images.AnnotationImageName: ref.String(),
+		ocispec.AnnotationRefName:  ociRefName(ref.String()),
+	}
+	manifests := index.Manifests[:0]
+	for _, m := range index.Manifests {
+		if m.Annotations[ocispec.AnnotationRefName] != target.Annotations[ocispec.AnnotationRefName] {
+			manifests = append(manifests, m)
+		}
+	}
+	index.Manifests = append(manifests, target)
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(indexFile, data, 0644)
+}
+
+// SaveDockerArchive writes a stored image to a tarball that can be loaded with `docker load`.
+func (r *Registry) SaveDockerArchive(ctx context.Context, ref image.Reference, file string) error {
+	// Set the default namespace if unset
+	ctx = ensureNamespace(ctx)
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err := archive.Export(ctx, r.Content(), f, archive.WithImage(r.Images(), ref.String()), archive.WithPlatform(r.platform)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// saveBlob copies a blob into the blobs directory of an OCI image layout, unless it's already there.
+func (r *Registry) saveBlob(ctx context.Context, desc ocispec.Descriptor, dir string) error {
+	path := filepath.Join(dir, "blobs", desc.Digest.Algorithm().String(), desc.Digest.Encoded())
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	ra, err := r.Content().ReaderAt(ctx, desc)
+	if err != nil {
+		return err
+	}
+	defer ra.Close()
+
+	f, err := ioutil.TempFile(filepath.Dir(path), ".blob-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, content.NewReader(ra)); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// ociRefName returns the name of an image in an OCI image layout: the tag of its reference.
+func ociRefName(ref string) string {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return ref
+	}
+	if tagged, ok := named.(reference.Tagged); ok {
+		return tagged.Tag()
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		return digested.Digest().String()
+	}
+	return "latest"
+}
@@ -2,24 +2,42 @@ package execregistry
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 
+	"github.com/docker/distribution/reference"
 	"github.com/sirupsen/logrus"
 
 	"github.com/operator-framework/operator-registry/pkg/containertools"
 	"github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
 )
 
 // CommandRunner provides some basic methods for manipulating images via an external container tool.
 type CommandRunner interface {
 	containertools.CommandRunner
 
+	Push(image string) error
 	Unpack(image, src, dst string) error
 }
 
 // Registry enables manipulation of images via exec podman/docker commands.
 type Registry struct {
-	log *logrus.Entry
-	cmd CommandRunner
+	log      *logrus.Entry
+	cmd      CommandRunner
+	platform string
+
+	// local manipulates images on the local filesystem, which container tools can't consume consistently.
+	// It's created by the first pull of such an image.
+	local    *containerdregistry.Registry
+	localDir string
+	localMu  sync.Mutex
 }
 
 // Adapt the cmd interface to the registry interface
@@ -27,32 +45,142 @@ var _ image.Registry = &Registry{}
 
 // NewRegistry instantiates and returns a new registry which manipulates images via exec podman/docker commands.
 func NewRegistry(tool containertools.ContainerTool, logger *logrus.Entry, opts ...containertools.RunnerOption) (registry *Registry, err error) {
+	var config containertools.RunnerConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
 	return &Registry{
-		log: logger,
-		cmd: containertools.NewCommandRunner(tool, logger, opts...),
+		log:      logger,
+		cmd:      containertools.NewCommandRunner(tool, logger, opts...),
+		platform: config.Platform,
 	}, nil
 }
 
 // Pull fetches and stores an image by reference.
+// References to images on the local filesystem, oci:<dir>[:tag] and docker-archive:<file>, are loaded without the container tool.
 func (r *Registry) Pull(ctx context.Context, ref image.Reference) error {
+	if image.IsLocal(ref) {
+		local, err := r.localRegistry()
+		if err != nil {
+			return err
+		}
+		return local.Pull(ctx, ref)
+	}
 	return r.cmd.Pull(ref.String())
 }
 
+// Resolve returns a reference to an image by the digest of its manifest in the remote registry of its reference.
+// Container tools only record the digests of images they pull, so the image is pulled to find its digest.
+// References that already have a digest are returned unchanged.
+func (r *Registry) Resolve(ctx context.Context, ref image.Reference) (image.Reference, error) {
+	if image.IsLocal(ref) {
+		return nil, fmt.Errorf("can't resolve %s, which isn't in a remote registry", ref)
+	}
+	if strings.Contains(ref.String(), "@") {
+		return ref, nil
+	}
+	named, err := reference.ParseNormalizedNamed(ref.String())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing reference %s: %v", ref, err)
+	}
+
+	if err := r.cmd.Pull(ref.String()); err != nil {
+		return nil, err
+	}
+	data, err := r.cmd.Inspect(ref.String())
+	if err != nil {
+		return nil, err
+	}
+	// Docker and podman both list the digests an image was pulled by as RepoDigests.
+	var inspected []struct {
+		RepoDigests []string `json:"RepoDigests"`
+	}
+	if err := json.Unmarshal(data, &inspected); err != nil {
+		return nil, fmt.Errorf("error parsing %s inspect output: %v", r.cmd.GetToolName(), err)
+	}
+	for _, img := range inspected {
+		for _, repoDigest := range img.RepoDigests {
+			digested, err := reference.ParseNormalizedNamed(repoDigest)
+			if err != nil {
+				continue
+			}
+			if _, ok := digested.(reference.Digested); ok && digested.Name() == named.Name() {
+				return image.SimpleReference(reference.FamiliarString(digested)), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no digest of %s found in %s inspect output", ref, r.cmd.GetToolName())
+}
+
+// Push uploads an image to the remote registry of its reference.
+// If the referenced image does not exist in the registry, an error is returned.
+func (r *Registry) Push(ctx context.Context, ref image.Reference) error {
+	return r.cmd.Push(ref.String())
+}
+
+// Pack is not supported by exec tools, which can only build images from a Dockerfile.
+func (r *Registry) Pack(ctx context.Context, ref image.Reference, from io.Reader) (image.Reference, error) {
+	return nil, fmt.Errorf("packing images is not supported with %s", r.cmd.GetToolName())
+}
+
 // Unpack writes the unpackaged content of an image to a directory.
 // If the referenced image does not exist in the registry, an error is returned.
 func (r *Registry) Unpack(ctx context.Context, ref image.Reference, dir string) error {
+	if image.IsLocal(ref) {
+		local, err := r.localRegistry()
+		if err != nil {
+			return err
+		}
+		return local.Unpack(ctx, ref, dir)
+	}
 	return r.cmd.Unpack(ref.String(), "/.", dir)
 }
 
 // Labels gets the labels for an image reference.
 func (r *Registry) Labels(ctx context.Context, ref image.Reference) (map[string]string, error) {
+	if image.IsLocal(ref) {
+		local, err := r.localRegistry()
+		if err != nil {
+			return nil, err
+		}
+		return local.Labels(ctx, ref)
+	}
 	return containertools.ImageLabelReader{
 		Cmd:    r.cmd,
 		Logger: r.log,
 	}.GetLabelsFromImage(ref.String())
 }
 
-// Destroy is no-op for exec tools
+// Destroy cleans up images loaded from the local filesystem, and is otherwise a no-op for exec tools
 func (r *Registry) Destroy() error {
-	return nil
+	r.localMu.Lock()
+	defer r.localMu.Unlock()
+	if r.local == nil {
+		return nil
+	}
+	defer os.RemoveAll(r.localDir)
+	err := r.local.Destroy()
+	r.local = nil
+	return err
+}
+
+// localRegistry returns the registry of images loaded from the local filesystem, creating it if needed.
+func (r *Registry) localRegistry() (*containerdregistry.Registry, error) {
+	r.localMu.Lock()
+	defer r.localMu.Unlock()
+	if r.local != nil {
+		return r.local, nil
+	}
+
+	dir, err := ioutil.TempDir("", "exec-registry-")
+	if err != nil {
+		return nil, err
+	}
+	local, err := containerdregistry.NewRegistry(containerdregistry.WithLog(r.log), containerdregistry.WithCacheDir(filepath.Join(dir, "cache")), containerdregistry.WithPlatform(r.platform))
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	r.local, r.localDir = local, dir
+	return local, nil
 }
@@ -0,0 +1,101 @@
+package image_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/pkg/containertools"
+	"github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+	"github.com/operator-framework/operator-registry/pkg/image/execregistry"
+	libimage "github.com/operator-framework/operator-registry/pkg/lib/image"
+)
+
+func TestLocalReferences(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	host, cafile, err := libimage.RunDockerRegistry(ctx, "testdata/golden")
+	require.NoError(t, err)
+
+	tmp := t.TempDir()
+	r, err := containerdregistry.NewRegistry(
+		containerdregistry.WithLog(logrus.New().WithField("test", t.Name())),
+		containerdregistry.WithCacheDir(filepath.Join(tmp, "cache")),
+		containerdregistry.WithRootCAs(poolForCertFile(t, cafile)),
+	)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, r.Destroy()) }()
+
+	// Save the same image to an OCI image layout twice, by tag, and to a docker-archive
+	kiali := image.SimpleReference(host + "/olmtest/kiali:1.4.2")
+	require.NoError(t, r.Pull(ctx, kiali))
+	labels, err := r.Labels(ctx, kiali)
+	require.NoError(t, err)
+	layout := filepath.Join(tmp, "layout")
+	require.NoError(t, r.SaveOCILayout(ctx, kiali, layout))
+	single := filepath.Join(tmp, "single")
+	require.NoError(t, r.SaveOCILayout(ctx, kiali, single))
+	other := image.SimpleReference(host + "/olmtest/kiali:other")
+	require.NoError(t, r.Append(ctx, kiali, other, layerWithFile(t, "other.txt", "other"), nil))
+	require.NoError(t, r.SaveOCILayout(ctx, other, layout))
+	file := filepath.Join(tmp, "kiali.tar")
+	require.NoError(t, r.SaveDockerArchive(ctx, kiali, file))
+
+	registries := map[string]func(t *testing.T) image.Registry{
+		"containerd": func(t *testing.T) image.Registry {
+			r, err := containerdregistry.NewRegistry(
+				containerdregistry.WithLog(logrus.New().WithField("test", t.Name())),
+				containerdregistry.WithCacheDir(filepath.Join(t.TempDir(), "cache")),
+			)
+			require.NoError(t, err)
+			return r
+		},
+		// Local images are loaded without the container tool, so it doesn't need to be installed
+		"docker": func(t *testing.T) image.Registry {
+			r, err := execregistry.NewRegistry(containertools.DockerTool, logrus.New().WithField("test", t.Name()))
+			require.NoError(t, err)
+			return r
+		},
+	}
+
+	for name, newRegistry := range registries {
+		t.Run(name, func(t *testing.T) {
+			for _, ref := range []string{
+				"oci:" + layout + ":1.4.2",
+				"oci:" + single,
+				"docker-archive:" + file,
+			} {
+				t.Run(ref, func(t *testing.T) {
+					reg := newRegistry(t)
+					defer func() { require.NoError(t, reg.Destroy()) }()
+
+					ref := image.SimpleReference(ref)
+					require.NoError(t, reg.Pull(ctx, ref))
+					actual, err := reg.Labels(ctx, ref)
+					require.NoError(t, err)
+					require.Equal(t, labels, actual)
+					dir := filepath.Join(t.TempDir(), "unpacked")
+					require.NoError(t, reg.Unpack(ctx, ref, dir))
+					require.Equal(t, dirChecksum(t, "testdata/golden/bundles/kiali"), dirChecksum(t, dir))
+				})
+			}
+
+			for _, ref := range []string{
+				"oci:" + layout,
+				"oci:" + layout + ":missing",
+				"oci:" + filepath.Join(tmp, "missing"),
+				"docker-archive:" + filepath.Join(tmp, "missing.tar"),
+			} {
+				t.Run(ref, func(t *testing.T) {
+					reg := newRegistry(t)
+					defer func() { require.NoError(t, reg.Destroy()) }()
+					require.Error(t, reg.Pull(ctx, image.SimpleReference(ref)))
+				})
+			}
+		})
+	}
+}
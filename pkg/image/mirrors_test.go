@@ -0,0 +1,168 @@
+package image_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+	libimage "github.com/operator-framework/operator-registry/pkg/lib/image"
+)
+
+func TestRegistriesConfig(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	host, cafile, err := libimage.RunDockerRegistry(ctx, "")
+	require.NoError(t, err)
+
+	pusher, err := containerdregistry.NewRegistry(
+		containerdregistry.WithLog(logrus.New().WithField("test", t.Name())),
+		containerdregistry.WithCacheDir(filepath.Join(t.TempDir(), "cache")),
+		containerdregistry.WithRootCAs(poolForCertFile(t, cafile)),
+	)
+	require.NoError(t, err)
+	defer pusher.Destroy()
+	ref := image.SimpleReference(host + "/olmtest/mirrored:v1")
+	digested, err := pusher.Pack(ctx, ref, layerWithFile(t, "file.txt", "mirrored"))
+	require.NoError(t, err)
+	require.NoError(t, pusher.Push(ctx, ref))
+	dgst := digested.String()[strings.LastIndex(digested.String(), "@"):]
+
+	// Trust the registry through its certs.d directory rather than root CAs.
+	certsDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(certsDir, host), 0755))
+	ca, err := ioutil.ReadFile(cafile)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(certsDir, host, "ca.crt"), ca, 0644))
+
+	// Nothing listens on port 1.
+	const unreachable = "127.0.0.1:1"
+	newRegistry := func(t *testing.T, certsDir, config string) *containerdregistry.Registry {
+		path := filepath.Join(t.TempDir(), "registries.conf")
+		require.NoError(t, ioutil.WriteFile(path, []byte(config), 0644))
+		r, err := containerdregistry.NewRegistry(
+			containerdregistry.WithLog(logrus.New().WithField("test", t.Name())),
+			containerdregistry.WithCacheDir(filepath.Join(t.TempDir(), "cache")),
+			containerdregistry.WithRegistriesConfig(path),
+			containerdregistry.WithCertsDir(certsDir),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, r.Destroy()) })
+		return r
+	}
+	requirePulled := func(t *testing.T, r *containerdregistry.Registry, ref image.Reference) {
+		require.NoError(t, r.Pull(ctx, ref))
+		dir := t.TempDir()
+		require.NoError(t, r.Unpack(ctx, ref, dir))
+		requireFileContents(t, filepath.Join(dir, "file.txt"), "mirrored")
+	}
+
+	t.Run("CertsDir", func(t *testing.T) {
+		requirePulled(t, newRegistry(t, certsDir, ""), ref)
+		require.Error(t, newRegistry(t, t.TempDir(), "").Pull(ctx, ref))
+	})
+
+	t.Run("Location", func(t *testing.T) {
+		r := newRegistry(t, certsDir, fmt.Sprintf(`
+[[registry]]
+prefix = "example.invalid/rewritten"
+location = "%s/olmtest"
+`, host))
+		requirePulled(t, r, image.SimpleReference("example.invalid/rewritten/mirrored:v1"))
+		requirePulled(t, r, image.SimpleReference("example.invalid/rewritten/mirrored"+dgst))
+	})
+
+	t.Run("MirrorFallback", func(t *testing.T) {
+		r := newRegistry(t, certsDir, fmt.Sprintf(`
+[[registry]]
+prefix = "example.invalid/olmtest"
+location = "%[1]s/olmtest"
+
+[[registry.mirror]]
+location = "%[1]s/missing"
+
+[[registry.mirror]]
+location = "%[2]s/olmtest"
+`, unreachable, host))
+		requirePulled(t, r, image.SimpleReference("example.invalid/olmtest/mirrored:v1"))
+	})
+
+	t.Run("MirrorByDigestOnly", func(t *testing.T) {
+		r := newRegistry(t, certsDir, fmt.Sprintf(`
+[[registry]]
+prefix = "example.invalid/olmtest"
+location = "%s/olmtest"
+mirror-by-digest-only = true
+
+[[registry.mirror]]
+location = "%s/olmtest"
+`, unreachable, host))
+		require.Error(t, r.Pull(ctx, image.SimpleReference("example.invalid/olmtest/mirrored:v1")))
+		requirePulled(t, r, image.SimpleReference("example.invalid/olmtest/mirrored"+dgst))
+	})
+
+	t.Run("LongestPrefix", func(t *testing.T) {
+		r := newRegistry(t, certsDir, fmt.Sprintf(`
+[[registry]]
+location = "%[1]s"
+blocked = true
+
+[[registry]]
+prefix = "%[1]s/olmtest/mirrored"
+`, host))
+		requirePulled(t, r, ref)
+		require.Error(t, r.Pull(ctx, image.SimpleReference(host+"/other/image:v1")))
+	})
+
+	t.Run("Blocked", func(t *testing.T) {
+		r := newRegistry(t, certsDir, fmt.Sprintf(`
+[[registry]]
+prefix = "*.invalid"
+blocked = true
+
+[[registry]]
+location = "%s"
+blocked = true
+`, host))
+		err := r.Pull(ctx, ref)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "blocked")
+		require.Error(t, r.Pull(ctx, image.SimpleReference("registry.example.invalid/olmtest/mirrored:v1")))
+		require.Error(t, r.Push(ctx, ref))
+	})
+
+	t.Run("Insecure", func(t *testing.T) {
+		r := newRegistry(t, t.TempDir(), fmt.Sprintf(`
+[[registry]]
+prefix = "example.invalid/olmtest"
+location = "%s/olmtest"
+insecure = true
+`, host))
+		requirePulled(t, r, image.SimpleReference("example.invalid/olmtest/mirrored:v1"))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, config := range []string{
+			"[[registry]\n",
+			"[[registry]]\nblocked = true\n",
+			"[[registry]]\nprefix = \"*.example.com\"\nlocation = \"mirror.example.com\"\n",
+			"[[registry]]\nprefix = \"example.com\"\n[[registry.mirror]]\ninsecure = true\n",
+		} {
+			path := filepath.Join(t.TempDir(), "registries.conf")
+			require.NoError(t, ioutil.WriteFile(path, []byte(config), 0644))
+			_, err := containerdregistry.NewRegistry(
+				containerdregistry.WithCacheDir(filepath.Join(t.TempDir(), "cache")),
+				containerdregistry.WithRegistriesConfig(path),
+			)
+			require.Error(t, err, config)
+		}
+	})
+}
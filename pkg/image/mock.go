@@ -1,13 +1,19 @@
 package image
 
 import (
+	"archive/tar"
 	"context"
 	"errors"
+	"io"
 	"io/fs"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"testing/fstest"
+
+	"github.com/opencontainers/go-digest"
 )
 
 var _ Registry = &MockRegistry{}
@@ -58,6 +64,83 @@ func (m *MockRegistry) Pull(_ context.Context, ref Reference) error {
 	return nil
 }
 
+// Resolve returns a reference by a digest of the reference itself, standing in for the digest of the image's manifest.
+func (m *MockRegistry) Resolve(_ context.Context, ref Reference) (Reference, error) {
+	name := ref.String()
+	if strings.Contains(name, "@") {
+		return ref, nil
+	}
+	if _, ok := m.RemoteImages[ref]; !ok {
+		return nil, errors.New("not found")
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+	return SimpleReference(name + "@" + digest.FromString(ref.String()).String()), nil
+}
+
+func (m *MockRegistry) Push(_ context.Context, ref Reference) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+	image, ok := m.localImages[ref]
+	if !ok {
+		return errors.New("not found")
+	}
+	if m.RemoteImages == nil {
+		m.RemoteImages = map[Reference]*MockImage{}
+	}
+	m.RemoteImages[ref] = image
+	return nil
+}
+
+func (m *MockRegistry) Pack(_ context.Context, ref Reference, from io.Reader) (Reference, error) {
+	m.m.Lock()
+	defer m.m.Unlock()
+	files := fstest.MapFS{}
+	labels := map[string]string{}
+	if base, ok := m.localImages[ref]; ok {
+		if err := fs.WalkDir(base.FS, ".", func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			data, err := fs.ReadFile(base.FS, path)
+			if err != nil {
+				return err
+			}
+			files[path] = &fstest.MapFile{Data: data}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		for k, v := range base.Labels {
+			labels[k] = v
+		}
+	}
+	tr := tar.NewReader(from)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.ToSlash(filepath.Clean(hdr.Name))] = &fstest.MapFile{Data: data}
+	}
+	if m.localImages == nil {
+		m.localImages = map[Reference]*MockImage{}
+	}
+	m.localImages[ref] = &MockImage{Labels: labels, FS: files}
+	return ref, nil
+}
+
 func (m *MockRegistry) Unpack(_ context.Context, ref Reference, dir string) error {
 	m.m.RLock()
 	defer m.m.RUnlock()
@@ -42,6 +42,16 @@ func TestMockRegistry(t *testing.T) {
 	// Test pull of non-existent ref
 	require.Error(t, r.Pull(ctx, dne))
 
+	// Test resolve of existing and non-existent refs
+	resolved, err := r.Resolve(ctx, exists)
+	require.NoError(t, err)
+	require.Contains(t, resolved.String(), "exists@sha256:")
+	again, err := r.Resolve(ctx, resolved)
+	require.NoError(t, err)
+	require.Equal(t, resolved, again)
+	_, err = r.Resolve(ctx, dne)
+	require.Error(t, err)
+
 	// Test unpack and labels of unpulled ref
 	require.Error(t, r.Unpack(ctx, exists, tmpDir))
 	_, err = r.Labels(ctx, exists)
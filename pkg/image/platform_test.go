@@ -0,0 +1,133 @@
+package image_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+	libimage "github.com/operator-framework/operator-registry/pkg/lib/image"
+)
+
+func TestPlatforms(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	host, cafile, err := libimage.RunDockerRegistry(ctx, "")
+	require.NoError(t, err)
+	rootCAs := poolForCertFile(t, cafile)
+
+	newRegistry := func(t *testing.T, options ...containerdregistry.RegistryOption) *containerdregistry.Registry {
+		r, err := containerdregistry.NewRegistry(append([]containerdregistry.RegistryOption{
+			containerdregistry.WithLog(logrus.New().WithField("test", t.Name())),
+			containerdregistry.WithCacheDir(filepath.Join(t.TempDir(), "cache")),
+			containerdregistry.WithRootCAs(rootCAs),
+		}, options...)...)
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, r.Destroy()) })
+		return r
+	}
+
+	// Build and push an image for each platform, and a multi-platform image of both
+	var platformImages []image.Reference
+	for _, platform := range []string{"linux/amd64", "linux/arm64"} {
+		r := newRegistry(t, containerdregistry.WithPlatform(platform))
+		ref := image.SimpleReference(host + "/olmtest/platform:" + filepath.Base(platform))
+		_, err := r.Pack(ctx, ref, layerWithFile(t, "platform.txt", platform))
+		require.NoError(t, err)
+		require.NoError(t, r.Push(ctx, ref))
+		platformImages = append(platformImages, ref)
+	}
+	multi := image.SimpleReference(host + "/olmtest/platform:multi")
+	r := newRegistry(t, containerdregistry.WithAllPlatforms())
+	for _, ref := range platformImages {
+		require.NoError(t, r.Pull(ctx, ref))
+	}
+	require.Error(t, r.StoreIndex(ctx, multi, platformImages[0], platformImages[0]), "images share a platform")
+	require.NoError(t, r.StoreIndex(ctx, multi, platformImages...))
+	require.NoError(t, r.Push(ctx, multi))
+
+	t.Run("Pull", func(t *testing.T) {
+		for _, platform := range []string{"linux/amd64", "linux/arm64"} {
+			r := newRegistry(t, containerdregistry.WithPlatform(platform))
+			require.NoError(t, r.Pull(ctx, multi))
+			dir := filepath.Join(t.TempDir(), "unpacked")
+			require.NoError(t, r.Unpack(ctx, multi, dir))
+			requireFileContents(t, filepath.Join(dir, "platform.txt"), platform)
+		}
+	})
+
+	t.Run("NoMatch", func(t *testing.T) {
+		r := newRegistry(t, containerdregistry.WithPlatform("linux/s390x"))
+		require.NoError(t, r.Pull(ctx, multi))
+		require.Error(t, r.Unpack(ctx, multi, filepath.Join(t.TempDir(), "unpacked")))
+	})
+
+	t.Run("PushSelectedPlatform", func(t *testing.T) {
+		pushed := image.SimpleReference(host + "/olmtest/platform:pushed")
+		r := newRegistry(t, containerdregistry.WithAllPlatforms())
+		for _, ref := range platformImages {
+			require.NoError(t, r.Pull(ctx, ref))
+		}
+		require.NoError(t, r.StoreIndex(ctx, pushed, platformImages...))
+		require.NoError(t, r.Push(ctx, pushed))
+
+		// Only the content of the selected platform is pulled, so only its image is pushed
+		r = newRegistry(t, containerdregistry.WithPlatform("linux/arm64"))
+		require.NoError(t, r.Pull(ctx, pushed))
+		require.NoError(t, r.Push(ctx, pushed))
+
+		r = newRegistry(t)
+		resolved, err := r.Resolve(ctx, pushed)
+		require.NoError(t, err)
+		arm64, err := r.Resolve(ctx, platformImages[1])
+		require.NoError(t, err)
+		require.Equal(t, arm64, resolved)
+	})
+
+	t.Run("InvalidPlatform", func(t *testing.T) {
+		_, err := containerdregistry.NewRegistry(
+			containerdregistry.WithCacheDir(filepath.Join(t.TempDir(), "cache")),
+			containerdregistry.WithPlatform("linux/amd64/v1/extra"),
+		)
+		require.Error(t, err)
+	})
+
+	t.Run("AppendAllPlatforms", func(t *testing.T) {
+		r := newRegistry(t, containerdregistry.WithAllPlatforms())
+		require.NoError(t, r.Pull(ctx, multi))
+		appended := image.SimpleReference(host + "/olmtest/platform:appended")
+		require.NoError(t, r.Append(ctx, multi, appended, layerWithFile(t, "appended.txt", "appended"), nil))
+		require.NoError(t, r.Push(ctx, appended))
+
+		for _, platform := range []string{"linux/amd64", "linux/arm64"} {
+			r := newRegistry(t, containerdregistry.WithPlatform(platform))
+			require.NoError(t, r.Pull(ctx, appended))
+			dir := filepath.Join(t.TempDir(), "unpacked")
+			require.NoError(t, r.Unpack(ctx, appended, dir))
+			requireFileContents(t, filepath.Join(dir, "platform.txt"), platform)
+			requireFileContents(t, filepath.Join(dir, "appended.txt"), "appended")
+		}
+	})
+
+	t.Run("AppendSelectedPlatform", func(t *testing.T) {
+		// Only the content of the selected platform is pulled, so only its image is appended to
+		r := newRegistry(t, containerdregistry.WithPlatform("linux/arm64"))
+		require.NoError(t, r.Pull(ctx, multi))
+		appended := image.SimpleReference(host + "/olmtest/platform:arm64-appended")
+		require.NoError(t, r.Append(ctx, multi, appended, layerWithFile(t, "appended.txt", "appended"), nil))
+		dir := filepath.Join(t.TempDir(), "unpacked")
+		require.NoError(t, r.Unpack(ctx, appended, dir))
+		requireFileContents(t, filepath.Join(dir, "platform.txt"), "linux/arm64")
+	})
+}
+
+func requireFileContents(t *testing.T, path, contents string) {
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, contents, string(data))
+}
@@ -0,0 +1,132 @@
+package image_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+	libimage "github.com/operator-framework/operator-registry/pkg/lib/image"
+)
+
+func TestPackAndPush(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	goldenHost, goldenCAFile, err := libimage.RunDockerRegistry(ctx, "testdata/golden")
+	require.NoError(t, err)
+	host, cafile, err := libimage.RunDockerRegistry(ctx, "")
+	require.NoError(t, err)
+
+	rootCAs := poolForCertFile(t, cafile)
+	goldenCerts, err := ioutil.ReadFile(goldenCAFile)
+	require.NoError(t, err)
+	require.True(t, rootCAs.AppendCertsFromPEM(goldenCerts))
+
+	tmp := t.TempDir()
+	newRegistry := func(t *testing.T) *containerdregistry.Registry {
+		r, err := containerdregistry.NewRegistry(
+			containerdregistry.WithLog(logrus.New().WithField("test", t.Name())),
+			containerdregistry.WithCacheDir(filepath.Join(t.TempDir(), "cache")),
+			containerdregistry.WithRootCAs(rootCAs),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, r.Destroy()) })
+		return r
+	}
+	requireFile := func(t *testing.T, dir, name, contents string) {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.Equal(t, contents, string(data))
+	}
+
+	ref := image.SimpleReference(host + "/olmtest/packed:v1")
+	var first image.Reference
+
+	t.Run("FromScratch", func(t *testing.T) {
+		r := newRegistry(t)
+		first, err = r.Pack(ctx, ref, layerWithFile(t, "first/file.txt", "first"))
+		require.NoError(t, err)
+		require.Contains(t, first.String(), host+"/olmtest/packed@sha256:")
+		require.NoError(t, r.Push(ctx, ref))
+		require.NoError(t, r.Push(ctx, first))
+
+		pulled := newRegistry(t)
+		for _, pull := range []image.Reference{ref, first} {
+			require.NoError(t, pulled.Pull(ctx, pull))
+			dir := filepath.Join(tmp, "unpacked-"+filepath.Base(pull.String()))
+			require.NoError(t, pulled.Unpack(ctx, pull, dir))
+			requireFile(t, dir, "first/file.txt", "first")
+		}
+	})
+
+	t.Run("Resolve", func(t *testing.T) {
+		r := newRegistry(t)
+		resolved, err := r.Resolve(ctx, ref)
+		require.NoError(t, err)
+		require.Equal(t, first, resolved)
+
+		// Nothing is pulled to resolve a reference.
+		_, err = r.Labels(ctx, ref)
+		require.Error(t, err)
+
+		resolved, err = r.Resolve(ctx, first)
+		require.NoError(t, err)
+		require.Equal(t, first, resolved)
+
+		_, err = r.Resolve(ctx, image.SimpleReference(host+"/olmtest/missing:v1"))
+		require.Error(t, err)
+		_, err = r.Resolve(ctx, image.SimpleReference("oci:"+tmp))
+		require.Error(t, err)
+	})
+
+	t.Run("FromBase", func(t *testing.T) {
+		r := newRegistry(t)
+		require.NoError(t, r.Pull(ctx, ref))
+		next, err := r.Pack(ctx, ref, layerWithFile(t, "second/file.txt", "second"))
+		require.NoError(t, err)
+		require.NotEqual(t, first, next)
+		require.NoError(t, r.Push(ctx, ref))
+
+		pulled := newRegistry(t)
+		require.NoError(t, pulled.Pull(ctx, ref))
+		dir := filepath.Join(tmp, "unpacked-base")
+		require.NoError(t, pulled.Unpack(ctx, ref, dir))
+		requireFile(t, dir, "first/file.txt", "first")
+		requireFile(t, dir, "second/file.txt", "second")
+
+		// The first image is still available by digest
+		require.NoError(t, pulled.Pull(ctx, first))
+		dir = filepath.Join(tmp, "unpacked-first")
+		require.NoError(t, pulled.Unpack(ctx, first, dir))
+		require.NoFileExists(t, filepath.Join(dir, "second", "file.txt"))
+	})
+
+	t.Run("KeepsBaseLabels", func(t *testing.T) {
+		r := newRegistry(t)
+		base := image.SimpleReference(goldenHost + "/olmtest/kiali:1.4.2")
+		require.NoError(t, r.Pull(ctx, base))
+		baseLabels, err := r.Labels(ctx, base)
+		require.NoError(t, err)
+
+		next, err := r.Pack(ctx, base, layerWithFile(t, "packed/file.txt", "packed"))
+		require.NoError(t, err)
+		labels, err := r.Labels(ctx, next)
+		require.NoError(t, err)
+		require.Equal(t, baseLabels, labels)
+
+		dir := filepath.Join(tmp, "unpacked-kiali")
+		require.NoError(t, r.Unpack(ctx, next, dir))
+		requireFile(t, dir, "packed/file.txt", "packed")
+		require.FileExists(t, filepath.Join(dir, "manifests", "kiali.crd.yaml"))
+	})
+
+	t.Run("PushMissing", func(t *testing.T) {
+		r := newRegistry(t)
+		require.Error(t, r.Push(ctx, image.SimpleReference(host+"/olmtest/missing:v1")))
+	})
+}
@@ -1,6 +1,16 @@
 package image
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// OCILayoutPrefix prefixes references to images in an OCI image layout directory: oci:<dir>[:tag].
+	OCILayoutPrefix = "oci:"
+	// DockerArchivePrefix prefixes references to images in a tarball written by `docker save`: docker-archive:<file>.
+	DockerArchivePrefix = "docker-archive:"
+)
 
 // Reference describes a reference to a container image.
 type Reference interface {
@@ -14,3 +24,10 @@ func (s SimpleReference) String() string {
 	ref := string(s)
 	return ref
 }
+
+// IsLocal returns true if a reference is to an image on the local filesystem,
+// in an OCI image layout directory or a docker-archive tarball, rather than in a remote registry.
+func IsLocal(ref Reference) bool {
+	s := ref.String()
+	return strings.HasPrefix(s, OCILayoutPrefix) || strings.HasPrefix(s, DockerArchivePrefix)
+}
@@ -2,17 +2,21 @@ package image
 
 import (
 	"context"
+	"io"
 )
 
-// Registry knows how to Pull and Unpack Operator Bundle images to the filesystem.
-// Note: In the future, Registry will know how to Build and Push Operator Bundle images as well.
+// Registry knows how to Pull, Unpack, Pack, and Push Operator Bundle images.
 type Registry interface {
 	// Pull fetches and stores an image by reference.
 	Pull(ctx context.Context, ref Reference) error
 
+	// Resolve returns a reference to an image by the digest of its manifest in the remote registry of its reference.
+	// References that already have a digest are returned unchanged.
+	Resolve(ctx context.Context, ref Reference) (Reference, error)
+
 	// Push uploads an image to the remote registry of its reference.
 	// If the referenced image does not exist in the registry, an error is returned.
-	// Push(ctx context.Context, ref string) error
+	Push(ctx context.Context, ref Reference) error
 
 	// Unpack writes the unpackaged content of an image to a directory.
 	// If the referenced image does not exist in the registry, an error is returned.
@@ -27,5 +31,6 @@ type Registry interface {
 	// Pack creates and stores an image based on the given reference and returns a reference to the new image.
 	// If the referenced image does not exist in the registry, a new image is created from scratch.
 	// If it exists, it's used as the base image.
-	// Pack(ctx context.Context, ref Reference, from io.Reader) (next string, err error)
+	// The content of the new image is read from the tar stream from, and the new image replaces the referenced one.
+	Pack(ctx context.Context, ref Reference, from io.Reader) (next Reference, err error)
 }
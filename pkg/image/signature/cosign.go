@@ -0,0 +1,224 @@
+package signature
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/containerd/containerd/remotes"
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+)
+
+const (
+	// CosignSignatureAnnotation is the annotation of the layers of a cosign signature artifact holding the
+	// base64 encoded signature of the layer's payload.
+	CosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// CosignPayloadMediaType is the media type of the layers of a cosign signature artifact.
+	CosignPayloadMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+
+	// maxSignatureSize limits the size of the signature manifests and payloads read from registries.
+	maxSignatureSize = 4 << 20
+)
+
+// CosignPayload is the payload signed by a cosign signature, identifying the image signed by its digest.
+type CosignPayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]interface{} `json:"optional"`
+}
+
+// CosignSignatureTag returns the tag cosign stores the signatures of the image with a digest under,
+// in the image's repository.
+func CosignSignatureTag(dgst digest.Digest) string {
+	return fmt.Sprintf("%s-%s.sig", dgst.Algorithm(), dgst.Encoded())
+}
+
+// CosignVerifier verifies cosign-style detached signatures against a set of public keys.
+// The signatures of an image are stored as the layers of an OCI artifact tagged after the image's digest
+// in the image's repository, and an image is verified if any of them is valid for any of the keys.
+type CosignVerifier struct {
+	resolver remotes.Resolver
+	keys     []crypto.PublicKey
+}
+
+var _ Verifier = &CosignVerifier{}
+
+// NewCosignVerifier returns a verifier of cosign signatures against public keys,
+// which resolves images and fetches their signatures with resolver.
+func NewCosignVerifier(resolver remotes.Resolver, keys []crypto.PublicKey) *CosignVerifier {
+	return &CosignVerifier{resolver: resolver, keys: keys}
+}
+
+// LoadCosignVerifier returns a verifier of cosign signatures against the public keys in keyFiles,
+// which resolves images in remote registries like a containerd registry created with options does.
+func LoadCosignVerifier(keyFiles []string, options ...containerdregistry.RegistryOption) (*CosignVerifier, error) {
+	keys, err := LoadPublicKeys(keyFiles...)
+	if err != nil {
+		return nil, err
+	}
+	resolver, err := containerdregistry.NewResolverFromOptions(options...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating resolver: %v", err)
+	}
+	return NewCosignVerifier(resolver, keys), nil
+}
+
+// Verify verifies that an image is signed with one of the verifier's keys.
+func (v *CosignVerifier) Verify(ctx context.Context, ref image.Reference) (image.Reference, error) {
+	if len(v.keys) == 0 {
+		return nil, errors.New("no public keys to verify signatures with")
+	}
+	if image.IsLocal(ref) {
+		return nil, fmt.Errorf("can't verify the signature of %s, which isn't in a remote registry", ref)
+	}
+	named, err := reference.ParseNormalizedNamed(ref.String())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing reference %s: %v", ref, err)
+	}
+
+	_, desc, err := v.resolver.Resolve(ctx, ref.String())
+	if err != nil {
+		return nil, fmt.Errorf("error resolving name %s: %v", ref, err)
+	}
+	if digested, ok := named.(reference.Digested); ok && digested.Digest() != desc.Digest {
+		return nil, fmt.Errorf("image %s has digest %s", ref, desc.Digest)
+	}
+
+	sigRef := reference.TrimNamed(named).String() + ":" + CosignSignatureTag(desc.Digest)
+	name, sigDesc, err := v.resolver.Resolve(ctx, sigRef)
+	if err != nil {
+		return nil, fmt.Errorf("no signatures found for %s: %v", ref, err)
+	}
+	fetcher, err := v.resolver.Fetcher(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	var manifest ocispec.Manifest
+	data, err := fetch(ctx, fetcher, sigDesc)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching signatures %s: %v", sigRef, err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("error parsing signatures %s: %v", sigRef, err)
+	}
+
+	var errs []error
+	for _, layer := range manifest.Layers {
+		sig, ok := layer.Annotations[CosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		payload, err := fetch(ctx, fetcher, layer)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching signature payload %s: %v", layer.Digest, err)
+		}
+		if err := v.verifyPayload(payload, sig, desc.Digest); err != nil {
+			errs = append(errs, fmt.Errorf("signature %s: %v", layer.Digest, err))
+			continue
+		}
+
+		digested, err := reference.WithDigest(reference.TrimNamed(named), desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+		return image.SimpleReference(reference.FamiliarString(digested)), nil
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no signatures found for %s", ref)
+	}
+	return nil, fmt.Errorf("no valid signature found for %s: %v", ref, utilerrors.NewAggregate(errs))
+}
+
+// verifyPayload verifies a signature of a payload with any of the verifier's keys,
+// and that the payload identifies the image with the digest.
+func (v *CosignVerifier) verifyPayload(payload []byte, sig string, dgst digest.Digest) error {
+	raw, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return fmt.Errorf("error decoding signature: %v", err)
+	}
+	var verified bool
+	for _, key := range v.keys {
+		if verifySignature(key, payload, raw) == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return errors.New("not signed by any of the public keys")
+	}
+
+	var p CosignPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("error parsing payload: %v", err)
+	}
+	if p.Critical.Image.DockerManifestDigest != dgst.String() {
+		return fmt.Errorf("signs digest %q rather than %s", p.Critical.Image.DockerManifestDigest, dgst)
+	}
+	return nil
+}
+
+// verifySignature verifies a signature of a payload with a public key, as cosign signs with keys of each type.
+func verifySignature(key crypto.PublicKey, payload, sig []byte) error {
+	sum := sha256.Sum256(payload)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, sum[:], sig) {
+			return errors.New("invalid ecdsa signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, sum[:], sig)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, payload, sig) {
+			return errors.New("invalid ed25519 signature")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported public key type %T", key)
+}
+
+// fetch reads a blob of limited size from a registry, verifying its digest.
+func fetch(ctx context.Context, fetcher remotes.Fetcher, desc ocispec.Descriptor) ([]byte, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, err
+	}
+	if desc.Size > maxSignatureSize {
+		return nil, fmt.Errorf("%s is too large, %d bytes", desc.Digest, desc.Size)
+	}
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(rc, maxSignatureSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if actual := desc.Digest.Algorithm().FromBytes(data); actual != desc.Digest {
+		return nil, fmt.Errorf("content has digest %s rather than %s", actual, desc.Digest)
+	}
+	return data, nil
+}
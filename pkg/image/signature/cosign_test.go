@@ -0,0 +1,316 @@
+package signature_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/remotes"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+	"github.com/operator-framework/operator-registry/pkg/image/signature"
+	libimage "github.com/operator-framework/operator-registry/pkg/lib/image"
+)
+
+// pushBlob pushes content to a registry, unless it's there already.
+func pushBlob(t *testing.T, ctx context.Context, pusher remotes.Pusher, mediaType string, data []byte, annotations map[string]string) ocispec.Descriptor {
+	desc := ocispec.Descriptor{
+		MediaType:   mediaType,
+		Digest:      digest.FromBytes(data),
+		Size:        int64(len(data)),
+		Annotations: annotations,
+	}
+	w, err := pusher.Push(ctx, desc)
+	if errdefs.IsAlreadyExists(err) {
+		return desc
+	}
+	require.NoError(t, err)
+	defer w.Close()
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	err = w.Commit(ctx, desc.Size, desc.Digest)
+	if !errdefs.IsAlreadyExists(err) {
+		require.NoError(t, err)
+	}
+	return desc
+}
+
+// pushImage pushes an image of layers with the media type, returning the digest of its manifest.
+func pushImage(t *testing.T, ctx context.Context, resolver remotes.Resolver, ref, layerType string, layers ...[]byte) digest.Digest {
+	pusher, err := resolver.Pusher(ctx, ref)
+	require.NoError(t, err)
+
+	manifest := ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Config:    pushBlob(t, ctx, pusher, ocispec.MediaTypeImageConfig, []byte("{}"), nil),
+	}
+	for _, layer := range layers {
+		manifest.Layers = append(manifest.Layers, pushBlob(t, ctx, pusher, layerType, layer, nil))
+	}
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	return pushBlob(t, ctx, pusher, ocispec.MediaTypeImageManifest, data, nil).Digest
+}
+
+type signer struct {
+	key  crypto.Signer
+	file string
+}
+
+func newSigner(t *testing.T, key crypto.Signer) signer {
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "key.pub")
+	require.NoError(t, ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644))
+	return signer{key: key, file: file}
+}
+
+// sign returns a cosign payload identifying an image by digest, and its base64 encoded signature.
+func (s signer) sign(t *testing.T, repo string, dgst digest.Digest) ([]byte, string) {
+	var p signature.CosignPayload
+	p.Critical.Identity.DockerReference = repo
+	p.Critical.Image.DockerManifestDigest = dgst.String()
+	p.Critical.Type = "cosign container image signature"
+	payload, err := json.Marshal(p)
+	require.NoError(t, err)
+
+	var sig []byte
+	if _, ok := s.key.(ed25519.PrivateKey); ok {
+		sig, err = s.key.Sign(rand.Reader, payload, crypto.Hash(0))
+	} else {
+		sum := sha256.Sum256(payload)
+		sig, err = s.key.Sign(rand.Reader, sum[:], crypto.SHA256)
+	}
+	require.NoError(t, err)
+	return payload, base64.StdEncoding.EncodeToString(sig)
+}
+
+// pushSignatures pushes a cosign signature artifact for an image, signing it with the signers,
+// or signing signedDigest in its place if it's set.
+func pushSignatures(t *testing.T, ctx context.Context, resolver remotes.Resolver, repo string, dgst, signedDigest digest.Digest, signers ...signer) {
+	if signedDigest == "" {
+		signedDigest = dgst
+	}
+	ref := repo + ":" + signature.CosignSignatureTag(dgst)
+	pusher, err := resolver.Pusher(ctx, ref)
+	require.NoError(t, err)
+
+	manifest := ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Config:    pushBlob(t, ctx, pusher, ocispec.MediaTypeImageConfig, []byte("{}"), nil),
+	}
+	for _, s := range signers {
+		payload, sig := s.sign(t, repo, signedDigest)
+		layer := pushBlob(t, ctx, pusher, signature.CosignPayloadMediaType, payload, map[string]string{
+			signature.CosignSignatureAnnotation: sig,
+		})
+		manifest.Layers = append(manifest.Layers, layer)
+	}
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	pushBlob(t, ctx, pusher, ocispec.MediaTypeImageManifest, data, nil)
+}
+
+func TestCosignVerifier(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	host, cafile, err := libimage.RunDockerRegistry(ctx, "")
+	require.NoError(t, err)
+	rootCAs, err := x509.SystemCertPool()
+	require.NoError(t, err)
+	certs, err := ioutil.ReadFile(cafile)
+	require.NoError(t, err)
+	require.True(t, rootCAs.AppendCertsFromPEM(certs))
+	resolver, err := containerdregistry.NewResolver("", false, rootCAs)
+	require.NoError(t, err)
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	releaseSigner, ed25519Signer, otherSigner := newSigner(t, ecdsaKey), newSigner(t, ed25519Key), newSigner(t, otherKey)
+
+	repo := host + "/olmtest/bundle"
+	push := func(tag, contents string) digest.Digest {
+		return pushImage(t, ctx, resolver, repo+":"+tag, ocispec.MediaTypeImageLayer, []byte(contents))
+	}
+	signed := push("signed", "signed")
+	pushSignatures(t, ctx, resolver, repo, signed, "", otherSigner, releaseSigner)
+	signedEd25519 := push("signed-ed25519", "signed-ed25519")
+	pushSignatures(t, ctx, resolver, repo, signedEd25519, "", ed25519Signer)
+	push("unsigned", "unsigned")
+	wrongDigest := push("wrong-digest", "wrong-digest")
+	pushSignatures(t, ctx, resolver, repo, wrongDigest, signed, releaseSigner)
+
+	newVerifier := func(t *testing.T, signers ...signer) *signature.CosignVerifier {
+		var files []string
+		for _, s := range signers {
+			files = append(files, s.file)
+		}
+		keys, err := signature.LoadPublicKeys(files...)
+		require.NoError(t, err)
+		return signature.NewCosignVerifier(resolver, keys)
+	}
+
+	tests := []struct {
+		name     string
+		ref      string
+		signers  []signer
+		expected string
+		err      string
+	}{
+		{
+			name:     "Signed",
+			ref:      repo + ":signed",
+			signers:  []signer{releaseSigner},
+			expected: repo + "@" + signed.String(),
+		},
+		{
+			name:     "SignedByDigest",
+			ref:      repo + "@" + signed.String(),
+			signers:  []signer{releaseSigner},
+			expected: repo + "@" + signed.String(),
+		},
+		{
+			name:     "AnyKey",
+			ref:      repo + ":signed-ed25519",
+			signers:  []signer{releaseSigner, ed25519Signer},
+			expected: repo + "@" + signedEd25519.String(),
+		},
+		{
+			name:    "WrongKey",
+			ref:     repo + ":signed-ed25519",
+			signers: []signer{releaseSigner},
+			err:     "not signed by any of the public keys",
+		},
+		{
+			name:    "Unsigned",
+			ref:     repo + ":unsigned",
+			signers: []signer{releaseSigner},
+			err:     "no signatures found",
+		},
+		{
+			name:    "WrongDigest",
+			ref:     repo + ":wrong-digest",
+			signers: []signer{releaseSigner},
+			err:     "rather than " + wrongDigest.String(),
+		},
+		{
+			name:    "Missing",
+			ref:     repo + ":missing",
+			signers: []signer{releaseSigner},
+			err:     "error resolving name",
+		},
+		{
+			name:    "Local",
+			ref:     "oci:" + t.TempDir(),
+			signers: []signer{releaseSigner},
+			err:     "isn't in a remote registry",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verified, err := newVerifier(t, tt.signers...).Verify(ctx, image.SimpleReference(tt.ref))
+			if tt.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, verified.String())
+		})
+	}
+
+	t.Run("LoadedWithRegistryOptions", func(t *testing.T) {
+		// The registry's CA is only trusted through the certs dir the verifier shares with registries
+		certsDir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(certsDir, host), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(certsDir, host, "ca.crt"), certs, 0644))
+
+		verifier, err := signature.LoadCosignVerifier([]string{releaseSigner.file}, containerdregistry.WithCertsDir(certsDir))
+		require.NoError(t, err)
+		verified, err := verifier.Verify(ctx, image.SimpleReference(repo+":signed"))
+		require.NoError(t, err)
+		require.Equal(t, repo+"@"+signed.String(), verified.String())
+
+		verifier, err = signature.LoadCosignVerifier([]string{releaseSigner.file})
+		require.NoError(t, err)
+		_, err = verifier.Verify(ctx, image.SimpleReference(repo+":signed"))
+		require.Error(t, err)
+	})
+
+	t.Run("WarnPolicy", func(t *testing.T) {
+		logger := logrus.New()
+		logger.SetOutput(ioutil.Discard)
+		entry := logrus.NewEntry(logger)
+
+		warn := signature.WithPolicy(newVerifier(t, releaseSigner), signature.PolicyWarn, entry)
+		verified, err := warn.Verify(ctx, image.SimpleReference(repo+":unsigned"))
+		require.NoError(t, err)
+		require.Equal(t, repo+":unsigned", verified.String())
+		verified, err = warn.Verify(ctx, image.SimpleReference(repo+":signed"))
+		require.NoError(t, err)
+		require.Equal(t, repo+"@"+signed.String(), verified.String())
+
+		enforce := signature.WithPolicy(newVerifier(t, releaseSigner), signature.PolicyEnforce, entry)
+		_, err = enforce.Verify(ctx, image.SimpleReference(repo+":unsigned"))
+		require.Error(t, err)
+	})
+}
+
+func TestLoadPublicKeys(t *testing.T) {
+	dir := t.TempDir()
+	var bundle []byte
+	for i := 0; i < 2; i++ {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKIXPublicKey(key.Public())
+		require.NoError(t, err)
+		bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})...)
+	}
+	keysFile := filepath.Join(dir, "keys.pub")
+	require.NoError(t, ioutil.WriteFile(keysFile, bundle, 0644))
+	emptyFile := filepath.Join(dir, "empty.pub")
+	require.NoError(t, ioutil.WriteFile(emptyFile, []byte("not a key"), 0644))
+
+	keys, err := signature.LoadPublicKeys(keysFile)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+
+	_, err = signature.LoadPublicKeys(keysFile, emptyFile)
+	require.EqualError(t, err, "no public key found in "+emptyFile)
+
+	_, err = signature.LoadPublicKeys(filepath.Join(dir, "missing.pub"))
+	require.Error(t, err)
+}
+
+func TestParsePolicy(t *testing.T) {
+	for _, name := range []string{"enforce", "warn"} {
+		policy, err := signature.ParsePolicy(name)
+		require.NoError(t, err)
+		require.Equal(t, signature.Policy(name), policy)
+	}
+	_, err := signature.ParsePolicy("ignore")
+	require.Error(t, err)
+}
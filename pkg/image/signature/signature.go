@@ -0,0 +1,96 @@
+// Package signature verifies the signatures of images before they're used.
+package signature
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/operator-framework/operator-registry/pkg/image"
+)
+
+// Verifier verifies the signatures of images.
+type Verifier interface {
+	// Verify verifies the signatures of an image, and returns a reference to the verified image by digest,
+	// so that it's the image pulled even if its reference by tag is moved afterwards.
+	Verify(ctx context.Context, ref image.Reference) (image.Reference, error)
+}
+
+// Policy is what happens to images whose signatures can't be verified.
+type Policy string
+
+const (
+	// PolicyEnforce rejects images whose signatures can't be verified.
+	PolicyEnforce Policy = "enforce"
+	// PolicyWarn accepts images whose signatures can't be verified, logging a warning about them.
+	PolicyWarn Policy = "warn"
+)
+
+// ParsePolicy parses the name of a policy.
+func ParsePolicy(name string) (Policy, error) {
+	switch p := Policy(name); p {
+	case PolicyEnforce, PolicyWarn:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown signature policy %q, expected %s or %s", name, PolicyEnforce, PolicyWarn)
+}
+
+// WithPolicy applies a policy to the images a verifier fails to verify.
+// With PolicyWarn, such images are returned unchanged after logging a warning.
+func WithPolicy(v Verifier, policy Policy, logger *logrus.Entry) Verifier {
+	if policy != PolicyWarn {
+		return v
+	}
+	return &warnVerifier{verifier: v, logger: logger}
+}
+
+type warnVerifier struct {
+	verifier Verifier
+	logger   *logrus.Entry
+}
+
+func (w *warnVerifier) Verify(ctx context.Context, ref image.Reference) (image.Reference, error) {
+	verified, err := w.verifier.Verify(ctx, ref)
+	if err != nil {
+		w.logger.WithError(err).Warnf("signature of image %s not verified", ref)
+		return ref, nil
+	}
+	return verified, nil
+}
+
+// LoadPublicKeys reads the PEM encoded public keys in files. A file may hold more than one key.
+func LoadPublicKeys(files ...string) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var found bool
+		for {
+			var block *pem.Block
+			block, data = pem.Decode(data)
+			if block == nil {
+				break
+			}
+			if block.Type != "PUBLIC KEY" {
+				continue
+			}
+			key, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing public key in %s: %v", file, err)
+			}
+			keys = append(keys, key)
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("no public key found in %s", file)
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,245 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileStamp identifies a version of a file by its size and modification time.
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+func stampFiles(paths ...string) ([]fileStamp, error) {
+	stamps := make([]fileStamp, 0, len(paths))
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		stamps = append(stamps, fileStamp{size: info.Size(), modTime: info.ModTime()})
+	}
+	return stamps, nil
+}
+
+func equalStamps(a, b []fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].size != b[i].size || !a[i].modTime.Equal(b[i].modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// KeyPairReloader serves an x509 key pair loaded from files, reloading it whenever
+// either file changes on disk, e.g. when a mounted secret is rotated.
+//
+// If a changed key pair fails to load, for instance because only one of the files
+// has been replaced so far, the previously loaded key pair keeps being served and
+// loading is retried on the next use.
+type KeyPairReloader struct {
+	certFile, keyFile string
+
+	mu     sync.Mutex
+	cert   *tls.Certificate
+	stamps []fileStamp
+}
+
+// NewKeyPairReloader returns a KeyPairReloader for the given files, failing if the
+// key pair can't be loaded initially.
+func NewKeyPairReloader(certFile, keyFile string) (*KeyPairReloader, error) {
+	r := &KeyPairReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *KeyPairReloader) reload() error {
+	stamps, err := stampFiles(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	if r.cert != nil && equalStamps(stamps, r.stamps) {
+		return nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load key pair %s, %s: %v", r.certFile, r.keyFile, err)
+	}
+	r.cert, r.stamps = &cert, stamps
+	return nil
+}
+
+// Certificate returns the current key pair.
+func (r *KeyPairReloader) Certificate() *tls.Certificate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Failures are deliberately ignored in favor of the last good key pair.
+	_ = r.reload()
+	return r.cert
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (r *KeyPairReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
+
+// GetClientCertificate implements tls.Config.GetClientCertificate.
+func (r *KeyPairReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
+
+// CertPoolReloader serves a pool of CA certificates loaded from a file, reloading it
+// whenever the file changes on disk. Like KeyPairReloader, it keeps serving the last
+// pool that loaded successfully.
+type CertPoolReloader struct {
+	caFile string
+
+	mu     sync.Mutex
+	pool   *x509.CertPool
+	stamps []fileStamp
+}
+
+// NewCertPoolReloader returns a CertPoolReloader for caFile, failing if it can't be
+// loaded initially.
+func NewCertPoolReloader(caFile string) (*CertPoolReloader, error) {
+	r := &CertPoolReloader{caFile: caFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *CertPoolReloader) reload() error {
+	stamps, err := stampFiles(r.caFile)
+	if err != nil {
+		return err
+	}
+	if r.pool != nil && equalStamps(stamps, r.stamps) {
+		return nil
+	}
+	certs, err := ioutil.ReadFile(r.caFile)
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if ok := pool.AppendCertsFromPEM(certs); !ok {
+		return fmt.Errorf("unable to add certs specified in %s", r.caFile)
+	}
+	r.pool, r.stamps = pool, stamps
+	return nil
+}
+
+// Pool returns the current pool of CA certificates.
+func (r *CertPoolReloader) Pool() *x509.CertPool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_ = r.reload()
+	return r.pool
+}
+
+// ServerTLSConfig returns the TLS config for a server presenting the key pair in
+// certFile and keyFile, both of which are reloaded when they change. If clientCAFile
+// is set, clients must present a certificate signed by one of the CAs it contains;
+// it is reloaded when it changes as well.
+//
+// ServerTLSConfig returns a nil config if no files are given.
+func ServerTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	if certFile == "" && keyFile == "" {
+		if clientCAFile != "" {
+			return nil, fmt.Errorf("a client CA requires a TLS certificate and key")
+		}
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("both a TLS certificate and key must be provided")
+	}
+
+	keyPair, err := NewKeyPairReloader(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: keyPair.GetCertificate,
+	}
+	if clientCAFile == "" {
+		return config, nil
+	}
+
+	clientCAs, err := NewCertPoolReloader(clientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	// Client certificates are verified by hand so that the CA pool can be reloaded
+	// without replacing the config, which TLS credentials take a copy of.
+	config.ClientAuth = tls.RequireAnyClientCert
+	config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		return verifyClientCertificate(rawCerts, clientCAs.Pool())
+	}
+	return config, nil
+}
+
+func verifyClientCertificate(rawCerts [][]byte, roots *x509.CertPool) error {
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("no client certificate provided")
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("failed to parse client certificate: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to verify client certificate: %v", err)
+	}
+	return nil
+}
+
+// ClientTLSConfig returns the TLS config for a client that trusts the system root CAs
+// and those in caFile, if set. If certFile and keyFile are set, the client presents
+// the key pair they contain, reloading it when it changes.
+func ClientTLSConfig(caFile, certFile, keyFile string, insecureSkipVerify bool) (*tls.Config, error) {
+	rootCAs, err := RootCAs(caFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		RootCAs:            rootCAs,
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+	if certFile == "" && keyFile == "" {
+		return config, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("both a client certificate and key must be provided")
+	}
+	keyPair, err := NewKeyPairReloader(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	config.GetClientCertificate = keyPair.GetClientCertificate
+	return config, nil
+}
@@ -0,0 +1,179 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCert returns a certificate for 127.0.0.1 signed by parent, or a self-signed CA for any usage if parent is nil.
+func newTestCert(t *testing.T, serial int64, usage x509.ExtKeyUsage, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	signer := &testCert{cert: template, key: key}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+		template.ExtKeyUsage = nil
+	} else {
+		signer = parent
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer.cert, &key.PublicKey, signer.key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key}
+}
+
+// write writes the certificate and key to dir, returning their paths.
+func (c *testCert) write(t *testing.T, dir, name string, modTime time.Time) (string, string) {
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+	return certFile, keyFile
+}
+
+func serial(t *testing.T, cert *tls.Certificate) int64 {
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	return parsed.SerialNumber.Int64()
+}
+
+func TestKeyPairReloader(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, 1, x509.ExtKeyUsageServerAuth, nil)
+	start := time.Now().Add(-time.Minute)
+	certFile, keyFile := newTestCert(t, 2, x509.ExtKeyUsageServerAuth, ca).write(t, dir, "server", start)
+
+	r, err := NewKeyPairReloader(certFile, keyFile)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), serial(t, r.Certificate()))
+
+	// A rotated key pair is picked up once both files have been replaced.
+	rotated := newTestCert(t, 3, x509.ExtKeyUsageServerAuth, ca)
+	keyDER, err := x509.MarshalECPrivateKey(rotated.key)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rotated.cert.Raw}), 0600))
+	require.Equal(t, int64(2), serial(t, r.Certificate()), "a mismatched key pair should not be served")
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	require.Equal(t, int64(3), serial(t, r.Certificate()))
+
+	// Removed files don't interrupt serving.
+	require.NoError(t, os.Remove(certFile))
+	require.Equal(t, int64(3), serial(t, r.Certificate()))
+
+	_, err = NewKeyPairReloader(certFile, keyFile)
+	require.Error(t, err)
+}
+
+func TestServerTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	ca := newTestCert(t, 1, x509.ExtKeyUsageServerAuth, nil)
+	caFile, _ := ca.write(t, dir, "ca", now)
+	clientCAFile, _ := ca.write(t, dir, "client-ca", now)
+	serverCert, serverKey := newTestCert(t, 2, x509.ExtKeyUsageServerAuth, ca).write(t, dir, "server", now)
+	clientCert, clientKey := newTestCert(t, 3, x509.ExtKeyUsageClientAuth, ca).write(t, dir, "client", now)
+	otherCA := newTestCert(t, 4, x509.ExtKeyUsageServerAuth, nil)
+	otherCert, otherKey := newTestCert(t, 5, x509.ExtKeyUsageClientAuth, otherCA).write(t, dir, "other", now)
+
+	t.Run("Disabled", func(t *testing.T) {
+		config, err := ServerTLSConfig("", "", "")
+		require.NoError(t, err)
+		require.Nil(t, config)
+	})
+
+	t.Run("InvalidFlags", func(t *testing.T) {
+		_, err := ServerTLSConfig(serverCert, "", "")
+		require.Error(t, err)
+		_, err = ServerTLSConfig("", "", clientCAFile)
+		require.Error(t, err)
+	})
+
+	// handshake connects a client using the given key pair to a server using config.
+	handshake := func(t *testing.T, config *tls.Config, certFile, keyFile string) error {
+		lis, err := tls.Listen("tcp", "127.0.0.1:0", config)
+		require.NoError(t, err)
+		defer lis.Close()
+		go func() {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			conn.(*tls.Conn).Handshake()
+			conn.Read(make([]byte, 1))
+		}()
+
+		clientConfig, err := ClientTLSConfig(caFile, certFile, keyFile, false)
+		require.NoError(t, err)
+		conn, err := tls.Dial("tcp", lis.Addr().String(), clientConfig)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		// With TLS 1.3, client certificate errors surface on the first read.
+		conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		if _, err := conn.Read(make([]byte, 1)); err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				return nil
+			}
+			return err
+		}
+		return nil
+	}
+
+	t.Run("TLS", func(t *testing.T) {
+		config, err := ServerTLSConfig(serverCert, serverKey, "")
+		require.NoError(t, err)
+		require.NoError(t, handshake(t, config, "", ""))
+	})
+
+	t.Run("MutualTLS", func(t *testing.T) {
+		config, err := ServerTLSConfig(serverCert, serverKey, clientCAFile)
+		require.NoError(t, err)
+		require.NoError(t, handshake(t, config, clientCert, clientKey))
+		require.Error(t, handshake(t, config, "", ""))
+		require.Error(t, handshake(t, config, otherCert, otherKey))
+
+		// Rotating the client CA changes which clients are accepted.
+		otherCAFile, _ := otherCA.write(t, dir, "other-ca", now.Add(time.Minute))
+		data, err := ioutil.ReadFile(otherCAFile)
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(clientCAFile, data, 0600))
+		require.NoError(t, os.Chtimes(clientCAFile, now.Add(time.Minute), now.Add(time.Minute)))
+		require.NoError(t, handshake(t, config, otherCert, otherKey))
+		require.Error(t, handshake(t, config, clientCert, clientKey))
+	})
+}
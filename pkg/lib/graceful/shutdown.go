@@ -11,11 +11,16 @@ import (
 )
 
 func Shutdown(logger logrus.FieldLogger, run func() error, cleanup func()) error {
+	return ShutdownContext(context.Background(), logger, run, cleanup)
+}
+
+// ShutdownContext is Shutdown, also cleaning up once ctx is done.
+func ShutdownContext(ctx context.Context, logger logrus.FieldLogger, run func() error, cleanup func()) error {
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 	defer signal.Stop(interrupt)
 
-	g, ctx := errgroup.WithContext(context.Background())
+	g, ctx := errgroup.WithContext(ctx)
 	g.Go(run)
 
 	select {
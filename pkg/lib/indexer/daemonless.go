@@ -0,0 +1,181 @@
+package indexer
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/operator-framework/operator-registry/pkg/containertools"
+	"github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+	"github.com/operator-framework/operator-registry/pkg/lib/certs"
+)
+
+const (
+	// OCILayoutOutputPrefix prefixes the build output of an index image written to an OCI image layout directory.
+	OCILayoutOutputPrefix = image.OCILayoutPrefix
+	// DockerArchiveOutputPrefix prefixes the build output of an index image written to a docker-archive tarball.
+	DockerArchiveOutputPrefix = image.DockerArchivePrefix
+)
+
+// buildWithoutContainerTool builds an index image serving the database at databasePath without a
+// container tool, by adding a layer with the database to the binary source image.
+// binarySourceImage may be a comma separated list of images of different platforms, and the index image has
+// an image for each platform of them, so multi-platform binary source images build multi-platform index images.
+// The image is written to output, either an OCI image layout directory ("oci:<dir>") or a
+// tarball that can be loaded with `docker load` ("docker-archive:<file>"), which only holds the image of the host platform.
+func (i ImageIndexer) buildWithoutContainerTool(binarySourceImage, databasePath, tag, output, caFile string, skipTLS bool) error {
+	var save func(context.Context, *containerdregistry.Registry, image.Reference) error
+	switch {
+	case strings.HasPrefix(output, OCILayoutOutputPrefix) && len(output) > len(OCILayoutOutputPrefix):
+		dir := strings.TrimPrefix(output, OCILayoutOutputPrefix)
+		save = func(ctx context.Context, reg *containerdregistry.Registry, ref image.Reference) error {
+			return reg.SaveOCILayout(ctx, ref, dir)
+		}
+	case strings.HasPrefix(output, DockerArchiveOutputPrefix) && len(output) > len(DockerArchiveOutputPrefix):
+		file := strings.TrimPrefix(output, DockerArchiveOutputPrefix)
+		save = func(ctx context.Context, reg *containerdregistry.Registry, ref image.Reference) error {
+			return reg.SaveDockerArchive(ctx, ref, file)
+		}
+	default:
+		return fmt.Errorf("invalid build output %q, expected %s<dir> or %s<file>", output, OCILayoutOutputPrefix, DockerArchiveOutputPrefix)
+	}
+
+	if binarySourceImage == "" {
+		binarySourceImage = containertools.DefaultBinarySourceImage
+	}
+	if tag == "" {
+		tag = defaultImageTag
+	}
+
+	tmpDir, err := ioutil.TempDir("./", tmpDirPrefix)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	rootCAs, err := certs.RootCAs(caFile)
+	if err != nil {
+		return fmt.Errorf("failed to get RootCAs: %v", err)
+	}
+	reg, err := containerdregistry.NewRegistry(containerdregistry.SkipTLS(skipTLS), containerdregistry.WithLog(i.Logger), containerdregistry.WithRootCAs(rootCAs), containerdregistry.WithCacheDir(filepath.Join(tmpDir, "cache")), containerdregistry.WithAllPlatforms())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := reg.Destroy(); err != nil {
+			i.Logger.WithError(err).Warn("error destroying local cache")
+		}
+	}()
+
+	ctx := context.TODO()
+	var bases []image.Reference
+	for _, binaryImage := range strings.Split(binarySourceImage, ",") {
+		base := image.SimpleReference(strings.TrimSpace(binaryImage))
+		i.Logger.Infof("Pulling binary source image %s", base)
+		if err := reg.Pull(ctx, base); err != nil {
+			return err
+		}
+		bases = append(bases, base)
+	}
+	base := bases[0]
+	if len(bases) > 1 {
+		base = image.SimpleReference("binary-source-images")
+		if err := reg.StoreIndex(ctx, base, bases...); err != nil {
+			return fmt.Errorf("error combining binary source images: %v", err)
+		}
+	}
+
+	layer, w := io.Pipe()
+	go func() {
+		w.CloseWithError(writeLayer(w, databasePath, containertools.DefaultDbLocation))
+	}()
+	defer layer.Close()
+
+	i.Logger.Infof("Building index image %s", tag)
+	ref := image.SimpleReference(tag)
+	if err := reg.Append(ctx, base, ref, layer, func(config *ocispec.ImageConfig) {
+		if config.Labels == nil {
+			config.Labels = map[string]string{}
+		}
+		config.Labels[containertools.DbLocationLabel] = containertools.DefaultDbLocation
+		if config.ExposedPorts == nil {
+			config.ExposedPorts = map[string]struct{}{}
+		}
+		config.ExposedPorts["50051/tcp"] = struct{}{}
+		config.Entrypoint = []string{"/bin/opm"}
+		config.Cmd = []string{"registry", "serve", "--database", containertools.DefaultDbLocation}
+	}); err != nil {
+		return err
+	}
+
+	i.Logger.Infof("Writing index image to %s", output)
+	return save(ctx, reg, ref)
+}
+
+// writeLayer writes an uncompressed layer tarball with the file or directory src at the path dst.
+// Entries are owned by root, like those added by a Dockerfile.
+func writeLayer(w io.Writer, src, dst string) error {
+	tw := tar.NewWriter(w)
+	dst = strings.TrimPrefix(filepath.ToSlash(dst), "/")
+
+	// Parent directories of dst come first
+	var parent string
+	for _, dir := range strings.Split(filepath.Dir(dst), "/") {
+		if dir == "." {
+			break
+		}
+		parent += dir + "/"
+		if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: parent, Mode: 0755}); err != nil {
+			return err
+		}
+	}
+
+	if err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(filepath.Join(dst, rel))
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		hdr.Uid, hdr.Gid, hdr.Uname, hdr.Gname = 0, 0, "", ""
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	}); err != nil {
+		return err
+	}
+	return tw.Close()
+}
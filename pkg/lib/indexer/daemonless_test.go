@@ -0,0 +1,173 @@
+package indexer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/pkg/containertools"
+	"github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+	libimage "github.com/operator-framework/operator-registry/pkg/lib/image"
+)
+
+func readBlob(t *testing.T, dir string, desc ocispec.Descriptor, v interface{}) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, "blobs", desc.Digest.Algorithm().String(), desc.Digest.Encoded()))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, v))
+}
+
+func emptyLayer(t *testing.T) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, tar.NewWriter(&buf).Close())
+	return buf.Bytes()
+}
+
+func rootCAs(t *testing.T, files ...string) *x509.CertPool {
+	pool := x509.NewCertPool()
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		require.True(t, pool.AppendCertsFromPEM(data))
+	}
+	return pool
+}
+
+func TestBuildWithoutContainerTool(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	host, cafile, err := libimage.RunDockerRegistry(ctx, "../../image/testdata/golden")
+	require.NoError(t, err)
+
+	i := ImageIndexer{
+		BuildTool: containertools.NoneTool,
+		Logger:    logrus.NewEntry(logrus.New()),
+	}
+	base := host + "/olmtest/kiali:1.4.2"
+	tmp := t.TempDir()
+
+	t.Run("OCILayout", func(t *testing.T) {
+		dir := filepath.Join(tmp, "layout")
+		require.NoError(t, i.buildWithoutContainerTool(base, "testdata/bundles.db", "example.com/index:v1", OCILayoutOutputPrefix+dir, cafile, false))
+
+		var index ocispec.Index
+		data, err := ioutil.ReadFile(filepath.Join(dir, "index.json"))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &index))
+		require.Len(t, index.Manifests, 1)
+		require.Equal(t, "v1", index.Manifests[0].Annotations[ocispec.AnnotationRefName])
+
+		var manifest ocispec.Manifest
+		readBlob(t, dir, index.Manifests[0], &manifest)
+		var config ocispec.Image
+		readBlob(t, dir, manifest.Config, &config)
+		require.Equal(t, containertools.DefaultDbLocation, config.Config.Labels[containertools.DbLocationLabel])
+		require.Equal(t, []string{"/bin/opm"}, config.Config.Entrypoint)
+		require.Equal(t, []string{"registry", "serve", "--database", containertools.DefaultDbLocation}, config.Config.Cmd)
+		require.Contains(t, config.Config.ExposedPorts, "50051/tcp")
+
+		// The database is added in a new layer at the location of the label
+		layer := manifest.Layers[len(manifest.Layers)-1]
+		f, err := os.Open(filepath.Join(dir, "blobs", layer.Digest.Algorithm().String(), layer.Digest.Encoded()))
+		require.NoError(t, err)
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		require.NoError(t, err)
+		files := map[string][]byte{}
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			require.Zero(t, hdr.Uid)
+			data, err := ioutil.ReadAll(tr)
+			require.NoError(t, err)
+			files[hdr.Name] = data
+		}
+		expected, err := ioutil.ReadFile("testdata/bundles.db")
+		require.NoError(t, err)
+		require.Contains(t, files, "database/")
+		require.Equal(t, expected, files["database/index.db"])
+	})
+
+	t.Run("DockerArchive", func(t *testing.T) {
+		file := filepath.Join(tmp, "index.tar")
+		require.NoError(t, i.buildWithoutContainerTool(base, "testdata/bundles.db", "", DockerArchiveOutputPrefix+file, cafile, false))
+		require.FileExists(t, file)
+	})
+
+	t.Run("MultiArch", func(t *testing.T) {
+		// Push binary images of two platforms to a registry
+		multiHost, multiCAFile, err := libimage.RunDockerRegistry(ctx, "")
+		require.NoError(t, err)
+		var binaryImages []string
+		for _, platform := range []string{"linux/amd64", "linux/arm64"} {
+			reg, err := containerdregistry.NewRegistry(
+				containerdregistry.WithCacheDir(filepath.Join(t.TempDir(), "cache")),
+				containerdregistry.WithPlatform(platform),
+				containerdregistry.WithRootCAs(rootCAs(t, cafile, multiCAFile)),
+			)
+			require.NoError(t, err)
+			ref := image.SimpleReference(multiHost + "/olmtest/opm:" + filepath.Base(platform))
+			if platform == "linux/amd64" {
+				require.NoError(t, reg.Pull(ctx, image.SimpleReference(base)))
+				require.NoError(t, reg.Append(ctx, image.SimpleReference(base), ref, bytes.NewReader(emptyLayer(t)), nil))
+			} else {
+				_, err = reg.Pack(ctx, ref, bytes.NewReader(emptyLayer(t)))
+				require.NoError(t, err)
+			}
+			require.NoError(t, reg.Push(ctx, ref))
+			require.NoError(t, reg.Destroy())
+			binaryImages = append(binaryImages, ref.String())
+		}
+
+		dir := filepath.Join(tmp, "multi")
+		require.NoError(t, i.buildWithoutContainerTool(strings.Join(binaryImages, ","), "testdata/bundles.db", "example.com/index:v1", OCILayoutOutputPrefix+dir, multiCAFile, false))
+
+		var layout ocispec.Index
+		data, err := ioutil.ReadFile(filepath.Join(dir, "index.json"))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &layout))
+		require.Len(t, layout.Manifests, 1)
+		require.Equal(t, ocispec.MediaTypeImageIndex, layout.Manifests[0].MediaType)
+
+		var index ocispec.Index
+		readBlob(t, dir, layout.Manifests[0], &index)
+		require.Len(t, index.Manifests, 2)
+		var architectures []string
+		for _, desc := range index.Manifests {
+			architectures = append(architectures, desc.Platform.Architecture)
+			var manifest ocispec.Manifest
+			readBlob(t, dir, desc, &manifest)
+			var config ocispec.Image
+			readBlob(t, dir, manifest.Config, &config)
+			require.Equal(t, desc.Platform.Architecture, config.Architecture)
+			require.Equal(t, containertools.DefaultDbLocation, config.Config.Labels[containertools.DbLocationLabel])
+		}
+		require.ElementsMatch(t, []string{"amd64", "arm64"}, architectures)
+
+		// Images must be of different platforms
+		require.Error(t, i.buildWithoutContainerTool(binaryImages[0]+","+binaryImages[0], "testdata/bundles.db", "", OCILayoutOutputPrefix+filepath.Join(tmp, "duplicate"), multiCAFile, false))
+	})
+
+	t.Run("InvalidOutput", func(t *testing.T) {
+		for _, output := range []string{"", "oci:", "docker-archive:", filepath.Join(tmp, "dir")} {
+			require.Error(t, i.buildWithoutContainerTool(base, "testdata/bundles.db", "", output, cafile, false), output)
+		}
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/operator-framework/operator-registry/pkg/image"
 	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
 	"github.com/operator-framework/operator-registry/pkg/image/execregistry"
+	"github.com/operator-framework/operator-registry/pkg/image/signature"
 	"github.com/operator-framework/operator-registry/pkg/lib/bundle"
 	"github.com/operator-framework/operator-registry/pkg/lib/certs"
 	"github.com/operator-framework/operator-registry/pkg/lib/registry"
@@ -66,6 +67,12 @@ type AddToIndexRequest struct {
 	SkipTLS           bool
 	Overwrite         bool
 	EnableAlpha       bool
+	BuildOutput       string
+	PullConcurrency   int
+	Verifier          signature.Verifier
+	// Platform is the platform of the images pulled from the multi-platform images of
+	// the index and bundles, such as linux/arm64. The host platform is pulled if unset.
+	Platform string
 }
 
 // AddToIndex is an aggregate API used to generate a registry index image with additional bundles
@@ -76,21 +83,24 @@ func (i ImageIndexer) AddToIndex(request AddToIndexRequest) error {
 		return err
 	}
 
-	databasePath, err := i.ExtractDatabase(buildDir, request.FromIndex, request.CaFile, request.SkipTLS)
+	databasePath, err := i.extractDatabase(buildDir, request.FromIndex, request.CaFile, request.Platform, request.SkipTLS)
 	if err != nil {
 		return err
 	}
 
 	// Run opm registry add on the database
 	addToRegistryReq := registry.AddToRegistryRequest{
-		Bundles:       request.Bundles,
-		InputDatabase: databasePath,
-		Permissive:    request.Permissive,
-		Mode:          request.Mode,
-		SkipTLS:       request.SkipTLS,
-		ContainerTool: i.PullTool,
-		Overwrite:     request.Overwrite,
-		EnableAlpha:   request.EnableAlpha,
+		Bundles:         request.Bundles,
+		InputDatabase:   databasePath,
+		Permissive:      request.Permissive,
+		Mode:            request.Mode,
+		SkipTLS:         request.SkipTLS,
+		ContainerTool:   i.PullTool,
+		Overwrite:       request.Overwrite,
+		EnableAlpha:     request.EnableAlpha,
+		PullConcurrency: request.PullConcurrency,
+		Verifier:        request.Verifier,
+		Platform:        request.Platform,
 	}
 
 	// Add the bundles to the registry
@@ -111,6 +121,10 @@ func (i ImageIndexer) AddToIndex(request AddToIndexRequest) error {
 		return nil
 	}
 
+	if i.BuildTool == containertools.NoneTool {
+		return i.buildWithoutContainerTool(request.BinarySourceImage, databasePath, request.Tag, request.BuildOutput, request.CaFile, request.SkipTLS)
+	}
+
 	// build the dockerfile
 	err = build(outDockerfile, request.Tag, i.CommandRunner, i.Logger)
 	if err != nil {
@@ -131,6 +145,7 @@ type DeleteFromIndexRequest struct {
 	Operators         []string
 	SkipTLS           bool
 	CaFile            string
+	BuildOutput       string
 }
 
 // DeleteFromIndex is an aggregate API used to generate a registry index image
@@ -171,6 +186,10 @@ func (i ImageIndexer) DeleteFromIndex(request DeleteFromIndexRequest) error {
 		return nil
 	}
 
+	if i.BuildTool == containertools.NoneTool {
+		return i.buildWithoutContainerTool(request.BinarySourceImage, databasePath, request.Tag, request.BuildOutput, request.CaFile, request.SkipTLS)
+	}
+
 	// build the dockerfile
 	err = build(outDockerfile, request.Tag, i.CommandRunner, i.Logger)
 	if err != nil {
@@ -189,6 +208,7 @@ type PruneStrandedFromIndexRequest struct {
 	Tag               string
 	CaFile            string
 	SkipTLS           bool
+	BuildOutput       string
 }
 
 // PruneStrandedFromIndex is an aggregate API used to generate a registry index image
@@ -227,6 +247,10 @@ func (i ImageIndexer) PruneStrandedFromIndex(request PruneStrandedFromIndexReque
 		return nil
 	}
 
+	if i.BuildTool == containertools.NoneTool {
+		return i.buildWithoutContainerTool(request.BinarySourceImage, databasePath, request.Tag, request.BuildOutput, request.CaFile, request.SkipTLS)
+	}
+
 	// build the dockerfile
 	err = build(outDockerfile, request.Tag, i.CommandRunner, i.Logger)
 	if err != nil {
@@ -246,6 +270,7 @@ type PruneFromIndexRequest struct {
 	Packages          []string
 	CaFile            string
 	SkipTLS           bool
+	BuildOutput       string
 }
 
 func (i ImageIndexer) PruneFromIndex(request PruneFromIndexRequest) error {
@@ -284,6 +309,10 @@ func (i ImageIndexer) PruneFromIndex(request PruneFromIndexRequest) error {
 		return nil
 	}
 
+	if i.BuildTool == containertools.NoneTool {
+		return i.buildWithoutContainerTool(request.BinarySourceImage, databasePath, request.Tag, request.BuildOutput, request.CaFile, request.SkipTLS)
+	}
+
 	// build the dockerfile
 	err = build(outDockerfile, request.Tag, i.CommandRunner, i.Logger)
 	if err != nil {
@@ -295,13 +324,18 @@ func (i ImageIndexer) PruneFromIndex(request PruneFromIndexRequest) error {
 
 // ExtractDatabase sets a temp directory for unpacking an image
 func (i ImageIndexer) ExtractDatabase(buildDir, fromIndex, caFile string, skipTLS bool) (string, error) {
+	return i.extractDatabase(buildDir, fromIndex, caFile, "", skipTLS)
+}
+
+// extractDatabase is ExtractDatabase, pulling the image of the given platform of a multi-platform index.
+func (i ImageIndexer) extractDatabase(buildDir, fromIndex, caFile, platform string, skipTLS bool) (string, error) {
 	tmpDir, err := ioutil.TempDir("./", tmpDirPrefix)
 	if err != nil {
 		return "", err
 	}
 	defer os.RemoveAll(tmpDir)
 
-	databaseFile, err := i.getDatabaseFile(tmpDir, fromIndex, caFile, skipTLS)
+	databaseFile, err := i.getDatabaseFile(tmpDir, fromIndex, caFile, platform, skipTLS)
 	if err != nil {
 		return "", err
 	}
@@ -309,7 +343,7 @@ func (i ImageIndexer) ExtractDatabase(buildDir, fromIndex, caFile string, skipTL
 	return copyDatabaseTo(databaseFile, filepath.Join(buildDir, defaultDatabaseFolder))
 }
 
-func (i ImageIndexer) getDatabaseFile(workingDir, fromIndex, caFile string, skipTLS bool) (string, error) {
+func (i ImageIndexer) getDatabaseFile(workingDir, fromIndex, caFile, platform string, skipTLS bool) (string, error) {
 	if fromIndex == "" {
 		return path.Join(workingDir, defaultDatabaseFile), nil
 	}
@@ -325,11 +359,11 @@ func (i ImageIndexer) getDatabaseFile(workingDir, fromIndex, caFile string, skip
 		if err != nil {
 			return "", fmt.Errorf("failed to get RootCAs: %v", err)
 		}
-		reg, rerr = containerdregistry.NewRegistry(containerdregistry.SkipTLS(skipTLS), containerdregistry.WithLog(i.Logger), containerdregistry.WithRootCAs(rootCAs))
+		reg, rerr = containerdregistry.NewRegistry(containerdregistry.SkipTLS(skipTLS), containerdregistry.WithLog(i.Logger), containerdregistry.WithRootCAs(rootCAs), containerdregistry.WithPlatform(platform))
 	case containertools.PodmanTool:
 		fallthrough
 	case containertools.DockerTool:
-		reg, rerr = execregistry.NewRegistry(i.PullTool, i.Logger, containertools.SkipTLS(skipTLS))
+		reg, rerr = execregistry.NewRegistry(i.PullTool, i.Logger, containertools.SkipTLS(skipTLS), containertools.WithPlatform(platform))
 	}
 	if rerr != nil {
 		return "", rerr
@@ -492,7 +526,7 @@ func (i ImageIndexer) ExportFromIndex(request ExportFromIndexRequest) error {
 	defer os.RemoveAll(workingDir)
 
 	// extract the index database to the file
-	databaseFile, err := i.getDatabaseFile(workingDir, request.Index, request.CaFile, request.SkipTLS)
+	databaseFile, err := i.getDatabaseFile(workingDir, request.Index, request.CaFile, "", request.SkipTLS)
 	if err != nil {
 		return err
 	}
@@ -649,6 +683,7 @@ type DeprecateFromIndexRequest struct {
 	Tag               string
 	CaFile            string
 	SkipTLS           bool
+	BuildOutput       string
 }
 
 // DeprecateFromIndex takes a DeprecateFromIndexRequest and deprecates the requested
@@ -689,6 +724,10 @@ func (i ImageIndexer) DeprecateFromIndex(request DeprecateFromIndexRequest) erro
 		return nil
 	}
 
+	if i.BuildTool == containertools.NoneTool {
+		return i.buildWithoutContainerTool(request.BinarySourceImage, databasePath, request.Tag, request.BuildOutput, request.CaFile, request.SkipTLS)
+	}
+
 	// build the dockerfile with requested tooling
 	err = build(outDockerfile, request.Tag, i.CommandRunner, i.Logger)
 	if err != nil {
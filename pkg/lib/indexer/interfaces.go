@@ -69,14 +69,14 @@ type IndexStrandedPruner interface {
 	PruneStrandedFromIndex(PruneStrandedFromIndexRequest) error
 }
 
-func NewIndexStrandedPruner(containerTool containertools.ContainerTool, logger *logrus.Entry) IndexStrandedPruner {
+func NewIndexStrandedPruner(buildTool, pullTool containertools.ContainerTool, logger *logrus.Entry) IndexStrandedPruner {
 	return ImageIndexer{
 		DockerfileGenerator:    containertools.NewDockerfileGenerator(logger),
-		CommandRunner:          containertools.NewCommandRunner(containerTool, logger),
-		LabelReader:            containertools.NewLabelReader(containerTool, logger),
+		CommandRunner:          containertools.NewCommandRunner(buildTool, logger),
+		LabelReader:            containertools.NewLabelReader(pullTool, logger),
 		RegistryStrandedPruner: registry.NewRegistryStrandedPruner(logger),
-		BuildTool:              containerTool,
-		PullTool:               containerTool,
+		BuildTool:              buildTool,
+		PullTool:               pullTool,
 		Logger:                 logger,
 	}
 }
@@ -86,14 +86,14 @@ type IndexPruner interface {
 	PruneFromIndex(PruneFromIndexRequest) error
 }
 
-func NewIndexPruner(containerTool containertools.ContainerTool, logger *logrus.Entry) IndexPruner {
+func NewIndexPruner(buildTool, pullTool containertools.ContainerTool, logger *logrus.Entry) IndexPruner {
 	return ImageIndexer{
 		DockerfileGenerator: containertools.NewDockerfileGenerator(logger),
-		CommandRunner:       containertools.NewCommandRunner(containerTool, logger),
-		LabelReader:         containertools.NewLabelReader(containerTool, logger),
+		CommandRunner:       containertools.NewCommandRunner(buildTool, logger),
+		LabelReader:         containertools.NewLabelReader(pullTool, logger),
 		RegistryPruner:      registry.NewRegistryPruner(logger),
-		BuildTool:           containerTool,
-		PullTool:            containerTool,
+		BuildTool:           buildTool,
+		PullTool:            pullTool,
 		Logger:              logger,
 	}
 }
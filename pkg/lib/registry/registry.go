@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sync"
+	"sync/atomic"
 
 	"github.com/sirupsen/logrus"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -13,6 +15,7 @@ import (
 	"github.com/operator-framework/operator-registry/pkg/image"
 	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
 	"github.com/operator-framework/operator-registry/pkg/image/execregistry"
+	"github.com/operator-framework/operator-registry/pkg/image/signature"
 	"github.com/operator-framework/operator-registry/pkg/lib/certs"
 	"github.com/operator-framework/operator-registry/pkg/registry"
 	"github.com/operator-framework/operator-registry/pkg/sqlite"
@@ -32,8 +35,18 @@ type AddToRegistryRequest struct {
 	ContainerTool containertools.ContainerTool
 	Overwrite     bool
 	EnableAlpha   bool
+	// PullConcurrency is the number of bundle images pulled and unpacked at once, DefaultPullConcurrency if unset.
+	PullConcurrency int
+	// Verifier verifies the signatures of bundle images before they're pulled, if set.
+	Verifier signature.Verifier
+	// Platform is the platform of the images pulled from multi-platform bundle images, such as linux/arm64.
+	// The host platform is pulled if unset.
+	Platform string
 }
 
+// DefaultPullConcurrency is the default number of bundle images pulled and unpacked at once.
+const DefaultPullConcurrency = 10
+
 func (r RegistryUpdater) AddToRegistry(request AddToRegistryRequest) error {
 	db, err := sqlite.Open(request.InputDatabase)
 	if err != nil {
@@ -66,11 +79,11 @@ func (r RegistryUpdater) AddToRegistry(request AddToRegistryRequest) error {
 		if err != nil {
 			return fmt.Errorf("failed to get RootCAs: %v", err)
 		}
-		reg, rerr = containerdregistry.NewRegistry(containerdregistry.SkipTLS(request.SkipTLS), containerdregistry.WithRootCAs(rootCAs))
+		reg, rerr = containerdregistry.NewRegistry(containerdregistry.SkipTLS(request.SkipTLS), containerdregistry.WithRootCAs(rootCAs), containerdregistry.WithPlatform(request.Platform))
 	case containertools.PodmanTool:
 		fallthrough
 	case containertools.DockerTool:
-		reg, rerr = execregistry.NewRegistry(request.ContainerTool, r.Logger, containertools.SkipTLS(request.SkipTLS))
+		reg, rerr = execregistry.NewRegistry(request.ContainerTool, r.Logger, containertools.SkipTLS(request.SkipTLS), containertools.WithPlatform(request.Platform))
 	}
 	if rerr != nil {
 		return rerr
@@ -86,7 +99,11 @@ func (r RegistryUpdater) AddToRegistry(request AddToRegistryRequest) error {
 		simpleRefs = append(simpleRefs, image.SimpleReference(ref))
 	}
 
-	if err := populate(context.TODO(), dbLoader, graphLoader, dbQuerier, reg, simpleRefs, request.Mode, request.Overwrite); err != nil {
+	concurrency := request.PullConcurrency
+	if concurrency == 0 {
+		concurrency = DefaultPullConcurrency
+	}
+	if err := populate(context.TODO(), dbLoader, graphLoader, dbQuerier, reg, simpleRefs, request.Mode, request.Overwrite, concurrency, request.Verifier, r.Logger); err != nil {
 		r.Logger.Debugf("unable to populate database: %s", err)
 
 		if !request.Permissive {
@@ -99,18 +116,29 @@ func (r RegistryUpdater) AddToRegistry(request AddToRegistryRequest) error {
 	return nil
 }
 
-func unpackImage(ctx context.Context, reg image.Registry, ref image.Reference) (image.Reference, string, func(), error) {
+// unpackImage pulls and unpacks an image. If verifier is set, the image's signatures are verified first,
+// and the verified image is the one pulled, though it's still returned by the reference it was given.
+func unpackImage(ctx context.Context, reg image.Registry, verifier signature.Verifier, ref image.Reference) (image.Reference, string, func(), error) {
+	pull := ref
+	if verifier != nil {
+		verified, err := verifier.Verify(ctx, ref)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("error verifying signature: %v", err)
+		}
+		pull = verified
+	}
+
 	var errs []error
 	workingDir, err := ioutil.TempDir("./", "bundle_tmp")
 	if err != nil {
 		errs = append(errs, err)
 	}
 
-	if err = reg.Pull(ctx, ref); err != nil {
+	if err = reg.Pull(ctx, pull); err != nil {
 		errs = append(errs, err)
 	}
 
-	if err = reg.Unpack(ctx, ref, workingDir); err != nil {
+	if err = reg.Unpack(ctx, pull, workingDir); err != nil {
 		errs = append(errs, err)
 	}
 
@@ -126,22 +154,90 @@ func unpackImage(ctx context.Context, reg image.Registry, ref image.Reference) (
 	return ref, workingDir, cleanup, nil
 }
 
-func populate(ctx context.Context, loader registry.Load, graphLoader registry.GraphLoader, querier registry.Query, reg image.Registry, refs []image.Reference, mode registry.Mode, overwrite bool) error {
-	unpackedImageMap := make(map[image.Reference]string, 0)
+// unpackedImage is the directory an image is unpacked to.
+type unpackedImage struct {
+	ref image.Reference
+	dir string
+}
+
+// unpackImages pulls and unpacks images concurrently, at most concurrency at a time, returning them in the order of refs.
+// Images referenced more than once are unpacked once, and pulls share the registry's store, so layers shared
+// by several images are stored once. Errors unpacking any of the images are reported together, after all have been tried.
+// Images are verified by verifier first, if it's set.
+// The returned cleanup function removes the unpacked images, and must be called even if there's an error.
+func unpackImages(ctx context.Context, reg image.Registry, refs []image.Reference, concurrency int, verifier signature.Verifier, logger *logrus.Entry) ([]unpackedImage, func(), error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	unique := make([]image.Reference, 0, len(refs))
+	seen := make(map[string]struct{}, len(refs))
 	for _, ref := range refs {
-		to, from, cleanup, err := unpackImage(ctx, reg, ref)
-		if err != nil {
-			return err
+		if _, ok := seen[ref.String()]; ok {
+			continue
+		}
+		seen[ref.String()] = struct{}{}
+		unique = append(unique, ref)
+	}
+
+	var (
+		unpacked = make([]unpackedImage, len(unique))
+		cleanups = make([]func(), len(unique))
+		errs     = make([]error, len(unique))
+		done     int32
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, concurrency)
+	)
+	for i, ref := range unique {
+		wg.Add(1)
+		go func(i int, ref image.Reference) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			to, from, cleanup, err := unpackImage(ctx, reg, verifier, ref)
+			cleanups[i] = cleanup
+			n := atomic.AddInt32(&done, 1)
+			if err != nil {
+				errs[i] = fmt.Errorf("error unpacking bundle image %s: %v", ref, err)
+				logger.WithError(err).Warnf("failed to unpack bundle image %s (%d/%d)", ref, n, len(unique))
+				return
+			}
+			unpacked[i] = unpackedImage{ref: to, dir: from}
+			logger.Infof("unpacked bundle image %s (%d/%d)", ref, n, len(unique))
+		}(i, ref)
+	}
+	wg.Wait()
+
+	cleanup := func() {
+		for _, cleanup := range cleanups {
+			if cleanup != nil {
+				cleanup()
+			}
 		}
-		unpackedImageMap[to] = from
-		defer cleanup()
+	}
+	if err := utilerrors.NewAggregate(errs); err != nil {
+		return nil, cleanup, err
+	}
+	return unpacked, cleanup, nil
+}
+
+func populate(ctx context.Context, loader registry.Load, graphLoader registry.GraphLoader, querier registry.Query, reg image.Registry, refs []image.Reference, mode registry.Mode, overwrite bool, concurrency int, verifier signature.Verifier, logger *logrus.Entry) error {
+	unpacked, cleanup, err := unpackImages(ctx, reg, refs, concurrency, verifier, logger)
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+	unpackedImageMap := make(map[image.Reference]string, len(unpacked))
+	for _, u := range unpacked {
+		unpackedImageMap[u.ref] = u.dir
 	}
 
 	overwriteImageMap := make(map[string]map[image.Reference]string, 0)
 	if overwrite {
 		// find all bundles that are attempting to overwrite
-		for to, from := range unpackedImageMap {
-			img, err := registry.NewImageInput(to, from)
+		for _, u := range unpacked {
+			img, err := registry.NewImageInput(u.ref, u.dir)
 			if err != nil {
 				return err
 			}
@@ -160,37 +256,27 @@ func populate(ctx context.Context, loader registry.Load, graphLoader registry.Gr
 			if err != nil {
 				return err
 			}
-			type unpackedImage struct {
-				to      image.Reference
-				from    string
-				cleanup func()
-				err     error
-			}
-			unpacked := make(chan unpackedImage)
-			for bundle := range bundles {
-				// parallelize image pulls
-				go func(bundle registry.BundleKey, img *registry.ImageInput) {
-					if bundle.CsvName != img.Bundle.Name {
-						to, from, cleanup, err := unpackImage(ctx, reg, image.SimpleReference(bundle.BundlePath))
-						unpacked <- unpackedImage{to: to, from: from, cleanup: cleanup, err: err}
-					} else {
-						unpacked <- unpackedImage{to: to, from: from, cleanup: func() { return }, err: nil}
-					}
-				}(bundle, img)
-			}
 			if _, ok := overwriteImageMap[img.Bundle.Package]; !ok {
 				overwriteImageMap[img.Bundle.Package] = make(map[image.Reference]string, 0)
 			}
-			for i := 0; i < len(bundles); i++ {
-				unpack := <-unpacked
-				if unpack.err != nil {
-					return unpack.err
-				}
-				overwriteImageMap[img.Bundle.Package][unpack.to] = unpack.from
-				if _, ok := unpackedImageMap[unpack.to]; ok {
-					delete(unpackedImageMap, unpack.to)
+			// the overwriting bundle replaces the bundle of the same name, and the others are pulled again,
+			// without verifying them, since they're in the index already
+			overwriteImageMap[img.Bundle.Package][u.ref] = u.dir
+			delete(unpackedImageMap, u.ref)
+			var others []image.Reference
+			for bundle := range bundles {
+				if bundle.CsvName != img.Bundle.Name {
+					others = append(others, image.SimpleReference(bundle.BundlePath))
 				}
-				defer unpack.cleanup()
+			}
+			reAdded, cleanup, err := unpackImages(ctx, reg, others, concurrency, nil, logger)
+			defer cleanup()
+			if err != nil {
+				return err
+			}
+			for _, r := range reAdded {
+				overwriteImageMap[img.Bundle.Package][r.ref] = r.dir
+				delete(unpackedImageMap, r.ref)
 			}
 		}
 	}
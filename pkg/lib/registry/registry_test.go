@@ -5,9 +5,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"testing/fstest"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 
 	"github.com/operator-framework/operator-registry/internal/model"
@@ -227,7 +232,7 @@ func TestUnpackImage(t *testing.T) {
 					FS: fstest.MapFS{},
 				}
 			}
-			ref, _, cleanup, err := unpackImage(context.TODO(), &image.MockRegistry{RemoteImages: images}, tt.srcImage)
+			ref, _, cleanup, err := unpackImage(context.TODO(), &image.MockRegistry{RemoteImages: images}, nil, tt.srcImage)
 			if cleanup != nil {
 				cleanup()
 			}
@@ -241,3 +246,107 @@ func TestUnpackImage(t *testing.T) {
 		})
 	}
 }
+
+// countingRegistry counts the pulls of a registry, and the most in flight at once.
+type countingRegistry struct {
+	image.Registry
+	pulls, inFlight, maxInFlight int32
+}
+
+func (r *countingRegistry) Pull(ctx context.Context, ref image.Reference) error {
+	atomic.AddInt32(&r.pulls, 1)
+	n := atomic.AddInt32(&r.inFlight, 1)
+	defer atomic.AddInt32(&r.inFlight, -1)
+	for {
+		max := atomic.LoadInt32(&r.maxInFlight)
+		if n <= max || atomic.CompareAndSwapInt32(&r.maxInFlight, max, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return r.Registry.Pull(ctx, ref)
+}
+
+// fakeVerifier verifies the images it has references by digest for.
+type fakeVerifier map[image.Reference]image.Reference
+
+func (v fakeVerifier) Verify(_ context.Context, ref image.Reference) (image.Reference, error) {
+	verified, ok := v[ref]
+	if !ok {
+		return nil, fmt.Errorf("no valid signature found for %s", ref)
+	}
+	return verified, nil
+}
+
+func TestUnpackImages(t *testing.T) {
+	images := map[image.Reference]*image.MockImage{}
+	var refs []image.Reference
+	for i := 0; i < 20; i++ {
+		ref := image.SimpleReference(fmt.Sprintf("image-%d", i))
+		images[ref] = &image.MockImage{
+			FS: fstest.MapFS{"ref": &fstest.MapFile{Data: []byte(ref)}},
+		}
+		refs = append(refs, ref)
+	}
+	logger := logrus.NewEntry(logrus.New())
+
+	t.Run("Concurrent", func(t *testing.T) {
+		reg := &countingRegistry{Registry: &image.MockRegistry{RemoteImages: images}}
+		unpacked, cleanup, err := unpackImages(context.TODO(), reg, append(refs, refs[0], refs[1]), 4, nil, logger)
+		defer cleanup()
+		require.NoError(t, err)
+
+		// Duplicates are pulled once, and images are returned in order
+		require.EqualValues(t, len(refs), reg.pulls)
+		require.LessOrEqual(t, reg.maxInFlight, int32(4))
+		require.Greater(t, reg.maxInFlight, int32(1))
+		require.Len(t, unpacked, len(refs))
+		for i, u := range unpacked {
+			require.Equal(t, refs[i], u.ref)
+			data, err := ioutil.ReadFile(filepath.Join(u.dir, "ref"))
+			require.NoError(t, err)
+			require.Equal(t, refs[i].String(), string(data))
+		}
+
+		cleanup()
+		for _, u := range unpacked {
+			require.NoDirExists(t, u.dir)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		reg := &countingRegistry{Registry: &image.MockRegistry{RemoteImages: images}}
+		withMissing := append([]image.Reference{image.SimpleReference("missing-1")}, refs...)
+		withMissing = append(withMissing, image.SimpleReference("missing-2"))
+		_, cleanup, err := unpackImages(context.TODO(), reg, withMissing, 4, nil, logger)
+		defer cleanup()
+
+		// Every image is tried, and all errors are reported
+		require.EqualValues(t, len(withMissing), reg.pulls)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing-1")
+		require.Contains(t, err.Error(), "missing-2")
+	})
+
+	t.Run("Verified", func(t *testing.T) {
+		reg := &countingRegistry{Registry: &image.MockRegistry{RemoteImages: images}}
+		// The verified image is the one unpacked, under the reference it was given.
+		verifier := fakeVerifier{refs[0]: refs[1]}
+		unpacked, cleanup, err := unpackImages(context.TODO(), reg, refs[:1], 4, verifier, logger)
+		defer cleanup()
+		require.NoError(t, err)
+		require.Len(t, unpacked, 1)
+		require.Equal(t, refs[0], unpacked[0].ref)
+		data, err := ioutil.ReadFile(filepath.Join(unpacked[0].dir, "ref"))
+		require.NoError(t, err)
+		require.Equal(t, refs[1].String(), string(data))
+
+		// Images that fail verification aren't pulled.
+		_, cleanup, err = unpackImages(context.TODO(), reg, refs[2:4], 4, verifier, logger)
+		defer cleanup()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no valid signature found for "+refs[2].String())
+		require.Contains(t, err.Error(), "no valid signature found for "+refs[3].String())
+		require.EqualValues(t, 1, reg.pulls)
+	})
+}
@@ -0,0 +1,371 @@
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"sync"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/remotes"
+	"github.com/docker/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+)
+
+// DefaultBlobConcurrency is the default number of blobs copied at once.
+const DefaultBlobConcurrency = 8
+
+// maxManifestSize limits the size of the manifests read from registries.
+const maxManifestSize = 4 << 20
+
+type RegistryMirrorerOptions struct {
+	Logger            *logrus.Entry
+	ResolverConfigDir string
+	SkipTLS           bool
+	RootCAs           *x509.CertPool
+	BlobConcurrency   int
+}
+
+func (o *RegistryMirrorerOptions) Validate() error {
+	if o.BlobConcurrency < 1 {
+		return fmt.Errorf("blob concurrency must be at least 1, got %d", o.BlobConcurrency)
+	}
+	return nil
+}
+
+// Apply sequentially applies the given options to the config.
+func (o *RegistryMirrorerOptions) Apply(options []RegistryMirrorerOption) {
+	for _, option := range options {
+		option(o)
+	}
+}
+
+type RegistryMirrorerOption func(*RegistryMirrorerOptions)
+
+func DefaultRegistryMirrorerOptions() *RegistryMirrorerOptions {
+	return &RegistryMirrorerOptions{
+		Logger:          logrus.NewEntry(logrus.New()),
+		BlobConcurrency: DefaultBlobConcurrency,
+	}
+}
+
+func WithLogger(logger *logrus.Entry) RegistryMirrorerOption {
+	return func(o *RegistryMirrorerOptions) {
+		o.Logger = logger
+	}
+}
+
+func WithResolverConfigDir(dir string) RegistryMirrorerOption {
+	return func(o *RegistryMirrorerOptions) {
+		o.ResolverConfigDir = dir
+	}
+}
+
+func WithSkipTLS(skip bool) RegistryMirrorerOption {
+	return func(o *RegistryMirrorerOptions) {
+		o.SkipTLS = skip
+	}
+}
+
+func WithRootCAs(roots *x509.CertPool) RegistryMirrorerOption {
+	return func(o *RegistryMirrorerOptions) {
+		o.RootCAs = roots
+	}
+}
+
+func WithBlobConcurrency(n int) RegistryMirrorerOption {
+	return func(o *RegistryMirrorerOptions) {
+		o.BlobConcurrency = n
+	}
+}
+
+// RegistryMirrorer is an ImageMirrorer that copies images from one remote registry to another itself,
+// rather than with external tooling. Manifest lists are copied along with the images of every platform they list.
+//
+// Content already in the destination registry is skipped, so mirroring again resumes a run that was interrupted.
+// All content is verified against its digest as it's copied.
+type RegistryMirrorer struct {
+	logger      *logrus.Entry
+	concurrency int
+	resolver    remotes.Resolver
+	newResolver func() (remotes.Resolver, error)
+
+	mu sync.Mutex
+	// pushers holds a resolver for each destination repository, since a resolver tracks the content it pushed
+	// by digest alone, and would skip content already pushed to another repository.
+	pushers map[string]remotes.Resolver
+}
+
+var _ ImageMirrorer = &RegistryMirrorer{}
+
+func NewRegistryMirrorer(options ...RegistryMirrorerOption) (*RegistryMirrorer, error) {
+	config := DefaultRegistryMirrorerOptions()
+	config.Apply(options)
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	newResolver := func() (remotes.Resolver, error) {
+		return containerdregistry.NewResolver(config.ResolverConfigDir, config.SkipTLS, config.RootCAs)
+	}
+	resolver, err := newResolver()
+	if err != nil {
+		return nil, fmt.Errorf("error creating resolver: %v", err)
+	}
+	return &RegistryMirrorer{
+		logger:      config.Logger,
+		concurrency: config.BlobConcurrency,
+		resolver:    resolver,
+		newResolver: newResolver,
+		pushers:     map[string]remotes.Resolver{},
+	}, nil
+}
+
+// Mirror copies each source image of a mapping to its destination. Every image is tried,
+// and the errors mirroring any of them are reported together.
+func (m *RegistryMirrorer) Mirror(mapping map[string]string) error {
+	srcs := make([]string, 0, len(mapping))
+	for src := range mapping {
+		srcs = append(srcs, src)
+	}
+	sort.Strings(srcs)
+
+	var errs []error
+	for i, src := range srcs {
+		dst := mapping[src]
+		if err := m.MirrorImage(context.TODO(), src, dst); err != nil {
+			m.logger.WithError(err).Warnf("failed to mirror %s to %s (%d/%d)", src, dst, i+1, len(srcs))
+			errs = append(errs, err)
+			continue
+		}
+		m.logger.Infof("mirrored %s to %s (%d/%d)", src, dst, i+1, len(srcs))
+	}
+	return errors.NewAggregate(errs)
+}
+
+// MirrorImage copies an image from one remote registry to another.
+// If the destination is a reference by digest, it must be the digest of the source image.
+func (m *RegistryMirrorer) MirrorImage(ctx context.Context, src, dst string) error {
+	dstNamed, err := reference.ParseNormalizedNamed(dst)
+	if err != nil {
+		return fmt.Errorf("error parsing destination %s: %v", dst, err)
+	}
+
+	name, desc, err := m.resolver.Resolve(ctx, src)
+	if err != nil {
+		return fmt.Errorf("error resolving %s: %v", src, err)
+	}
+	if digested, ok := dstNamed.(reference.Digested); ok && digested.Digest() != desc.Digest {
+		return fmt.Errorf("can't mirror %s with digest %s to %s", src, desc.Digest, dst)
+	}
+	fetcher, err := m.resolver.Fetcher(ctx, name)
+	if err != nil {
+		return err
+	}
+	repo := reference.TrimNamed(dstNamed)
+	resolver, err := m.pusher(repo.Name())
+	if err != nil {
+		return err
+	}
+
+	c := &imageCopier{
+		fetcher:  fetcher,
+		resolver: resolver,
+		repo:     repo,
+		sem:      make(chan struct{}, m.concurrency),
+		logger:   m.logger.WithField("image", src),
+	}
+	// The image is tagged once all of its content is copied, so that it's never tagged incomplete.
+	tag := ""
+	if tagged, ok := dstNamed.(reference.Tagged); ok {
+		tag = tagged.Tag()
+	}
+	if err := c.copyManifest(ctx, desc, tag); err != nil {
+		return fmt.Errorf("error mirroring %s to %s: %v", src, dst, err)
+	}
+	return nil
+}
+
+// pusher returns the resolver pushing to a destination repository.
+func (m *RegistryMirrorer) pusher(repo string) (remotes.Resolver, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if resolver, ok := m.pushers[repo]; ok {
+		return resolver, nil
+	}
+	resolver, err := m.newResolver()
+	if err != nil {
+		return nil, fmt.Errorf("error creating resolver: %v", err)
+	}
+	m.pushers[repo] = resolver
+	return resolver, nil
+}
+
+// imageCopier copies the content of an image to a repository.
+type imageCopier struct {
+	fetcher  remotes.Fetcher
+	resolver remotes.Resolver
+	repo     reference.Named
+	// sem limits the blobs copied at once.
+	sem    chan struct{}
+	logger *logrus.Entry
+}
+
+// copyManifest copies a manifest after the content it references, and tags it if tag is set.
+// Manifest lists are copied after the manifests they list.
+func (c *imageCopier) copyManifest(ctx context.Context, desc ocispec.Descriptor, tag string) error {
+	data, err := c.fetchManifest(ctx, desc)
+	if err != nil {
+		return err
+	}
+
+	switch desc.MediaType {
+	case images.MediaTypeDockerSchema2ManifestList, ocispec.MediaTypeImageIndex:
+		var index ocispec.Index
+		if err := json.Unmarshal(data, &index); err != nil {
+			return fmt.Errorf("error parsing manifest list %s: %v", desc.Digest, err)
+		}
+		for _, manifest := range index.Manifests {
+			if err := c.copyManifest(ctx, manifest, ""); err != nil {
+				return err
+			}
+		}
+	case images.MediaTypeDockerSchema2Manifest, ocispec.MediaTypeImageManifest:
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return fmt.Errorf("error parsing manifest %s: %v", desc.Digest, err)
+		}
+		if err := c.copyBlobs(ctx, append([]ocispec.Descriptor{manifest.Config}, manifest.Layers...)); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported manifest media type %s", desc.MediaType)
+	}
+
+	// Manifests are pushed by digest, unless they're tagged.
+	ref, err := reference.WithDigest(c.repo, desc.Digest)
+	if err != nil {
+		return err
+	}
+	if tag != "" {
+		tagged, err := reference.WithTag(c.repo, tag)
+		if err != nil {
+			return err
+		}
+		ref, err = reference.WithDigest(tagged, desc.Digest)
+		if err != nil {
+			return err
+		}
+		// Track the tagged push apart from pushes of the manifest by digest, or under other tags.
+		ctx = remotes.WithMediaTypeKeyPrefix(ctx, desc.MediaType, "tag-"+tag)
+	}
+	pusher, err := c.resolver.Pusher(ctx, ref.String())
+	if err != nil {
+		return err
+	}
+	return c.push(ctx, pusher, desc, func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	})
+}
+
+// copyBlobs copies blobs concurrently. Blobs that aren't stored in registries, such as foreign layers, are skipped.
+func (c *imageCopier) copyBlobs(ctx context.Context, blobs []ocispec.Descriptor) error {
+	ref, err := reference.WithDigest(c.repo, blobs[0].Digest)
+	if err != nil {
+		return err
+	}
+	pusher, err := c.resolver.Pusher(ctx, ref.String())
+	if err != nil {
+		return err
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	for _, blob := range blobs {
+		if len(blob.URLs) > 0 {
+			c.logger.Debugf("skipping foreign blob %s", blob.Digest)
+			continue
+		}
+		blob := blob
+		g.Go(func() error {
+			select {
+			case c.sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-c.sem }()
+
+			return c.push(ctx, pusher, blob, func() (io.ReadCloser, error) {
+				return c.fetcher.Fetch(ctx, blob)
+			})
+		})
+	}
+	return g.Wait()
+}
+
+// push copies content to the destination unless it's there already, verifying its size and digest.
+func (c *imageCopier) push(ctx context.Context, pusher remotes.Pusher, desc ocispec.Descriptor, open func() (io.ReadCloser, error)) error {
+	w, err := pusher.Push(ctx, desc)
+	if errdefs.IsAlreadyExists(err) {
+		c.logger.Debugf("%s exists, skipping", desc.Digest)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error pushing %s: %v", desc.Digest, err)
+	}
+	defer w.Close()
+
+	rc, err := open()
+	if err != nil {
+		return fmt.Errorf("error fetching %s: %v", desc.Digest, err)
+	}
+	defer rc.Close()
+
+	verifier := desc.Digest.Verifier()
+	n, err := io.Copy(w, io.TeeReader(io.LimitReader(rc, desc.Size+1), verifier))
+	if err != nil {
+		return fmt.Errorf("error copying %s: %v", desc.Digest, err)
+	}
+	if n != desc.Size || !verifier.Verified() {
+		return fmt.Errorf("content of %s doesn't match its digest", desc.Digest)
+	}
+	if err := w.Commit(ctx, desc.Size, desc.Digest); err != nil && !errdefs.IsAlreadyExists(err) {
+		return fmt.Errorf("error committing %s: %v", desc.Digest, err)
+	}
+	c.logger.Debugf("copied %s (%d bytes)", desc.Digest, desc.Size)
+	return nil
+}
+
+// fetchManifest reads a manifest of limited size, verifying its digest.
+func (c *imageCopier) fetchManifest(ctx context.Context, desc ocispec.Descriptor) ([]byte, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, err
+	}
+	if desc.Size > maxManifestSize {
+		return nil, fmt.Errorf("manifest %s is too large, %d bytes", desc.Digest, desc.Size)
+	}
+	rc, err := c.fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching manifest %s: %v", desc.Digest, err)
+	}
+	defer rc.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(rc, maxManifestSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching manifest %s: %v", desc.Digest, err)
+	}
+	if actual := desc.Digest.Algorithm().FromBytes(data); actual != desc.Digest {
+		return nil, fmt.Errorf("manifest %s has digest %s", desc.Digest, actual)
+	}
+	return data, nil
+}
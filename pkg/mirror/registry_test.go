@@ -0,0 +1,253 @@
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync/atomic"
+	"testing"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/remotes"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
+	libimage "github.com/operator-framework/operator-registry/pkg/lib/image"
+)
+
+// testRegistry pushes and fetches the content of images in a registry.
+type testRegistry struct {
+	t        *testing.T
+	host     string
+	resolver remotes.Resolver
+	roots    *x509.CertPool
+}
+
+func (r *testRegistry) push(ref string, desc ocispec.Descriptor, data []byte) {
+	// Content is pushed with a resolver of its own, since resolvers skip content they pushed to any repository.
+	resolver, err := containerdregistry.NewResolver("", false, r.roots)
+	require.NoError(r.t, err)
+	pusher, err := resolver.Pusher(context.TODO(), ref)
+	require.NoError(r.t, err)
+	w, err := pusher.Push(context.TODO(), desc)
+	if errdefs.IsAlreadyExists(err) {
+		return
+	}
+	require.NoError(r.t, err)
+	defer w.Close()
+	_, err = w.Write(data)
+	require.NoError(r.t, err)
+	require.NoError(r.t, w.Commit(context.TODO(), desc.Size, desc.Digest))
+}
+
+func (r *testRegistry) pushJSON(ref, mediaType string, v interface{}) ocispec.Descriptor {
+	data, err := json.Marshal(v)
+	require.NoError(r.t, err)
+	desc := ocispec.Descriptor{MediaType: mediaType, Digest: digest.FromBytes(data), Size: int64(len(data))}
+	r.push(ref, desc, data)
+	return desc
+}
+
+// pushImage pushes an image with a layer of contents, tagging it if ref is tagged.
+func (r *testRegistry) pushImage(ref, contents string) ocispec.Descriptor {
+	layer := []byte(contents)
+	layerDesc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: digest.FromBytes(layer), Size: int64(len(layer))}
+	r.push(ref, layerDesc, layer)
+	return r.pushJSON(ref, ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Config:    r.pushJSON(ref, ocispec.MediaTypeImageConfig, ocispec.Image{Architecture: "amd64", OS: "linux"}),
+		Layers:    []ocispec.Descriptor{layerDesc},
+	})
+}
+
+func (r *testRegistry) fetch(ref string, desc ocispec.Descriptor) []byte {
+	fetcher, err := r.resolver.Fetcher(context.TODO(), ref)
+	require.NoError(r.t, err)
+	rc, err := fetcher.Fetch(context.TODO(), desc)
+	require.NoError(r.t, err)
+	defer rc.Close()
+	data, err := ioutil.ReadAll(rc)
+	require.NoError(r.t, err)
+	return data
+}
+
+// requireImage requires an image to be in the registry, with all of its content.
+func (r *testRegistry) requireImage(ref string, expected ocispec.Descriptor) {
+	_, desc, err := r.resolver.Resolve(context.TODO(), ref)
+	require.NoError(r.t, err)
+	require.Equal(r.t, expected.Digest, desc.Digest)
+
+	var requireContent func(desc ocispec.Descriptor)
+	requireContent = func(desc ocispec.Descriptor) {
+		data := r.fetch(ref, desc)
+		require.Equal(r.t, desc.Digest, digest.FromBytes(data))
+		switch desc.MediaType {
+		case ocispec.MediaTypeImageIndex, images.MediaTypeDockerSchema2ManifestList:
+			var index ocispec.Index
+			require.NoError(r.t, json.Unmarshal(data, &index))
+			for _, m := range index.Manifests {
+				requireContent(m)
+			}
+		case ocispec.MediaTypeImageManifest, images.MediaTypeDockerSchema2Manifest:
+			var manifest ocispec.Manifest
+			require.NoError(r.t, json.Unmarshal(data, &manifest))
+			requireContent(manifest.Config)
+			for _, l := range manifest.Layers {
+				requireContent(l)
+			}
+		}
+	}
+	requireContent(desc)
+}
+
+// countingResolver counts the blobs fetched through a resolver, optionally corrupting them.
+type countingResolver struct {
+	remotes.Resolver
+	fetches int32
+	corrupt bool
+}
+
+func (r *countingResolver) Fetcher(ctx context.Context, ref string) (remotes.Fetcher, error) {
+	fetcher, err := r.Resolver.Fetcher(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return remotes.FetcherFunc(func(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+		rc, err := fetcher.Fetch(ctx, desc)
+		if err != nil || !(images.IsLayerType(desc.MediaType) || images.IsKnownConfig(desc.MediaType)) {
+			return rc, err
+		}
+		atomic.AddInt32(&r.fetches, 1)
+		if !r.corrupt {
+			return rc, nil
+		}
+		defer rc.Close()
+		data, err := ioutil.ReadAll(rc)
+		if err != nil {
+			return nil, err
+		}
+		data[0] ^= 0xff
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}), nil
+}
+
+func TestRegistryMirrorer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	roots, err := x509.SystemCertPool()
+	require.NoError(t, err)
+	newTestRegistry := func() *testRegistry {
+		host, cafile, err := libimage.RunDockerRegistry(ctx, "")
+		require.NoError(t, err)
+		certs, err := ioutil.ReadFile(cafile)
+		require.NoError(t, err)
+		require.True(t, roots.AppendCertsFromPEM(certs))
+		resolver, err := containerdregistry.NewResolver("", false, roots)
+		require.NoError(t, err)
+		return &testRegistry{t: t, host: host, resolver: resolver, roots: roots}
+	}
+	src, dst := newTestRegistry(), newTestRegistry()
+
+	single := src.pushImage(src.host+"/olmtest/single:v1", "single")
+	amd64 := src.pushImage(src.host+"/olmtest/multi", "amd64")
+	arm64 := src.pushImage(src.host+"/olmtest/multi", "arm64")
+	amd64.Platform = &ocispec.Platform{Architecture: "amd64", OS: "linux"}
+	arm64.Platform = &ocispec.Platform{Architecture: "arm64", OS: "linux"}
+	multi := src.pushJSON(src.host+"/olmtest/multi:v1", ocispec.MediaTypeImageIndex, ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Manifests: []ocispec.Descriptor{amd64, arm64},
+	})
+
+	newMirrorer := func(t *testing.T) (*RegistryMirrorer, *countingResolver) {
+		logger := logrus.New()
+		logger.SetOutput(ioutil.Discard)
+		m, err := NewRegistryMirrorer(WithRootCAs(roots), WithBlobConcurrency(2), WithLogger(logrus.NewEntry(logger)))
+		require.NoError(t, err)
+		counting := &countingResolver{Resolver: m.resolver}
+		m.resolver = counting
+		return m, counting
+	}
+
+	mapping := map[string]string{
+		src.host + "/olmtest/single:v1":                        dst.host + "/mirrored/single:v1",
+		src.host + "/olmtest/multi:v1":                         dst.host + "/mirrored/multi:v1",
+		src.host + "/olmtest/single@" + single.Digest.String(): dst.host + "/bydigest/single@" + single.Digest.String(),
+	}
+
+	t.Run("Mirror", func(t *testing.T) {
+		m, counting := newMirrorer(t)
+		require.NoError(t, m.Mirror(mapping))
+		// The images share a config, which is copied once to each repository.
+		require.EqualValues(t, 7, counting.fetches)
+
+		dst.requireImage(dst.host+"/mirrored/single:v1", single)
+		dst.requireImage(dst.host+"/mirrored/multi:v1", multi)
+		dst.requireImage(dst.host+"/mirrored/multi@"+amd64.Digest.String(), amd64)
+		dst.requireImage(dst.host+"/mirrored/multi@"+arm64.Digest.String(), arm64)
+		dst.requireImage(dst.host+"/bydigest/single@"+single.Digest.String(), single)
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		// Content already mirrored isn't copied again.
+		m, counting := newMirrorer(t)
+		require.NoError(t, m.Mirror(mapping))
+		require.EqualValues(t, 0, counting.fetches)
+
+		// Content copied before an interrupted run isn't copied again.
+		partial := src.pushImage(src.host+"/olmtest/partial:v1", "partial")
+		config := dst.pushJSON(dst.host+"/mirrored/partial:v1", ocispec.MediaTypeImageConfig, ocispec.Image{Architecture: "amd64", OS: "linux"})
+		m, counting = newMirrorer(t)
+		require.NoError(t, m.MirrorImage(ctx, src.host+"/olmtest/partial:v1", dst.host+"/mirrored/partial:v1"))
+		require.EqualValues(t, 1, counting.fetches)
+		dst.requireImage(dst.host+"/mirrored/partial:v1", partial)
+		dst.requireImage(dst.host+"/mirrored/partial@"+partial.Digest.String(), partial)
+		require.Equal(t, config.Digest, digest.FromBytes(dst.fetch(dst.host+"/mirrored/partial:v1", config)))
+	})
+
+	t.Run("Retag", func(t *testing.T) {
+		m, _ := newMirrorer(t)
+		require.NoError(t, m.MirrorImage(ctx, src.host+"/olmtest/single:v1", dst.host+"/mirrored/retag:v1"))
+		require.NoError(t, m.MirrorImage(ctx, src.host+"/olmtest/single:v1", dst.host+"/mirrored/retag:v2"))
+		dst.requireImage(dst.host+"/mirrored/retag:v1", single)
+		dst.requireImage(dst.host+"/mirrored/retag:v2", single)
+	})
+
+	t.Run("Corrupt", func(t *testing.T) {
+		m, counting := newMirrorer(t)
+		counting.corrupt = true
+		err := m.MirrorImage(ctx, src.host+"/olmtest/multi:v1", dst.host+"/corrupt/multi:v1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "doesn't match its digest")
+
+		// Nothing referencing corrupt content is pushed.
+		_, _, err = dst.resolver.Resolve(ctx, dst.host+"/corrupt/multi:v1")
+		require.Error(t, err)
+		_, _, err = dst.resolver.Resolve(ctx, dst.host+"/corrupt/multi@"+amd64.Digest.String())
+		require.Error(t, err)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		m, _ := newMirrorer(t)
+		err := m.Mirror(map[string]string{
+			src.host + "/olmtest/missing:v1": dst.host + "/errors/missing:v1",
+			src.host + "/olmtest/single:v1":  dst.host + "/errors/single@" + multi.Digest.String(),
+			src.host + "/olmtest/multi:v1":   dst.host + "/errors/multi:v1",
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), fmt.Sprintf("error resolving %s/olmtest/missing:v1", src.host))
+		require.Contains(t, err.Error(), fmt.Sprintf("can't mirror %s/olmtest/single:v1 with digest %s", src.host, single.Digest))
+
+		// Images that can be mirrored still are.
+		dst.requireImage(dst.host+"/errors/multi:v1", multi)
+	})
+}
@@ -0,0 +1,65 @@
+package proxy
+
+import (
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds the number of responses cached at once. Expired responses are
+// evicted when it is reached, and if that doesn't free up space the new response isn't cached.
+const maxCacheEntries = 10000
+
+// responseCache caches responses for a fixed time. Cached responses are shared between
+// calls, and must not be modified.
+type responseCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{ttl: ttl, now: time.Now, entries: map[string]cacheEntry{}}
+}
+
+// load returns the cached response for key, or the response returned by fetch if there
+// is none. Responses are only cached if fetch reports they are complete.
+func (c *responseCache) load(key string, fetch func() (value interface{}, complete bool, err error)) (interface{}, error) {
+	if c.ttl <= 0 || key == "" {
+		value, _, err := fetch()
+		return value, err
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expires) {
+		return entry.value, nil
+	}
+
+	value, complete, err := fetch()
+	if err != nil || !complete {
+		return value, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.entries) >= maxCacheEntries {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	if len(c.entries) < maxCacheEntries {
+		c.entries[key] = cacheEntry{value: value, expires: now.Add(c.ttl)}
+	}
+	return value, nil
+}
@@ -0,0 +1,549 @@
+package proxy
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	health "github.com/operator-framework/operator-registry/pkg/api/grpc_health_v1"
+	"github.com/operator-framework/operator-registry/pkg/client"
+	"github.com/operator-framework/operator-registry/pkg/server"
+)
+
+// DefaultCacheTTL is how long responses are cached by default.
+const DefaultCacheTTL = 30 * time.Second
+
+// registryService is the name clients check the health of the registry as a whole with.
+const registryService = "Registry"
+
+// Upstream is a registry served by the proxy.
+type Upstream struct {
+	// Name identifies the upstream in logs, and is the service name its health is
+	// reported under.
+	Name string
+	// Priority decides which upstream serves a package that is in more than one of them:
+	// the one with the highest priority. Upstreams with equal priority are ranked in the
+	// order they were given.
+	Priority int
+	Client   client.Interface
+}
+
+// upstream is an Upstream and the status of its last health check or call.
+type upstream struct {
+	Upstream
+
+	mu     sync.RWMutex
+	status health.HealthCheckResponse_ServingStatus
+}
+
+func (u *upstream) getStatus() health.HealthCheckResponse_ServingStatus {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.status
+}
+
+func (u *upstream) setStatus(status health.HealthCheckResponse_ServingStatus) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.status = status
+}
+
+// Server serves the registry API by fanning requests out to a set of upstream registries
+// and merging their responses. Each package is served by the highest priority upstream
+// that contains it, so bundles and channel entries of a package that are in lower priority
+// upstreams are left out. Upstreams that fail are left out of responses until they
+// recover, and responses are only cached if every upstream answered.
+type Server struct {
+	api.UnimplementedRegistryServer
+
+	upstreams []*upstream
+	cache     *responseCache
+	logger    *logrus.Entry
+}
+
+var _ api.RegistryServer = &Server{}
+
+type Option func(*Server)
+
+// WithCacheTTL caches responses for ttl. Caching is disabled if ttl is 0.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(s *Server) {
+		s.cache.ttl = ttl
+	}
+}
+
+// WithLogger logs the failures of upstreams to logger.
+func WithLogger(logger *logrus.Entry) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+func NewServer(upstreams []Upstream, opts ...Option) (*Server, error) {
+	if len(upstreams) == 0 {
+		return nil, fmt.Errorf("at least one upstream is required")
+	}
+	s := &Server{
+		cache:  newResponseCache(DefaultCacheTTL),
+		logger: logrus.NewEntry(logrus.StandardLogger()),
+	}
+	names := map[string]struct{}{}
+	for _, u := range upstreams {
+		if u.Name == "" {
+			return nil, fmt.Errorf("upstream names must not be empty")
+		}
+		if u.Name == registryService {
+			return nil, fmt.Errorf("upstream name %q is reserved", u.Name)
+		}
+		if _, ok := names[u.Name]; ok {
+			return nil, fmt.Errorf("duplicate upstream name %q", u.Name)
+		}
+		names[u.Name] = struct{}{}
+		s.upstreams = append(s.upstreams, &upstream{Upstream: u})
+	}
+	sort.SliceStable(s.upstreams, func(i, j int) bool { return s.upstreams[i].Priority > s.upstreams[j].Priority })
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// CheckHealth checks the health of every upstream, waiting up to reconnectTimeout for
+// those with failing connections to recover.
+func (s *Server) CheckHealth(ctx context.Context, reconnectTimeout time.Duration) {
+	var wg sync.WaitGroup
+	for _, u := range s.upstreams {
+		wg.Add(1)
+		go func(u *upstream) {
+			defer wg.Done()
+			healthy, err := u.Client.HealthCheck(ctx, reconnectTimeout)
+			status := health.HealthCheckResponse_SERVING
+			if !healthy {
+				status = health.HealthCheckResponse_NOT_SERVING
+			}
+			if previous := u.getStatus(); previous != status {
+				s.logger.WithError(err).WithFields(logrus.Fields{"upstream": u.Name, "status": status}).Info("upstream health changed")
+			}
+			u.setStatus(status)
+		}(u)
+	}
+	wg.Wait()
+}
+
+// MonitorHealth checks the health of every upstream immediately, and then every interval
+// until ctx is done.
+func (s *Server) MonitorHealth(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.CheckHealth(ctx, interval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// HealthStatus is a server.HealthChecker reporting the health of the upstream named by
+// service. The empty service and "Registry" refer to the proxy itself, which is serving
+// unless every upstream is known not to be.
+func (s *Server) HealthStatus(_ context.Context, service string) (health.HealthCheckResponse_ServingStatus, error) {
+	if service == "" || service == registryService {
+		for _, u := range s.upstreams {
+			if u.getStatus() != health.HealthCheckResponse_NOT_SERVING {
+				return health.HealthCheckResponse_SERVING, nil
+			}
+		}
+		return health.HealthCheckResponse_NOT_SERVING, nil
+	}
+	for _, u := range s.upstreams {
+		if u.Name == service {
+			return u.getStatus(), nil
+		}
+	}
+	return health.HealthCheckResponse_UNKNOWN, status.Errorf(codes.NotFound, "unknown service %q", service)
+}
+
+var _ server.HealthChecker = (&Server{}).HealthStatus
+
+// result is the response of an upstream to a call.
+type result struct {
+	upstream *upstream
+	value    interface{}
+	err      error
+}
+
+// fanOut calls every upstream concurrently, returning their results in priority order.
+// Upstreams that are unavailable are marked as not serving.
+func (s *Server) fanOut(ctx context.Context, call func(context.Context, client.Interface) (interface{}, error)) []result {
+	results := make([]result, len(s.upstreams))
+	var wg sync.WaitGroup
+	for i, u := range s.upstreams {
+		wg.Add(1)
+		go func(i int, u *upstream) {
+			defer wg.Done()
+			value, err := call(ctx, u.Client)
+			s.observe(u, err)
+			results[i] = result{upstream: u, value: value, err: err}
+		}(i, u)
+	}
+	wg.Wait()
+	return results
+}
+
+// observe marks u as not serving if err shows it's unavailable.
+func (s *Server) observe(u *upstream, err error) {
+	if status.Code(err) == codes.Unavailable && u.getStatus() != health.HealthCheckResponse_NOT_SERVING {
+		s.logger.WithError(err).WithField("upstream", u.Name).Warn("upstream unavailable")
+		u.setStatus(health.HealthCheckResponse_NOT_SERVING)
+	}
+}
+
+// owners returns the upstream that serves each package.
+func (s *Server) owners(ctx context.Context) (map[string]*upstream, error) {
+	value, err := s.cache.load("owners", func() (interface{}, bool, error) {
+		results := s.fanOut(ctx, func(ctx context.Context, c client.Interface) (interface{}, error) {
+			it, err := c.ListPackages(ctx)
+			if err != nil {
+				return nil, err
+			}
+			var names []string
+			for name := it.Next(); name != nil; name = it.Next() {
+				names = append(names, name.GetName())
+			}
+			return names, it.Error()
+		})
+		owners := map[string]*upstream{}
+		complete, err := s.merge(results, func(r result) {
+			for _, name := range r.value.([]string) {
+				if _, ok := owners[name]; !ok {
+					owners[name] = r.upstream
+				}
+			}
+		})
+		return owners, complete, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]*upstream), nil
+}
+
+// merge calls add with the results of the upstreams that succeeded, and logs the others.
+// It returns whether every upstream succeeded, or the first error if none did.
+func (s *Server) merge(results []result, add func(result)) (bool, error) {
+	var firstErr error
+	succeeded := 0
+	for _, r := range results {
+		if r.err != nil {
+			s.logger.WithError(r.err).WithField("upstream", r.upstream.Name).Debug("upstream call failed")
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			continue
+		}
+		succeeded++
+		add(r)
+	}
+	if succeeded == 0 {
+		return false, firstErr
+	}
+	return succeeded == len(results), nil
+}
+
+// owner returns the upstream that serves pkgName.
+func (s *Server) owner(ctx context.Context, pkgName string) (*upstream, error) {
+	owners, err := s.owners(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u, ok := owners[pkgName]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "package %q not found in any upstream", pkgName)
+	}
+	return u, nil
+}
+
+// callOwner calls the upstream that serves pkgName, caching its response under key.
+func (s *Server) callOwner(ctx context.Context, key, pkgName string, call func(context.Context, client.Interface) (interface{}, error)) (interface{}, error) {
+	return s.cache.load(key, func() (interface{}, bool, error) {
+		u, err := s.owner(ctx, pkgName)
+		if err != nil {
+			return nil, false, err
+		}
+		value, err := call(ctx, u.Client)
+		s.observe(u, err)
+		return value, err == nil, err
+	})
+}
+
+func (s *Server) ListPackages(req *api.ListPackageRequest, stream api.Registry_ListPackagesServer) error {
+	owners, err := s.owners(stream.Context())
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(owners))
+	for name := range owners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := stream.Send(&api.PackageName{Name: name}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) GetPackage(ctx context.Context, req *api.GetPackageRequest) (*api.Package, error) {
+	value, err := s.callOwner(ctx, cacheKey("GetPackage", req), req.GetName(), func(ctx context.Context, c client.Interface) (interface{}, error) {
+		return c.GetPackage(ctx, req.GetName())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*api.Package), nil
+}
+
+func (s *Server) GetBundle(ctx context.Context, req *api.GetBundleRequest) (*api.Bundle, error) {
+	value, err := s.callOwner(ctx, cacheKey("GetBundle", req), req.GetPkgName(), func(ctx context.Context, c client.Interface) (interface{}, error) {
+		return c.GetBundle(ctx, req.GetPkgName(), req.GetChannelName(), req.GetCsvName())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*api.Bundle), nil
+}
+
+func (s *Server) GetBundleForChannel(ctx context.Context, req *api.GetBundleInChannelRequest) (*api.Bundle, error) {
+	value, err := s.callOwner(ctx, cacheKey("GetBundleForChannel", req), req.GetPkgName(), func(ctx context.Context, c client.Interface) (interface{}, error) {
+		return c.GetBundleInPackageChannel(ctx, req.GetPkgName(), req.GetChannelName())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*api.Bundle), nil
+}
+
+func (s *Server) GetBundleThatReplaces(ctx context.Context, req *api.GetReplacementRequest) (*api.Bundle, error) {
+	value, err := s.callOwner(ctx, cacheKey("GetBundleThatReplaces", req), req.GetPkgName(), func(ctx context.Context, c client.Interface) (interface{}, error) {
+		return c.GetReplacementBundleInPackageChannel(ctx, req.GetCsvName(), req.GetPkgName(), req.GetChannelName())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*api.Bundle), nil
+}
+
+func (s *Server) ListBundles(req *api.ListBundlesRequest, stream api.Registry_ListBundlesServer) error {
+	bundles, err := s.listBundles(stream.Context())
+	if err != nil {
+		return err
+	}
+	for _, b := range bundles {
+		if err := stream.Send(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listBundles returns the bundles of every package, from the upstream that serves it.
+func (s *Server) listBundles(ctx context.Context) ([]*api.Bundle, error) {
+	owners, err := s.owners(ctx)
+	if err != nil {
+		return nil, err
+	}
+	value, err := s.cache.load(cacheKey("ListBundles", &api.ListBundlesRequest{}), func() (interface{}, bool, error) {
+		results := s.fanOut(ctx, func(ctx context.Context, c client.Interface) (interface{}, error) {
+			it, err := c.ListBundles(ctx)
+			if err != nil {
+				return nil, err
+			}
+			var bundles []*api.Bundle
+			for b := it.Next(); b != nil; b = it.Next() {
+				bundles = append(bundles, b)
+			}
+			return bundles, it.Error()
+		})
+		var bundles []*api.Bundle
+		complete, err := s.merge(results, func(r result) {
+			for _, b := range r.value.([]*api.Bundle) {
+				if owners[b.GetPackageName()] == r.upstream {
+					bundles = append(bundles, b)
+				}
+			}
+		})
+		return bundles, complete, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]*api.Bundle), nil
+}
+
+func (s *Server) GetChannelEntriesThatReplace(req *api.GetAllReplacementsRequest, stream api.Registry_GetChannelEntriesThatReplaceServer) error {
+	return s.sendChannelEntries(stream.Context(), cacheKey("GetChannelEntriesThatReplace", req), stream.Send, func(ctx context.Context, c client.Interface) (*client.ChannelEntryIterator, error) {
+		return c.GetChannelEntriesThatReplace(ctx, req.GetCsvName())
+	})
+}
+
+func (s *Server) GetChannelEntriesThatProvide(req *api.GetAllProvidersRequest, stream api.Registry_GetChannelEntriesThatProvideServer) error {
+	return s.sendChannelEntries(stream.Context(), cacheKey("GetChannelEntriesThatProvide", req), stream.Send, func(ctx context.Context, c client.Interface) (*client.ChannelEntryIterator, error) {
+		return c.GetChannelEntriesThatProvide(ctx, req.GetGroup(), req.GetVersion(), req.GetKind())
+	})
+}
+
+func (s *Server) GetLatestChannelEntriesThatProvide(req *api.GetLatestProvidersRequest, stream api.Registry_GetLatestChannelEntriesThatProvideServer) error {
+	return s.sendChannelEntries(stream.Context(), cacheKey("GetLatestChannelEntriesThatProvide", req), stream.Send, func(ctx context.Context, c client.Interface) (*client.ChannelEntryIterator, error) {
+		return c.GetLatestChannelEntriesThatProvide(ctx, req.GetGroup(), req.GetVersion(), req.GetKind())
+	})
+}
+
+// sendChannelEntries sends the channel entries returned by every upstream for the packages
+// it serves. Upstreams report that there are no entries with an error, so the first error
+// is returned if there are no entries at all.
+func (s *Server) sendChannelEntries(ctx context.Context, key string, send func(*api.ChannelEntry) error, call func(context.Context, client.Interface) (*client.ChannelEntryIterator, error)) error {
+	owners, err := s.owners(ctx)
+	if err != nil {
+		return err
+	}
+	value, err := s.cache.load(key, func() (interface{}, bool, error) {
+		results := s.fanOut(ctx, func(ctx context.Context, c client.Interface) (interface{}, error) {
+			it, err := call(ctx, c)
+			if err != nil {
+				return nil, err
+			}
+			var entries []*api.ChannelEntry
+			for e := it.Next(); e != nil; e = it.Next() {
+				entries = append(entries, e)
+			}
+			return entries, it.Error()
+		})
+		var (
+			entries  []*api.ChannelEntry
+			firstErr error
+		)
+		for _, r := range results {
+			if r.err != nil {
+				if firstErr == nil {
+					firstErr = r.err
+				}
+				continue
+			}
+			for _, e := range r.value.([]*api.ChannelEntry) {
+				if owners[e.GetPackageName()] == r.upstream {
+					entries = append(entries, e)
+				}
+			}
+		}
+		if len(entries) == 0 && firstErr != nil {
+			return nil, false, firstErr
+		}
+		return entries, firstErr == nil, nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, e := range value.([]*api.ChannelEntry) {
+		if err := send(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) GetDefaultBundleThatProvides(ctx context.Context, req *api.GetDefaultProviderRequest) (*api.Bundle, error) {
+	owners, err := s.owners(ctx)
+	if err != nil {
+		return nil, err
+	}
+	value, err := s.cache.load(cacheKey("GetDefaultBundleThatProvides", req), func() (interface{}, bool, error) {
+		results := s.fanOut(ctx, func(ctx context.Context, c client.Interface) (interface{}, error) {
+			return c.GetBundleThatProvides(ctx, req.GetGroup(), req.GetVersion(), req.GetKind())
+		})
+		// The highest priority upstream's provider wins, as long as it serves its package.
+		var firstErr error
+		for _, r := range results {
+			if r.err != nil {
+				if firstErr == nil {
+					firstErr = r.err
+				}
+				continue
+			}
+			if b := r.value.(*api.Bundle); owners[b.GetPackageName()] == r.upstream {
+				return b, true, nil
+			}
+		}
+		if firstErr == nil {
+			firstErr = status.Errorf(codes.NotFound, "no default provider of %s/%s/%s found", req.GetGroup(), req.GetVersion(), req.GetKind())
+		}
+		return nil, false, firstErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*api.Bundle), nil
+}
+
+// GetCatalogInfo describes the merged catalog. Its digest changes whenever the digest of
+// an upstream does, and its load time is that of the most recently loaded upstream.
+func (s *Server) GetCatalogInfo(ctx context.Context, req *api.GetCatalogInfoRequest) (*api.CatalogInfo, error) {
+	value, err := s.cache.load(cacheKey("GetCatalogInfo", req), func() (interface{}, bool, error) {
+		results := s.fanOut(ctx, func(ctx context.Context, c client.Interface) (interface{}, error) {
+			return c.GetCatalogInfo(ctx)
+		})
+		info := &api.CatalogInfo{SourceType: server.SourceTypeProxy}
+		h := sha256.New()
+		var latest time.Time
+		complete, err := s.merge(results, func(r result) {
+			upstreamInfo := r.value.(*api.CatalogInfo)
+			fmt.Fprintf(h, "%s\x00%s\x00", r.upstream.Name, upstreamInfo.GetDigest())
+			if loadTime, err := time.Parse(time.RFC3339, upstreamInfo.GetLoadTime()); err == nil && loadTime.After(latest) {
+				latest = loadTime
+				info.LoadTime = upstreamInfo.GetLoadTime()
+			}
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		info.Digest = fmt.Sprintf("sha256:%x", h.Sum(nil))
+
+		bundles, err := s.listBundles(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		packages, channels, csvs := map[string]struct{}{}, map[[2]string]struct{}{}, map[[2]string]struct{}{}
+		for _, b := range bundles {
+			packages[b.GetPackageName()] = struct{}{}
+			channels[[2]string{b.GetPackageName(), b.GetChannelName()}] = struct{}{}
+			csvs[[2]string{b.GetPackageName(), b.GetCsvName()}] = struct{}{}
+		}
+		info.PackageCount, info.ChannelCount, info.BundleCount = int32(len(packages)), int32(len(channels)), int32(len(csvs))
+		return info, complete, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*api.CatalogInfo), nil
+}
+
+// cacheKey identifies the response to req by the method it was sent to.
+func cacheKey(method string, req proto.Message) string {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		// Requests are always serializable, but don't share responses if one isn't.
+		return ""
+	}
+	return method + "\x00" + string(data)
+}
@@ -0,0 +1,304 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"net"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	health "github.com/operator-framework/operator-registry/pkg/api/grpc_health_v1"
+	"github.com/operator-framework/operator-registry/pkg/client"
+	"github.com/operator-framework/operator-registry/pkg/client/clientfakes"
+	"github.com/operator-framework/operator-registry/pkg/client/local"
+	"github.com/operator-framework/operator-registry/pkg/server"
+)
+
+// countingClient counts the calls made to some of the methods of a client.
+type countingClient struct {
+	client.Interface
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (c *countingClient) count(method string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls[method]++
+}
+
+func (c *countingClient) getCalls(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[method]
+}
+
+func (c *countingClient) ListPackages(ctx context.Context) (*client.PackageNameIterator, error) {
+	c.count("ListPackages")
+	return c.Interface.ListPackages(ctx)
+}
+
+func (c *countingClient) GetPackage(ctx context.Context, name string) (*api.Package, error) {
+	c.count("GetPackage")
+	return c.Interface.GetPackage(ctx, name)
+}
+
+func localUpstream(t *testing.T, name string, priority int) (Upstream, *countingClient) {
+	c, err := local.NewClientFromDeclcfg("testdata/" + name)
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+	counting := &countingClient{Interface: c, calls: map[string]int{}}
+	return Upstream{Name: name, Priority: priority, Client: counting}, counting
+}
+
+// unavailableUpstream fails every call as if it couldn't be reached.
+func unavailableUpstream(name string) Upstream {
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+	fake := &clientfakes.FakeInterface{}
+	fake.ListPackagesReturns(nil, unavailable)
+	fake.ListBundlesReturns(nil, unavailable)
+	fake.GetPackageReturns(nil, unavailable)
+	fake.GetChannelEntriesThatProvideReturns(nil, unavailable)
+	fake.GetCatalogInfoReturns(nil, unavailable)
+	fake.HealthCheckReturns(false, unavailable)
+	return Upstream{Name: name, Priority: 100, Client: fake}
+}
+
+func serve(t *testing.T, p *Server) api.RegistryClient {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := grpc.NewServer()
+	api.RegisterRegistryServer(s, p)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return api.NewRegistryClient(conn)
+}
+
+func listPackages(t *testing.T, c api.RegistryClient) []string {
+	stream, err := c.ListPackages(context.Background(), &api.ListPackageRequest{})
+	require.NoError(t, err)
+	var names []string
+	for {
+		name, err := stream.Recv()
+		if err == io.EOF {
+			return names
+		}
+		require.NoError(t, err)
+		names = append(names, name.GetName())
+	}
+}
+
+func channelEntries(t *testing.T, stream interface {
+	Recv() (*api.ChannelEntry, error)
+}) []string {
+	var entries []string
+	for {
+		e, err := stream.Recv()
+		if err == io.EOF {
+			sort.Strings(entries)
+			return entries
+		}
+		require.NoError(t, err)
+		entries = append(entries, e.GetPackageName()+"/"+e.GetChannelName()+"/"+e.GetBundleName()+"/"+e.GetReplaces())
+	}
+}
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	// Both upstreams contain the foo package, which is served from internal because of its
+	// higher priority.
+	community, _ := localUpstream(t, "community", 0)
+	internal, _ := localUpstream(t, "internal", 10)
+	p, err := NewServer([]Upstream{community, internal})
+	require.NoError(t, err)
+	c := serve(t, p)
+
+	require.Equal(t, []string{"bar", "baz", "foo"}, listPackages(t, c))
+
+	pkg, err := c.GetPackage(ctx, &api.GetPackageRequest{Name: "foo"})
+	require.NoError(t, err)
+	require.Equal(t, "foo.v0.2.0", pkg.GetChannels()[0].GetCsvName())
+	_, err = c.GetPackage(ctx, &api.GetPackageRequest{Name: "missing"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	b, err := c.GetBundle(ctx, &api.GetBundleRequest{PkgName: "bar", ChannelName: "stable", CsvName: "bar.v0.1.0"})
+	require.NoError(t, err)
+	require.Equal(t, "test.registry/bar-bundle:v0.1.0", b.GetBundlePath())
+	b, err = c.GetBundleForChannel(ctx, &api.GetBundleInChannelRequest{PkgName: "foo", ChannelName: "stable"})
+	require.NoError(t, err)
+	require.Equal(t, "foo.v0.2.0", b.GetCsvName())
+	b, err = c.GetBundleThatReplaces(ctx, &api.GetReplacementRequest{CsvName: "foo.v0.1.0", PkgName: "foo", ChannelName: "stable"})
+	require.NoError(t, err)
+	require.Equal(t, "foo.v0.2.0", b.GetCsvName())
+	b, err = c.GetDefaultBundleThatProvides(ctx, &api.GetDefaultProviderRequest{Group: "test.bar", Version: "v1", Kind: "Bar"})
+	require.NoError(t, err)
+	require.Equal(t, "bar.v0.1.0", b.GetCsvName())
+
+	stream, err := c.ListBundles(ctx, &api.ListBundlesRequest{})
+	require.NoError(t, err)
+	var bundles []string
+	for {
+		b, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		bundles = append(bundles, b.GetCsvName())
+	}
+	sort.Strings(bundles)
+	require.Equal(t, []string{"bar.v0.1.0", "baz.v1.0.0", "foo.v0.1.0", "foo.v0.2.0"}, bundles)
+
+	// Entries for foo only come from internal, so its first bundle isn't repeated.
+	provides, err := c.GetChannelEntriesThatProvide(ctx, &api.GetAllProvidersRequest{Group: "test.foo", Version: "v1", Kind: "Foo"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo/stable/foo.v0.1.0/", "foo/stable/foo.v0.2.0/foo.v0.1.0"}, channelEntries(t, provides))
+	latest, err := c.GetLatestChannelEntriesThatProvide(ctx, &api.GetLatestProvidersRequest{Group: "test.foo", Version: "v1", Kind: "Foo"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo/stable/foo.v0.2.0/foo.v0.1.0"}, channelEntries(t, latest))
+	replace, err := c.GetChannelEntriesThatReplace(ctx, &api.GetAllReplacementsRequest{CsvName: "foo.v0.1.0"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo/stable/foo.v0.2.0/foo.v0.1.0"}, channelEntries(t, replace))
+	replace, err = c.GetChannelEntriesThatReplace(ctx, &api.GetAllReplacementsRequest{CsvName: "foo.v0.2.0"})
+	require.NoError(t, err)
+	_, err = replace.Recv()
+	require.Error(t, err)
+	require.NotEqual(t, io.EOF, err)
+
+	info, err := c.GetCatalogInfo(ctx, &api.GetCatalogInfoRequest{})
+	require.NoError(t, err)
+	require.Equal(t, server.SourceTypeProxy, info.GetSourceType())
+	require.NotEmpty(t, info.GetDigest())
+	require.EqualValues(t, 3, info.GetPackageCount())
+	require.EqualValues(t, 3, info.GetChannelCount())
+	require.EqualValues(t, 4, info.GetBundleCount())
+}
+
+func TestServerUnavailableUpstream(t *testing.T) {
+	ctx := context.Background()
+	internal, counting := localUpstream(t, "internal", 10)
+	p, err := NewServer([]Upstream{unavailableUpstream("down"), internal})
+	require.NoError(t, err)
+	c := serve(t, p)
+
+	// Upstreams that fail are left out, and incomplete responses aren't cached.
+	require.Equal(t, []string{"baz", "foo"}, listPackages(t, c))
+	require.Equal(t, []string{"baz", "foo"}, listPackages(t, c))
+	require.Equal(t, 2, counting.getCalls("ListPackages"))
+	provides, err := c.GetChannelEntriesThatProvide(ctx, &api.GetAllProvidersRequest{Group: "test.foo", Version: "v1", Kind: "Foo"})
+	require.NoError(t, err)
+	require.Len(t, channelEntries(t, provides), 2)
+
+	checker := server.NewHealthServer(server.WithHealthChecker(p.HealthStatus))
+	check := func(service string) (health.HealthCheckResponse_ServingStatus, error) {
+		res, err := checker.Check(ctx, &health.HealthCheckRequest{Service: service})
+		return res.GetStatus(), err
+	}
+
+	// Calls that find an upstream unavailable mark it as not serving.
+	st, err := check("down")
+	require.NoError(t, err)
+	require.Equal(t, health.HealthCheckResponse_NOT_SERVING, st)
+	st, err = check("internal")
+	require.NoError(t, err)
+	require.Equal(t, health.HealthCheckResponse_UNKNOWN, st)
+
+	p.CheckHealth(ctx, 0)
+	st, err = check("internal")
+	require.NoError(t, err)
+	require.Equal(t, health.HealthCheckResponse_SERVING, st)
+	for _, service := range []string{"", "Registry"} {
+		st, err = check(service)
+		require.NoError(t, err)
+		require.Equal(t, health.HealthCheckResponse_SERVING, st)
+	}
+	_, err = check("missing")
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// The proxy stops serving once every upstream is down.
+	p, err = NewServer([]Upstream{unavailableUpstream("down")})
+	require.NoError(t, err)
+	p.CheckHealth(ctx, 0)
+	st, err = p.HealthStatus(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, health.HealthCheckResponse_NOT_SERVING, st)
+	_, err = serve(t, p).GetPackage(ctx, &api.GetPackageRequest{Name: "foo"})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestServerCache(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name          string
+		opts          []Option
+		expectedCalls int
+	}{
+		{name: "Cached", expectedCalls: 1},
+		{name: "Disabled", opts: []Option{WithCacheTTL(0)}, expectedCalls: 3},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			internal, counting := localUpstream(t, "internal", 0)
+			p, err := NewServer([]Upstream{internal}, tt.opts...)
+			require.NoError(t, err)
+			c := serve(t, p)
+			for i := 0; i < 3; i++ {
+				_, err := c.GetPackage(ctx, &api.GetPackageRequest{Name: "foo"})
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.expectedCalls, counting.getCalls("GetPackage"))
+			require.Equal(t, tt.expectedCalls, counting.getCalls("ListPackages"))
+		})
+	}
+
+	cache := newResponseCache(DefaultCacheTTL)
+	now := cache.now()
+	cache.now = func() time.Time { return now }
+	fetches := 0
+	fetch := func() (interface{}, bool, error) {
+		fetches++
+		return fetches, true, nil
+	}
+	value, err := cache.load("key", fetch)
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+	value, err = cache.load("key", fetch)
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+	now = now.Add(DefaultCacheTTL)
+	value, err = cache.load("key", fetch)
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+}
+
+func TestNewServer(t *testing.T) {
+	fake := &clientfakes.FakeInterface{}
+	for _, upstreams := range [][]Upstream{
+		nil,
+		{{Name: "", Client: fake}},
+		{{Name: "Registry", Client: fake}},
+		{{Name: "a", Client: fake}, {Name: "a", Client: fake}},
+	} {
+		_, err := NewServer(upstreams)
+		require.Error(t, err)
+	}
+
+	p, err := NewServer([]Upstream{{Name: "low", Client: fake}, {Name: "high", Priority: 1, Client: fake}, {Name: "low2", Client: fake}})
+	require.NoError(t, err)
+	var names []string
+	for _, u := range p.upstreams {
+		names = append(names, u.Name)
+	}
+	require.Equal(t, []string{"high", "low", "low2"}, names)
+}
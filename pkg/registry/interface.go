@@ -3,6 +3,7 @@ package registry
 import (
 	"context"
 
+	"github.com/operator-framework/operator-registry/internal/model"
 	"github.com/operator-framework/operator-registry/pkg/api"
 )
 
@@ -49,6 +50,11 @@ type GRPCQuery interface {
 	GetBundleThatProvides(ctx context.Context, group, version, kind string) (*api.Bundle, error)
 }
 
+// ModelQuery is implemented by stores that can return their contents as an index model.
+type ModelQuery interface {
+	Model(ctx context.Context) (model.Model, error)
+}
+
 type Query interface {
 	GRPCQuery
 
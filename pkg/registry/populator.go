@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/blang/semver"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -39,11 +40,13 @@ func NewDirectoryPopulator(loader Load, graphLoader GraphLoader, querier Query,
 	}
 }
 
+// Populate loads the bundles of the populator's images into the database.
+// Images are loaded in the order of their references, so populating is deterministic.
 func (i *DirectoryPopulator) Populate(mode Mode) error {
 	var errs []error
 	imagesToAdd := make([]*ImageInput, 0)
-	for to, from := range i.imageDirMap {
-		imageInput, err := NewImageInput(to, from)
+	for _, to := range sortedReferences(i.imageDirMap) {
+		imageInput, err := NewImageInput(to, i.imageDirMap[to])
 		if err != nil {
 			errs = append(errs, err)
 			continue
@@ -53,9 +56,14 @@ func (i *DirectoryPopulator) Populate(mode Mode) error {
 	}
 
 	imagesToReAdd := make([]*ImageInput, 0)
+	pkgs := make([]string, 0, len(i.overwriteDirMap))
 	for pkg := range i.overwriteDirMap {
-		for to, from := range i.overwriteDirMap[pkg] {
-			imageInput, err := NewImageInput(to, from)
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+	for _, pkg := range pkgs {
+		for _, to := range sortedReferences(i.overwriteDirMap[pkg]) {
+			imageInput, err := NewImageInput(to, i.overwriteDirMap[pkg][to])
 			if err != nil {
 				errs = append(errs, err)
 				continue
@@ -77,6 +85,18 @@ func (i *DirectoryPopulator) Populate(mode Mode) error {
 	return nil
 }
 
+// sortedReferences returns the image references of a map of references to directories, sorted.
+func sortedReferences(dirs map[image.Reference]string) []image.Reference {
+	refs := make([]image.Reference, 0, len(dirs))
+	for ref := range dirs {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].String() < refs[j].String()
+	})
+	return refs
+}
+
 func (i *DirectoryPopulator) globalSanityCheck(imagesToAdd []*ImageInput) error {
 	var errs []error
 	images := make(map[string]struct{})
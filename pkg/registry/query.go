@@ -14,6 +14,7 @@ type Querier struct {
 }
 
 var _ GRPCQuery = &Querier{}
+var _ ModelQuery = &Querier{}
 
 func NewQuerier(packages model.Model) *Querier {
 	return &Querier{
@@ -21,6 +22,11 @@ func NewQuerier(packages model.Model) *Querier {
 	}
 }
 
+// Model returns the index model queried.
+func (q Querier) Model(_ context.Context) (model.Model, error) {
+	return q.pkgs, nil
+}
+
 func (q Querier) ListPackages(_ context.Context) ([]string, error) {
 	var packages []string
 	for pkgName := range q.pkgs {
@@ -292,7 +298,7 @@ func channelEntriesThatReplace(b model.Bundle, name string) []*ChannelEntry {
 				PackageName: b.Package.Name,
 				ChannelName: b.Channel.Name,
 				BundleName:  b.Name,
-				Replaces:    b.Replaces,
+				Replaces:    s,
 			})
 		}
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/operator-framework/operator-registry/internal/declcfg"
+	"github.com/operator-framework/operator-registry/internal/model"
 )
 
 var testModelQuerier = genTestModelQuerier()
@@ -111,6 +112,35 @@ func TestQuerier_GetChannelEntriesThatReplace(t *testing.T) {
 	}, entries)
 }
 
+func TestQuerier_GetChannelEntriesThatReplaceSkipped(t *testing.T) {
+	// Entries for skipped bundles report the skipped bundle as replaced, like the sqlite querier.
+	entries, err := testModelQuerier.GetChannelEntriesThatReplace(context.TODO(), "etcdoperator.v0.6.1")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*ChannelEntry{
+		{
+			PackageName: "etcd",
+			ChannelName: "clusterwide-alpha",
+			BundleName:  "etcdoperator.v0.9.2-clusterwide",
+			Replaces:    "etcdoperator.v0.6.1",
+		},
+	}, entries)
+}
+
+func TestChannelEntriesThatReplace(t *testing.T) {
+	pkg := &model.Package{Name: "etcd"}
+	ch := &model.Channel{Package: pkg, Name: "alpha"}
+	b := model.Bundle{Package: pkg, Channel: ch, Name: "etcd.v3", Replaces: "etcd.v2", Skips: []string{"etcd.v1", "etcd.v2"}}
+	entry := func(replaces string) *ChannelEntry {
+		return &ChannelEntry{PackageName: "etcd", ChannelName: "alpha", BundleName: "etcd.v3", Replaces: replaces}
+	}
+
+	// A bundle that is both replaced and skipped has a single entry
+	require.Equal(t, []*ChannelEntry{entry("etcd.v2")}, channelEntriesThatReplace(b, "etcd.v2"))
+	// Entries for skipped bundles report the skipped bundle as replaced, not the bundle's replaces
+	require.Equal(t, []*ChannelEntry{entry("etcd.v1")}, channelEntriesThatReplace(b, "etcd.v1"))
+	require.Empty(t, channelEntriesThatReplace(b, "etcd.v0"))
+}
+
 func TestQuerier_GetLatestChannelEntriesThatProvide(t *testing.T) {
 	entries, err := testModelQuerier.GetLatestChannelEntriesThatProvide(context.TODO(), "etcd.database.coreos.com", "v1beta2", "EtcdBackup")
 	require.NoError(t, err)
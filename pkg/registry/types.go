@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/blang/semver"
+
+	"github.com/operator-framework/operator-registry/internal/property"
 )
 
 var (
@@ -50,11 +52,12 @@ func (e OverwriteErr) Error() string {
 }
 
 const (
-	GVKType        = "olm.gvk"
-	PackageType    = "olm.package"
-	DeprecatedType = "olm.deprecated"
-	LabelType      = "olm.label"
-	PropertyKey    = "olm.properties"
+	GVKType         = "olm.gvk"
+	PackageType     = "olm.package"
+	DeprecatedType  = property.TypeDeprecated
+	LabelType       = "olm.label"
+	PinnedImageType = "olm.pinnedImage"
+	PropertyKey     = "olm.properties"
 )
 
 // APIKey stores GroupVersionKind for use as map keys
@@ -248,6 +251,14 @@ type LabelProperty struct {
 	Label string `json:"label" yaml:"label"`
 }
 
+type PinnedImageProperty struct {
+	// The reference by digest the image was pinned to
+	Image string `json:"image" yaml:"image"`
+
+	// The reference by tag the image was pinned from
+	Tag string `json:"tag" yaml:"tag"`
+}
+
 // Validate will validate GVK dependency type and return error(s)
 func (gd *GVKDependency) Validate() []error {
 	errs := []error{}
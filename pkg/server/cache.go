@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+// cachedResponses are the responses to the RPCs clients use to sync whole catalogs,
+// computed once when a store is loaded. Stores are never modified while they are served,
+// so the responses stay valid until the store is replaced.
+type cachedResponses struct {
+	packageNames []*encodedMessage
+	bundles      []*encodedMessage
+	packages     map[string]*api.Package
+}
+
+// encodedMessage is a message along with its wire encoding. The default gRPC codec sends
+// messages implementing Marshal as they encode themselves, so streaming one skips encoding
+// the message again. Everything else sees the message itself.
+type encodedMessage struct {
+	proto.Message
+	data []byte
+}
+
+func newEncodedMessage(m proto.Message) (*encodedMessage, error) {
+	data, err := proto.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return &encodedMessage{Message: m, data: data}, nil
+}
+
+func (m *encodedMessage) Marshal() ([]byte, error) {
+	return m.data, nil
+}
+
+// unwrapMessage returns the message sent on a stream, without any encoding cached with it.
+func unwrapMessage(m interface{}) interface{} {
+	if e, ok := m.(*encodedMessage); ok {
+		return e.Message
+	}
+	return m
+}
+
+func precomputeResponses(ctx context.Context, store registry.GRPCQuery) (*cachedResponses, error) {
+	r := &cachedResponses{packages: map[string]*api.Package{}}
+
+	names, err := store.ListPackages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list packages: %v", err)
+	}
+	for _, name := range names {
+		m, err := newEncodedMessage(&api.PackageName{Name: name})
+		if err != nil {
+			return nil, fmt.Errorf("encode package name %q: %v", name, err)
+		}
+		r.packageNames = append(r.packageNames, m)
+		manifest, err := store.GetPackage(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("get package %q: %v", name, err)
+		}
+		r.packages[name] = registry.PackageManifestToAPIPackage(manifest)
+	}
+	bundles, err := store.ListBundles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list bundles: %v", err)
+	}
+	for _, b := range bundles {
+		m, err := newEncodedMessage(b)
+		if err != nil {
+			return nil, fmt.Errorf("encode bundle %q: %v", b.GetCsvName(), err)
+		}
+		r.bundles = append(r.bundles, m)
+	}
+	return r, nil
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	encodingproto "google.golang.org/grpc/encoding/proto"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+// countingStore counts the queries that the response cache answers.
+type countingStore struct {
+	registry.GRPCQuery
+	queries int64
+}
+
+func (s *countingStore) ListPackages(ctx context.Context) ([]string, error) {
+	atomic.AddInt64(&s.queries, 1)
+	return s.GRPCQuery.ListPackages(ctx)
+}
+
+func (s *countingStore) GetPackage(ctx context.Context, name string) (*registry.PackageManifest, error) {
+	atomic.AddInt64(&s.queries, 1)
+	return s.GRPCQuery.GetPackage(ctx, name)
+}
+
+func (s *countingStore) ListBundles(ctx context.Context) ([]*api.Bundle, error) {
+	atomic.AddInt64(&s.queries, 1)
+	return s.GRPCQuery.ListBundles(ctx)
+}
+
+func serveCached(t *testing.T, s *RegistryServer) api.RegistryClient {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	g := grpc.NewServer()
+	api.RegisterRegistryServer(g, s)
+	go g.Serve(lis)
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return api.NewRegistryClient(conn)
+}
+
+// cachedRPCs returns the results of the RPCs answered from the response cache.
+func cachedRPCs(t *testing.T, c api.RegistryClient) map[string]rpcResult {
+	ctx := context.Background()
+	results := map[string]rpcResult{}
+
+	packages, err := c.ListPackages(ctx, &api.ListPackageRequest{})
+	results["ListPackages"] = streamResult(func() (proto.Message, error) { return packages.Recv() }, err)
+	for _, m := range results["ListPackages"].msgs {
+		name := m.(*api.PackageName).GetName()
+		results["GetPackage/"+name] = unaryResult(c.GetPackage(ctx, &api.GetPackageRequest{Name: name}))
+	}
+	results["GetPackage/missing"] = unaryResult(c.GetPackage(ctx, &api.GetPackageRequest{Name: "missing"}))
+	bundles, err := c.ListBundles(ctx, &api.ListBundlesRequest{})
+	results["ListBundles"] = streamResult(func() (proto.Message, error) { return bundles.Recv() }, err)
+	return results
+}
+
+func requireSameResults(t *testing.T, expected, actual map[string]rpcResult) {
+	t.Helper()
+	require.Equal(t, len(expected), len(actual))
+	for name, e := range expected {
+		a, ok := actual[name]
+		require.True(t, ok, name)
+		require.Equal(t, e.code, a.code, name)
+		require.Equal(t, e.normalize(t), a.normalize(t), name)
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		store func(t *testing.T) registry.GRPCQuery
+	}{
+		{
+			name: "SQL",
+			store: func(t *testing.T) registry.GRPCQuery {
+				return dbStore(filepath.Join(t.TempDir(), "test.db"))
+			},
+		},
+		{
+			name: "Memory",
+			store: func(t *testing.T) registry.GRPCQuery {
+				return registry.NewQuerier(cfgModel())
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &countingStore{GRPCQuery: tt.store(t)}
+			expected := cachedRPCs(t, serveCached(t, NewRegistryServer(store)))
+			require.NotEmpty(t, expected["ListBundles"].msgs)
+
+			// Cached responses are the same as the store's, without querying it.
+			s := NewRegistryServer(store, WithResponseCache())
+			atomic.StoreInt64(&store.queries, 0)
+			requireSameResults(t, expected, cachedRPCs(t, serveCached(t, s)))
+			// Only the missing package is looked up.
+			require.Equal(t, int64(1), atomic.LoadInt64(&store.queries))
+		})
+	}
+}
+
+func TestResponseCacheReload(t *testing.T) {
+	s := NewRegistryServer(registry.NewQuerier(cfgModel()), WithResponseCache())
+	c := serveCached(t, s)
+	before := cachedRPCs(t, c)
+	require.NotEmpty(t, before["GetPackage/etcd"].msgs)
+
+	// Reloading replaces the responses and their encodings.
+	reloaded := watchTestModel(map[string][]string{"stable": {"0.1.0"}})
+	s.Reload(registry.NewQuerier(reloaded), nil)
+	after := cachedRPCs(t, c)
+	requireSameResults(t, cachedRPCs(t, serveCached(t, NewRegistryServer(registry.NewQuerier(reloaded)))), after)
+	require.NotEmpty(t, after["GetPackage/foo"].msgs)
+	require.Empty(t, after["GetPackage/etcd"].msgs)
+}
+
+func TestEncodedResponses(t *testing.T) {
+	s := NewRegistryServer(registry.NewQuerier(cfgModel()), WithResponseCache())
+	codec := encoding.GetCodec(encodingproto.Name)
+
+	// Cached messages are sent by the default codec as they were encoded when the store was loaded.
+	r := s.getResponses()
+	require.NotNil(t, r)
+	b := r.bundles[0]
+	data, err := codec.Marshal(b)
+	require.NoError(t, err)
+	require.Equal(t, b.data, data)
+
+	var decoded api.Bundle
+	require.NoError(t, codec.Unmarshal(data, &decoded))
+	require.True(t, proto.Equal(b.Message, &decoded))
+
+	// Reloading replaces the encoded messages.
+	s.Reload(registry.NewQuerier(cfgModel()), nil)
+	require.NotSame(t, b, s.getResponses().bundles[0])
+}
+
+func TestResponseCacheHTTP(t *testing.T) {
+	// The REST/JSON API filters the cached responses like any others.
+	m := cfgModel()
+	srv := httptest.NewServer(NewHTTPHandler(NewRegistryServer(registry.NewQuerier(m), WithResponseCache())))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/v1/bundles?package=etcd")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var names []string
+	dec := json.NewDecoder(resp.Body)
+	for dec.More() {
+		var b struct {
+			PackageName string `json:"packageName"`
+			CsvName     string `json:"csvName"`
+		}
+		require.NoError(t, dec.Decode(&b))
+		require.Equal(t, "etcd", b.PackageName)
+		names = append(names, b.CsvName)
+	}
+	require.NotEmpty(t, names)
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/operator-framework/operator-registry/internal/declcfg"
+	"github.com/operator-framework/operator-registry/internal/model"
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/sqlite"
+)
+
+const (
+	SourceTypeSqlite    = "sqlite"
+	SourceTypeDeclcfg   = "declcfg"
+	SourceTypeConfigMap = "configmap"
+	SourceTypeProxy     = "proxy"
+)
+
+// NewModelCatalogInfo returns the catalog info for an index model loaded at loadTime from
+// declarative configs.
+func NewModelCatalogInfo(sourceType, digest string, loadTime time.Time, m model.Model) *api.CatalogInfo {
+	info := &api.CatalogInfo{
+		SourceType:    sourceType,
+		SchemaVersion: declcfg.SchemaVersion,
+		Digest:        digest,
+		LoadTime:      loadTime.UTC().Format(time.RFC3339),
+		PackageCount:  int32(len(m)),
+	}
+	for _, pkg := range m {
+		info.ChannelCount += int32(len(pkg.Channels))
+		bundles := map[string]struct{}{}
+		for _, ch := range pkg.Channels {
+			for name := range ch.Bundles {
+				bundles[name] = struct{}{}
+			}
+		}
+		info.BundleCount += int32(len(bundles))
+	}
+	return info
+}
+
+// NewSQLCatalogInfo returns the catalog info for a sqlite database loaded at loadTime.
+func NewSQLCatalogInfo(ctx context.Context, sourceType, digest string, loadTime time.Time, q *sqlite.SQLQuerier) (*api.CatalogInfo, error) {
+	version, err := q.GetMigrationVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get migration version: %v", err)
+	}
+	packages, channels, bundles, err := q.CountContents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count database contents: %v", err)
+	}
+	return &api.CatalogInfo{
+		SourceType:    sourceType,
+		SchemaVersion: strconv.Itoa(version),
+		Digest:        digest,
+		LoadTime:      loadTime.UTC().Format(time.RFC3339),
+		PackageCount:  int32(packages),
+		ChannelCount:  int32(channels),
+		BundleCount:   int32(bundles),
+	}, nil
+}
+
+// DigestFile returns the sha256 digest of the file at path.
+func DigestFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
+
+// DigestFS returns the sha256 digest of the paths and contents of all regular files in fsys.
+func DigestFS(fsys fs.FS) (string, error) {
+	h := sha256.New()
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		f, err := fsys.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		fmt.Fprintf(h, "%s\x00", path)
+		_, err = io.Copy(h, f)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
+
+// DigestData returns the sha256 digest of the keys and values of data.
+func DigestData(data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s\x00%s\x00", k, data[k])
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil))
+}
@@ -8,14 +8,39 @@ import (
 
 type HealthServer struct {
 	health.UnimplementedHealthServer
+	checker HealthChecker
 }
 
 var _ health.HealthServer = &HealthServer{}
 
-func NewHealthServer() *HealthServer {
-	return &HealthServer{health.UnimplementedHealthServer{}}
+// HealthChecker returns the serving status of the named service. Errors are returned to
+// the caller as they are, so unknown services should be reported with codes.NotFound.
+type HealthChecker func(ctx context.Context, service string) (health.HealthCheckResponse_ServingStatus, error)
+
+type HealthServerOption func(*HealthServer)
+
+// WithHealthChecker reports the status returned by checker, instead of always serving.
+func WithHealthChecker(checker HealthChecker) HealthServerOption {
+	return func(s *HealthServer) {
+		s.checker = checker
+	}
+}
+
+func NewHealthServer(opts ...HealthServerOption) *HealthServer {
+	s := &HealthServer{UnimplementedHealthServer: health.UnimplementedHealthServer{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *HealthServer) Check(ctx context.Context, req *health.HealthCheckRequest) (*health.HealthCheckResponse, error) {
-	return &health.HealthCheckResponse{Status: health.HealthCheckResponse_SERVING}, nil
+	if s.checker == nil {
+		return &health.HealthCheckResponse{Status: health.HealthCheckResponse_SERVING}, nil
+	}
+	status, err := s.checker(ctx, req.GetService())
+	if err != nil {
+		return nil, err
+	}
+	return &health.HealthCheckResponse{Status: status}, nil
 }
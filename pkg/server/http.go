@@ -0,0 +1,367 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+)
+
+const (
+	contentTypeJSON   = "application/json"
+	contentTypeNDJSON = "application/x-ndjson"
+)
+
+// HTTPHandler exposes a RegistryServer as REST/JSON endpoints. Unary methods respond
+// with a single JSON object, and streaming methods respond with newline-delimited JSON,
+// one object per line.
+//
+//	GET /api/v1/catalog                                         GetCatalogInfo
+//	GET /api/v1/packages                                        ListPackages
+//	GET /api/v1/packages/{package}                              GetPackage
+//	GET /api/v1/packages/{package}/channels/{channel}/head      GetBundleForChannel
+//	GET /api/v1/packages/{package}/channels/{channel}/bundles/{csvName}
+//	                                                            GetBundle
+//	GET /api/v1/packages/{package}/channels/{channel}/bundles/{csvName}/replacement
+//	                                                            GetBundleThatReplaces
+//	GET /api/v1/bundles[?package=]                              ListBundles
+//	GET /api/v1/bundles/{csvName}/replacements                  GetChannelEntriesThatReplace
+//	GET /api/v1/providers?group=&version=&kind=[&latest=true]   GetChannelEntriesThatProvide,
+//	                                                            GetLatestChannelEntriesThatProvide
+//	GET /api/v1/providers/default?group=&version=&kind=         GetDefaultBundleThatProvides
+//	GET /api/v1/watch[?resumeToken=]                            Watch
+//
+// Requests are handled through the interceptors set with WithHTTPInterceptors, as if
+// they were RPCs from the client's address.
+type HTTPHandler struct {
+	server       api.RegistryServer
+	interceptors Interceptors
+}
+
+var _ http.Handler = &HTTPHandler{}
+
+type HTTPHandlerOption func(*HTTPHandler)
+
+// WithHTTPInterceptors handles requests through the same interceptors as the gRPC server.
+func WithHTTPInterceptors(i Interceptors) HTTPHandlerOption {
+	return func(h *HTTPHandler) {
+		h.interceptors = i
+	}
+}
+
+func NewHTTPHandler(server api.RegistryServer, opts ...HTTPHandlerOption) *HTTPHandler {
+	h := &HTTPHandler{server: server}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(httpError{Code: codes.Unimplemented.String(), Message: fmt.Sprintf("method %s not allowed", r.Method)})
+		return
+	}
+
+	path := strings.Trim(r.URL.Path, "/")
+	if !strings.HasPrefix(path, "api/v1/") {
+		writeError(w, status.Errorf(codes.NotFound, "path %q not found", r.URL.Path))
+		return
+	}
+	segments := strings.Split(strings.TrimPrefix(path, "api/v1/"), "/")
+	query := r.URL.Query()
+	ctx := rpcContext(r)
+
+	switch {
+	case match(segments, "catalog"):
+		h.unary(ctx, w, "GetCatalogInfo", &api.GetCatalogInfoRequest{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return h.server.GetCatalogInfo(ctx, req.(*api.GetCatalogInfoRequest))
+		})
+	case match(segments, "packages"):
+		h.stream(ctx, w, "ListPackages", &api.ListPackageRequest{}, func(req proto.Message, ss grpc.ServerStream) error {
+			return h.server.ListPackages(req.(*api.ListPackageRequest), &packageNameStream{ss})
+		})
+	case match(segments, "packages", "*"):
+		h.unary(ctx, w, "GetPackage", &api.GetPackageRequest{Name: segments[1]}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return h.server.GetPackage(ctx, req.(*api.GetPackageRequest))
+		})
+	case match(segments, "packages", "*", "channels", "*", "head"):
+		req := &api.GetBundleInChannelRequest{PkgName: segments[1], ChannelName: segments[3]}
+		h.unary(ctx, w, "GetBundleForChannel", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return h.server.GetBundleForChannel(ctx, req.(*api.GetBundleInChannelRequest))
+		})
+	case match(segments, "packages", "*", "channels", "*", "bundles", "*"):
+		req := &api.GetBundleRequest{PkgName: segments[1], ChannelName: segments[3], CsvName: segments[5]}
+		h.unary(ctx, w, "GetBundle", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return h.server.GetBundle(ctx, req.(*api.GetBundleRequest))
+		})
+	case match(segments, "packages", "*", "channels", "*", "bundles", "*", "replacement"):
+		req := &api.GetReplacementRequest{PkgName: segments[1], ChannelName: segments[3], CsvName: segments[5]}
+		h.unary(ctx, w, "GetBundleThatReplaces", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return h.server.GetBundleThatReplaces(ctx, req.(*api.GetReplacementRequest))
+		})
+	case match(segments, "bundles"):
+		h.stream(ctx, w, "ListBundles", &api.ListBundlesRequest{}, func(req proto.Message, ss grpc.ServerStream) error {
+			return h.server.ListBundles(req.(*api.ListBundlesRequest), &bundleStream{ss, query.Get("package")})
+		})
+	case match(segments, "bundles", "*", "replacements"):
+		req := &api.GetAllReplacementsRequest{CsvName: segments[1]}
+		h.stream(ctx, w, "GetChannelEntriesThatReplace", req, func(req proto.Message, ss grpc.ServerStream) error {
+			return h.server.GetChannelEntriesThatReplace(req.(*api.GetAllReplacementsRequest), &channelEntryStream{ss})
+		})
+	case match(segments, "providers"):
+		latest, err := parseBool(query.Get("latest"))
+		if err != nil {
+			writeError(w, status.Errorf(codes.InvalidArgument, "invalid value for latest: %v", err))
+			return
+		}
+		if latest {
+			req := &api.GetLatestProvidersRequest{Group: query.Get("group"), Version: query.Get("version"), Kind: query.Get("kind"), Plural: query.Get("plural")}
+			h.stream(ctx, w, "GetLatestChannelEntriesThatProvide", req, func(req proto.Message, ss grpc.ServerStream) error {
+				return h.server.GetLatestChannelEntriesThatProvide(req.(*api.GetLatestProvidersRequest), &channelEntryStream{ss})
+			})
+		} else {
+			req := &api.GetAllProvidersRequest{Group: query.Get("group"), Version: query.Get("version"), Kind: query.Get("kind"), Plural: query.Get("plural")}
+			h.stream(ctx, w, "GetChannelEntriesThatProvide", req, func(req proto.Message, ss grpc.ServerStream) error {
+				return h.server.GetChannelEntriesThatProvide(req.(*api.GetAllProvidersRequest), &channelEntryStream{ss})
+			})
+		}
+	case match(segments, "providers", "default"):
+		req := &api.GetDefaultProviderRequest{Group: query.Get("group"), Version: query.Get("version"), Kind: query.Get("kind"), Plural: query.Get("plural")}
+		h.unary(ctx, w, "GetDefaultBundleThatProvides", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return h.server.GetDefaultBundleThatProvides(ctx, req.(*api.GetDefaultProviderRequest))
+		})
+	case match(segments, "watch"):
+		h.stream(ctx, w, "Watch", &api.WatchRequest{ResumeToken: query.Get("resumeToken")}, func(req proto.Message, ss grpc.ServerStream) error {
+			return h.server.Watch(req.(*api.WatchRequest), &watchEventStream{ss})
+		})
+	default:
+		writeError(w, status.Errorf(codes.NotFound, "path %q not found", r.URL.Path))
+	}
+}
+
+// registryMethod returns the full gRPC name of a method of the registry service.
+func registryMethod(method string) string {
+	return "/api.Registry/" + method
+}
+
+// rpcContext returns the context of a request the way gRPC would present it to
+// interceptors: with the client's address as its peer.
+func rpcContext(r *http.Request) context.Context {
+	return peer.NewContext(r.Context(), &peer.Peer{Addr: httpAddr(r.RemoteAddr)})
+}
+
+// httpAddr is the address of an HTTP client.
+type httpAddr string
+
+func (a httpAddr) Network() string { return "tcp" }
+func (a httpAddr) String() string  { return string(a) }
+
+// match reports whether segments matches pattern, where "*" matches any non-empty segment.
+func match(segments []string, pattern ...string) bool {
+	if len(segments) != len(pattern) {
+		return false
+	}
+	for i, p := range pattern {
+		if segments[i] == "" || (p != "*" && p != segments[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseBool(s string) (bool, error) {
+	if s == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(s)
+}
+
+// unary calls a unary method through the unary interceptors, responding with its result.
+func (h *HTTPHandler) unary(ctx context.Context, w http.ResponseWriter, method string, req proto.Message, call grpc.UnaryHandler) {
+	resp, err := h.interceptors.unaryHandler(registryMethod(method), h.server, call)(ctx, req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	data, err := protojson.Marshal(resp.(proto.Message))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.Write(append(data, '\n'))
+}
+
+// stream calls a server streaming method through the stream interceptors, responding
+// with the messages it sends. The method receives req from the stream, as it would from
+// a gRPC client, so interceptors see it too.
+func (h *HTTPHandler) stream(ctx context.Context, w http.ResponseWriter, method string, req proto.Message, call func(req proto.Message, ss grpc.ServerStream) error) {
+	stream := newNDJSONStream(ctx, w, req)
+	handler := h.interceptors.streamHandler(registryMethod(method), func(_ interface{}, ss grpc.ServerStream) error {
+		in := req.ProtoReflect().New().Interface()
+		if err := ss.RecvMsg(in); err != nil {
+			return err
+		}
+		return call(in, ss)
+	})
+	stream.finish(handler(h.server, stream))
+}
+
+// httpStatus maps gRPC status codes to HTTP status codes.
+var httpStatus = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusPreconditionFailed,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusGone,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+type httpError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// storeNotFound matches the messages of the errors stores return for missing packages,
+// channels, bundles and entries, which aren't status errors.
+var storeNotFound = regexp.MustCompile(`not found|^no (entry|channel entries) found`)
+
+// convertError returns the status of err, treating errors from stores about missing
+// content as NotFound.
+func convertError(err error) *status.Status {
+	s := status.Convert(err)
+	if s.Code() == codes.Unknown && storeNotFound.MatchString(s.Message()) {
+		return status.New(codes.NotFound, s.Message())
+	}
+	return s
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	s := convertError(err)
+	code, ok := httpStatus[s.Code()]
+	if !ok {
+		code = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(httpError{Code: s.Code().String(), Message: s.Message()})
+}
+
+// ndjsonStream adapts an HTTP response to a gRPC server stream, writing each sent
+// message as a line of JSON. Errors returned by the streaming method before anything
+// was sent are written as the response status; later errors can only be reported by
+// terminating the response with an error object.
+type ndjsonStream struct {
+	ctx  context.Context
+	w    http.ResponseWriter
+	req  proto.Message
+	sent bool
+}
+
+var _ grpc.ServerStream = &ndjsonStream{}
+
+// newNDJSONStream returns a stream responding to w, which receives req.
+func newNDJSONStream(ctx context.Context, w http.ResponseWriter, req proto.Message) *ndjsonStream {
+	return &ndjsonStream{ctx: ctx, w: w, req: req}
+}
+
+func (s *ndjsonStream) SendMsg(m interface{}) error {
+	data, err := protojson.Marshal(m.(proto.Message))
+	if err != nil {
+		return err
+	}
+	if !s.sent {
+		s.w.Header().Set("Content-Type", contentTypeNDJSON)
+		s.sent = true
+	}
+	if _, err := s.w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// finish completes the response after the streaming method returned err.
+func (s *ndjsonStream) finish(err error) {
+	switch {
+	case err == nil && !s.sent:
+		// Empty streams still respond with the streaming content type.
+		s.w.Header().Set("Content-Type", contentTypeNDJSON)
+		s.w.WriteHeader(http.StatusOK)
+	case err != nil && !s.sent:
+		writeError(s.w, err)
+	case err != nil:
+		st := convertError(err)
+		json.NewEncoder(s.w).Encode(map[string]httpError{"error": {Code: st.Code().String(), Message: st.Message()}})
+	}
+}
+
+func (s *ndjsonStream) Context() context.Context { return s.ctx }
+
+// RecvMsg receives the request, which is the only message clients send.
+func (s *ndjsonStream) RecvMsg(m interface{}) error {
+	if s.req == nil {
+		return io.EOF
+	}
+	proto.Merge(m.(proto.Message), s.req)
+	s.req = nil
+	return nil
+}
+func (s *ndjsonStream) SetHeader(metadata.MD) error  { return nil }
+func (s *ndjsonStream) SendHeader(metadata.MD) error { return nil }
+func (s *ndjsonStream) SetTrailer(metadata.MD)       {}
+
+type packageNameStream struct{ grpc.ServerStream }
+
+func (s *packageNameStream) Send(m *api.PackageName) error { return s.SendMsg(m) }
+
+type bundleStream struct {
+	grpc.ServerStream
+	packageName string
+}
+
+func (s *bundleStream) Send(m *api.Bundle) error { return s.SendMsg(m) }
+
+// SendMsg filters bundles by package, including cached bundles that aren't sent with Send.
+func (s *bundleStream) SendMsg(m interface{}) error {
+	if b := unwrapMessage(m).(*api.Bundle); s.packageName != "" && b.GetPackageName() != s.packageName {
+		return nil
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+type channelEntryStream struct{ grpc.ServerStream }
+
+func (s *channelEntryStream) Send(m *api.ChannelEntry) error { return s.SendMsg(m) }
+
+type watchEventStream struct{ grpc.ServerStream }
+
+func (s *watchEventStream) Send(m *api.WatchEvent) error { return s.SendMsg(m) }
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	m := watchTestModel(map[string][]string{
+		"stable": {"0.1.0", "0.2.0"},
+		"beta":   {"0.2.0"},
+	})
+	srv := httptest.NewServer(NewHTTPHandler(NewRegistryServer(registry.NewQuerier(m))))
+	defer srv.Close()
+
+	get := func(t *testing.T, path string) (int, string, string) {
+		resp, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, resp.Header.Get("Content-Type"), string(body)
+	}
+
+	// lines decodes each line of an NDJSON body into a generic object.
+	lines := func(t *testing.T, body string) []map[string]interface{} {
+		var objs []map[string]interface{}
+		scanner := bufio.NewScanner(strings.NewReader(body))
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			obj := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &obj))
+			objs = append(objs, obj)
+		}
+		return objs
+	}
+
+	t.Run("ListPackages", func(t *testing.T) {
+		code, contentType, body := get(t, "/api/v1/packages")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, contentTypeNDJSON, contentType)
+		require.Equal(t, []map[string]interface{}{{"name": "foo"}}, lines(t, body))
+	})
+
+	t.Run("GetPackage", func(t *testing.T) {
+		code, contentType, body := get(t, "/api/v1/packages/foo")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, contentTypeJSON, contentType)
+		pkg := lines(t, body)
+		require.Len(t, pkg, 1)
+		require.Equal(t, "foo", pkg[0]["name"])
+		require.Equal(t, "stable", pkg[0]["defaultChannelName"])
+		require.Len(t, pkg[0]["channels"], 2)
+	})
+
+	t.Run("GetBundleForChannel", func(t *testing.T) {
+		code, _, body := get(t, "/api/v1/packages/foo/channels/stable/head")
+		require.Equal(t, http.StatusOK, code)
+		bundle := lines(t, body)
+		require.Len(t, bundle, 1)
+		require.Equal(t, "foo.v0.2.0", bundle[0]["csvName"])
+	})
+
+	t.Run("GetBundleThatReplaces", func(t *testing.T) {
+		code, _, body := get(t, "/api/v1/packages/foo/channels/stable/bundles/foo.v0.1.0/replacement")
+		require.Equal(t, http.StatusOK, code)
+		bundle := lines(t, body)
+		require.Len(t, bundle, 1)
+		require.Equal(t, "foo.v0.2.0", bundle[0]["csvName"])
+	})
+
+	t.Run("ListBundles", func(t *testing.T) {
+		code, _, body := get(t, "/api/v1/bundles")
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, lines(t, body), 3)
+
+		code, _, body = get(t, "/api/v1/bundles?package=foo")
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, lines(t, body), 3)
+
+		code, contentType, body := get(t, "/api/v1/bundles?package=bar")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, contentTypeNDJSON, contentType)
+		require.Empty(t, body)
+	})
+
+	t.Run("GetChannelEntriesThatReplace", func(t *testing.T) {
+		code, _, body := get(t, "/api/v1/bundles/foo.v0.1.0/replacements")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, []map[string]interface{}{{
+			"packageName": "foo",
+			"channelName": "stable",
+			"bundleName":  "foo.v0.2.0",
+			"replaces":    "foo.v0.1.0",
+		}}, lines(t, body))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		code, contentType, body := get(t, "/api/v1/catalog")
+		require.Equal(t, http.StatusServiceUnavailable, code)
+		require.Equal(t, contentTypeJSON, contentType)
+		require.Equal(t, []map[string]interface{}{{"code": "Unavailable", "message": "catalog info not available"}}, lines(t, body))
+
+		code, _, _ = get(t, "/api/v1/watch")
+		require.Equal(t, http.StatusNotImplemented, code)
+
+		code, _, _ = get(t, "/api/v1/providers?latest=maybe")
+		require.Equal(t, http.StatusBadRequest, code)
+
+		code, _, _ = get(t, "/api/v1/unknown")
+		require.Equal(t, http.StatusNotFound, code)
+
+		// Stores report missing content with plain errors, which are still not found.
+		for _, path := range []string{
+			"/api/v1/packages/missing",
+			"/api/v1/packages/foo/channels/missing/head",
+			"/api/v1/packages/foo/channels/stable/bundles/missing",
+			"/api/v1/bundles/missing/replacements",
+			"/api/v1/providers/default?group=missing&version=v1&kind=Missing",
+		} {
+			code, _, body := get(t, path)
+			require.Equal(t, http.StatusNotFound, code, path)
+			require.Equal(t, "NotFound", lines(t, body)[0]["code"], path)
+		}
+
+		resp, err := http.Post(srv.URL+"/api/v1/packages", contentTypeJSON, nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
+
+func TestHTTPHandlerInterceptors(t *testing.T) {
+	// Requests are handled through the interceptors of the gRPC server, seeing the
+	// same methods, requests and peers as RPCs would.
+	type call struct {
+		method, pkg string
+		peer        bool
+	}
+	var calls []call
+	record := func(ctx context.Context, method string, req interface{}) {
+		_, ok := peer.FromContext(ctx)
+		calls = append(calls, call{method: method, pkg: requestPackage(req), peer: ok})
+	}
+	interceptors := Interceptors{
+		Unary: []grpc.UnaryServerInterceptor{func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			record(ctx, info.FullMethod, req)
+			return handler(ctx, req)
+		}},
+		Stream: []grpc.StreamServerInterceptor{func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			rs := &recordingServerStream{ServerStream: ss}
+			err := handler(srv, rs)
+			record(ss.Context(), info.FullMethod, rs.first)
+			return err
+		}},
+	}
+	limiter, err := NewLimiter(Limits{PeerRate: 1, PeerBurst: 3})
+	require.NoError(t, err)
+	limits := NewInterceptors(nil, nil, limiter)
+	interceptors.Unary = append(interceptors.Unary, limits.Unary...)
+	interceptors.Stream = append(interceptors.Stream, limits.Stream...)
+
+	m := watchTestModel(map[string][]string{"stable": {"0.1.0"}})
+	srv := httptest.NewServer(NewHTTPHandler(NewRegistryServer(registry.NewQuerier(m)), WithHTTPInterceptors(interceptors)))
+	defer srv.Close()
+	get := func(path string) int {
+		resp, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	require.Equal(t, http.StatusOK, get("/api/v1/packages/foo"))
+	require.Equal(t, http.StatusOK, get("/api/v1/bundles"))
+	require.Equal(t, http.StatusOK, get("/api/v1/packages/foo/channels/stable/head"))
+	require.Equal(t, []call{
+		{method: "/api.Registry/GetPackage", pkg: "foo", peer: true},
+		{method: "/api.Registry/ListBundles", peer: true},
+		{method: "/api.Registry/GetBundleForChannel", pkg: "foo", peer: true},
+	}, calls)
+
+	// Clients are limited across both APIs.
+	require.Equal(t, http.StatusTooManyRequests, get("/api/v1/packages/foo"))
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// Interceptors log, record metrics for and limit the requests handled by a
+// server. The same interceptors are installed in the gRPC server and the HTTP gateway,
+// so requests are handled alike whichever way they arrive.
+type Interceptors struct {
+	Unary  []grpc.UnaryServerInterceptor
+	Stream []grpc.StreamServerInterceptor
+}
+
+// NewInterceptors returns the interceptors for the given access logger, metrics and
+// limiter. Interceptors are left out for nil arguments.
+func NewInterceptors(accessLogger logrus.FieldLogger, metrics *Metrics, limiter *Limiter) Interceptors {
+	var i Interceptors
+	if accessLogger != nil {
+		i.Unary = append(i.Unary, UnaryAccessLogInterceptor(accessLogger))
+		i.Stream = append(i.Stream, StreamAccessLogInterceptor(accessLogger))
+	}
+	if metrics != nil {
+		i.Unary = append(i.Unary, metrics.UnaryServerInterceptor())
+		i.Stream = append(i.Stream, metrics.StreamServerInterceptor())
+	}
+	// Limits are enforced last, so that rejected requests are still logged and counted
+	if limiter != nil {
+		i.Unary = append(i.Unary, limiter.UnaryServerInterceptor())
+		i.Stream = append(i.Stream, limiter.StreamServerInterceptor())
+	}
+	return i
+}
+
+// ServerOptions returns the options that install the interceptors in a gRPC server.
+func (i Interceptors) ServerOptions() []grpc.ServerOption {
+	var opts []grpc.ServerOption
+	if len(i.Unary) > 0 {
+		opts = append(opts, grpc.ChainUnaryInterceptor(i.Unary...))
+	}
+	if len(i.Stream) > 0 {
+		opts = append(opts, grpc.ChainStreamInterceptor(i.Stream...))
+	}
+	return opts
+}
+
+// InterceptorOptions returns the server options that log, record metrics for and limit
+// the RPCs handled. Interceptors are left out for nil arguments.
+func InterceptorOptions(accessLogger logrus.FieldLogger, metrics *Metrics, limiter *Limiter) []grpc.ServerOption {
+	return NewInterceptors(accessLogger, metrics, limiter).ServerOptions()
+}
+
+// unaryHandler returns handler wrapped in the unary interceptors, the first outermost,
+// the way a gRPC server would call it for the method.
+func (i Interceptors) unaryHandler(fullMethod string, srv interface{}, handler grpc.UnaryHandler) grpc.UnaryHandler {
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}
+	for j := len(i.Unary) - 1; j >= 0; j-- {
+		interceptor, next := i.Unary[j], handler
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+	return handler
+}
+
+// streamHandler returns handler wrapped in the stream interceptors, the first
+// outermost, the way a gRPC server would call it for the server streaming method.
+func (i Interceptors) streamHandler(fullMethod string, handler grpc.StreamHandler) grpc.StreamHandler {
+	info := &grpc.StreamServerInfo{FullMethod: fullMethod, IsServerStream: true}
+	for j := len(i.Stream) - 1; j >= 0; j-- {
+		interceptor, next := i.Stream[j], handler
+		handler = func(srv interface{}, ss grpc.ServerStream) error {
+			return interceptor(srv, ss, info, next)
+		}
+	}
+	return handler
+}
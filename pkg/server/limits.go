@@ -0,0 +1,308 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	v2 "github.com/operator-framework/operator-registry/pkg/api/v2"
+)
+
+// Reasons a request is rejected by a Limiter.
+const (
+	RejectReasonInFlight = "in_flight"
+	RejectReasonMethod   = "method_concurrency"
+	RejectReasonPeerRate = "peer_rate"
+)
+
+const (
+	// healthService is exempt from limits, so that probes keep passing while a server
+	// sheds load.
+	healthService = "grpc.health.v1.Health"
+
+	// idlePeerTimeout is how long the rate limiter of a peer is kept after its last request.
+	idlePeerTimeout = 10 * time.Minute
+)
+
+// Limits bound the requests a server handles. Zero values are unlimited.
+type Limits struct {
+	// MaxInFlight is the number of requests handled at once, across all methods.
+	MaxInFlight int
+	// MethodConcurrency is the number of requests handled at once for each method of
+	// the v1 and v2 registry APIs, keyed by method name (e.g. "ListBundles") or full
+	// method name (e.g. "/api.Registry/ListBundles").
+	MethodConcurrency map[string]int
+	// PeerRate is the number of requests per second accepted from each client address,
+	// allowing bursts of up to PeerBurst requests.
+	PeerRate  float64
+	PeerBurst int
+}
+
+// limitedServices are the services whose methods can be given concurrency limits.
+var limitedServices = []protoreflect.ServiceDescriptor{
+	api.File_registry_proto.Services().ByName("Registry"),
+	v2.File_v2_registry_proto.Services().ByName("Registry"),
+}
+
+// isLimitedMethod reports whether name is the method name or full method name of a
+// method of the limited services.
+func isLimitedMethod(name string) bool {
+	for _, service := range limitedServices {
+		methods := service.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			method := methods.Get(i).Name()
+			if name == string(method) || name == fmt.Sprintf("/%s/%s", service.FullName(), method) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (l Limits) enabled() bool {
+	return l.MaxInFlight > 0 || len(l.MethodConcurrency) > 0 || l.PeerRate > 0
+}
+
+// AddLimitFlags adds the flags read by LimitsFromFlags to fs.
+func AddLimitFlags(fs *pflag.FlagSet) {
+	fs.Int("max-in-flight", 0, "maximum number of requests handled at once across all methods, unlimited if 0")
+	fs.StringToInt("max-concurrent-requests", nil, "maximum number of requests handled at once for a method of the registry API, e.g. ListBundles=2,GetBundle=20")
+	fs.Float64("peer-rate-limit", 0, "requests per second accepted from each client address, unlimited if 0")
+	fs.Int("peer-rate-burst", 0, "requests accepted at once from each client address before --peer-rate-limit applies, defaults to the rate rounded up")
+}
+
+// LimitsFromFlags returns the limits set by the flags added by AddLimitFlags.
+func LimitsFromFlags(fs *pflag.FlagSet) (Limits, error) {
+	var (
+		l   Limits
+		err error
+	)
+	if l.MaxInFlight, err = fs.GetInt("max-in-flight"); err != nil {
+		return l, err
+	}
+	if l.MethodConcurrency, err = fs.GetStringToInt("max-concurrent-requests"); err != nil {
+		return l, err
+	}
+	if l.PeerRate, err = fs.GetFloat64("peer-rate-limit"); err != nil {
+		return l, err
+	}
+	if l.PeerBurst, err = fs.GetInt("peer-rate-burst"); err != nil {
+		return l, err
+	}
+	return l, l.validate()
+}
+
+func (l Limits) validate() error {
+	if l.MaxInFlight < 0 {
+		return fmt.Errorf("invalid in-flight limit %d, must not be negative", l.MaxInFlight)
+	}
+	for method, limit := range l.MethodConcurrency {
+		if !isLimitedMethod(method) {
+			return fmt.Errorf("invalid concurrency limit for unknown method %q", method)
+		}
+		if limit <= 0 {
+			return fmt.Errorf("invalid concurrency limit %d for method %q, must be positive", limit, method)
+		}
+	}
+	if l.PeerRate < 0 || math.IsInf(l.PeerRate, 0) || math.IsNaN(l.PeerRate) {
+		return fmt.Errorf("invalid peer rate limit %v, must be a positive number of requests per second", l.PeerRate)
+	}
+	if l.PeerBurst < 0 {
+		return fmt.Errorf("invalid peer rate burst %d, must not be negative", l.PeerBurst)
+	}
+	return nil
+}
+
+// Limiter sheds requests in excess of its limits, failing them with ResourceExhausted
+// instead of queueing them. Health checks are never limited.
+type Limiter struct {
+	limits   Limits
+	inFlight chan struct{}
+	methods  map[string]chan struct{}
+	observe  func(fullMethod, reason string)
+	logger   logrus.FieldLogger
+	now      func() time.Time
+
+	mu        sync.Mutex
+	peers     map[string]*peerLimiter
+	lastSweep time.Time
+}
+
+type peerLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+type LimiterOption func(*Limiter)
+
+// WithRejectObserver calls observe with the full method name and reason of every
+// request rejected.
+func WithRejectObserver(observe func(fullMethod, reason string)) LimiterOption {
+	return func(l *Limiter) {
+		l.observe = observe
+	}
+}
+
+// WithRejectLogger logs every request rejected at debug level.
+func WithRejectLogger(logger logrus.FieldLogger) LimiterOption {
+	return func(l *Limiter) {
+		l.logger = logger
+	}
+}
+
+// NewLimiter returns a Limiter enforcing limits, or nil if there are none.
+func NewLimiter(limits Limits, opts ...LimiterOption) (*Limiter, error) {
+	if err := limits.validate(); err != nil {
+		return nil, err
+	}
+	if !limits.enabled() {
+		return nil, nil
+	}
+	if limits.PeerRate > 0 && limits.PeerBurst == 0 {
+		limits.PeerBurst = int(math.Ceil(limits.PeerRate))
+	}
+	l := &Limiter{
+		limits:  limits,
+		methods: map[string]chan struct{}{},
+		now:     time.Now,
+		peers:   map[string]*peerLimiter{},
+	}
+	if limits.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, limits.MaxInFlight)
+	}
+	for method, limit := range limits.MethodConcurrency {
+		l.methods[method] = make(chan struct{}, limit)
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l, nil
+}
+
+// UnaryServerInterceptor returns an interceptor that enforces the limits on unary RPCs.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		release, err := l.acquire(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns an interceptor that enforces the limits on streaming
+// RPCs, which count against them until the stream ends.
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, err := l.acquire(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		defer release()
+		return handler(srv, ss)
+	}
+}
+
+// acquire admits a request, returning a func to call once it has been handled.
+func (l *Limiter) acquire(ctx context.Context, fullMethod string) (func(), error) {
+	service, method := splitMethodName(fullMethod)
+	if service == healthService {
+		return func() {}, nil
+	}
+
+	if l.limits.PeerRate > 0 && !l.allowPeer(ctx) {
+		return nil, l.reject(fullMethod, RejectReasonPeerRate, "rate limit exceeded, retry later")
+	}
+
+	var held []chan struct{}
+	release := func() {
+		for _, sem := range held {
+			<-sem
+		}
+	}
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+			held = append(held, l.inFlight)
+		default:
+			return nil, l.reject(fullMethod, RejectReasonInFlight, "server is handling too many requests, retry later")
+		}
+	}
+	sem, ok := l.methods[fullMethod]
+	if !ok {
+		sem, ok = l.methods[method]
+	}
+	if ok {
+		select {
+		case sem <- struct{}{}:
+			held = append(held, sem)
+		default:
+			release()
+			return nil, l.reject(fullMethod, RejectReasonMethod, fmt.Sprintf("server is handling too many %s requests, retry later", method))
+		}
+	}
+	return release, nil
+}
+
+func (l *Limiter) reject(fullMethod, reason, msg string) error {
+	if l.observe != nil {
+		l.observe(fullMethod, reason)
+	}
+	if l.logger != nil {
+		l.logger.WithFields(logrus.Fields{"method": fullMethod, "reason": reason}).Debug("rejected request")
+	}
+	return status.Error(codes.ResourceExhausted, msg)
+}
+
+// allowPeer reports whether the rate limit of the client that sent a request allows it.
+func (l *Limiter) allowPeer(ctx context.Context) bool {
+	key := peerKey(ctx)
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastSweep) > idlePeerTimeout {
+		for k, p := range l.peers {
+			if now.Sub(p.lastSeen) > idlePeerTimeout {
+				delete(l.peers, k)
+			}
+		}
+		l.lastSweep = now
+	}
+	p, ok := l.peers[key]
+	if !ok {
+		p = &peerLimiter{limiter: rate.NewLimiter(rate.Limit(l.limits.PeerRate), l.limits.PeerBurst)}
+		l.peers[key] = p
+	}
+	p.lastSeen = now
+	return p.limiter.AllowN(now, 1)
+}
+
+// peerKey identifies the client that sent a request by its address, without the port,
+// so that the limit applies across its connections.
+func peerKey(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return strings.TrimSpace(addr)
+}
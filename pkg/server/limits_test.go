@@ -0,0 +1,244 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type rejection struct {
+	method, reason string
+}
+
+// testLimiter returns a Limiter recording the requests it rejects.
+func testLimiter(t *testing.T, limits Limits) (*Limiter, *[]rejection) {
+	var rejected []rejection
+	l, err := NewLimiter(limits, WithRejectObserver(func(fullMethod, reason string) {
+		rejected = append(rejected, rejection{fullMethod, reason})
+	}))
+	require.NoError(t, err)
+	require.NotNil(t, l)
+	return l, &rejected
+}
+
+// blockingCall starts a unary call that is handled until the returned func is called.
+func blockingCall(t *testing.T, l *Limiter, ctx context.Context, fullMethod string) func() {
+	started, release, done := make(chan struct{}), make(chan struct{}), make(chan error)
+	go func() {
+		_, err := l.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: fullMethod}, func(context.Context, interface{}) (interface{}, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+		done <- err
+	}()
+	<-started
+	return func() {
+		close(release)
+		require.NoError(t, <-done)
+	}
+}
+
+func unaryCall(l *Limiter, ctx context.Context, fullMethod string) error {
+	_, err := l.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: fullMethod}, func(context.Context, interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	return err
+}
+
+func streamCall(l *Limiter, ctx context.Context, fullMethod string) error {
+	return l.StreamServerInterceptor()(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: fullMethod, IsServerStream: true}, func(interface{}, grpc.ServerStream) error {
+		return nil
+	})
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func peerContext(addr string) context.Context {
+	tcpAddr, err := net.ResolveTCPAddr("tcp", addr)
+	if err != nil {
+		panic(err)
+	}
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: tcpAddr})
+}
+
+func requireExhausted(t *testing.T, err error) {
+	t.Helper()
+	require.Error(t, err)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestLimiterInFlight(t *testing.T) {
+	l, rejected := testLimiter(t, Limits{MaxInFlight: 1})
+	ctx := context.Background()
+
+	release := blockingCall(t, l, ctx, "/api.Registry/GetPackage")
+	requireExhausted(t, unaryCall(l, ctx, "/api.Registry/GetBundle"))
+	requireExhausted(t, streamCall(l, ctx, "/api.Registry/ListBundles"))
+	require.NoError(t, unaryCall(l, ctx, "/grpc.health.v1.Health/Check"))
+	release()
+
+	require.NoError(t, unaryCall(l, ctx, "/api.Registry/GetBundle"))
+	require.NoError(t, streamCall(l, ctx, "/api.Registry/ListBundles"))
+	require.Equal(t, []rejection{
+		{"/api.Registry/GetBundle", RejectReasonInFlight},
+		{"/api.Registry/ListBundles", RejectReasonInFlight},
+	}, *rejected)
+}
+
+func TestLimiterMethodConcurrency(t *testing.T) {
+	l, rejected := testLimiter(t, Limits{
+		MaxInFlight:       3,
+		MethodConcurrency: map[string]int{"GetPackage": 1, "/registry.v2.Registry/GetBundle": 1},
+	})
+	ctx := context.Background()
+
+	releasePackage := blockingCall(t, l, ctx, "/api.Registry/GetPackage")
+	releaseBundle := blockingCall(t, l, ctx, "/registry.v2.Registry/GetBundle")
+	requireExhausted(t, unaryCall(l, ctx, "/api.Registry/GetPackage"))
+	requireExhausted(t, unaryCall(l, ctx, "/registry.v2.Registry/GetBundle"))
+	require.NoError(t, unaryCall(l, ctx, "/api.Registry/GetBundle"))
+
+	// Rejected requests don't hold on to their share of the in-flight limit
+	release := blockingCall(t, l, ctx, "/api.Registry/GetBundle")
+	requireExhausted(t, unaryCall(l, ctx, "/api.Registry/GetBundle"))
+	release()
+	releasePackage()
+	releaseBundle()
+
+	require.NoError(t, unaryCall(l, ctx, "/api.Registry/GetPackage"))
+	require.Equal(t, []rejection{
+		{"/api.Registry/GetPackage", RejectReasonMethod},
+		{"/registry.v2.Registry/GetBundle", RejectReasonMethod},
+		{"/api.Registry/GetBundle", RejectReasonInFlight},
+	}, *rejected)
+}
+
+func TestLimiterPeerRate(t *testing.T) {
+	l, rejected := testLimiter(t, Limits{PeerRate: 1, PeerBurst: 2})
+	now := time.Unix(0, 0)
+	l.now = func() time.Time { return now }
+
+	a1, a2, b := peerContext("10.0.0.1:40000"), peerContext("10.0.0.1:40001"), peerContext("10.0.0.2:40000")
+	require.NoError(t, unaryCall(l, a1, "/api.Registry/GetPackage"))
+	require.NoError(t, streamCall(l, a2, "/api.Registry/ListBundles"))
+	requireExhausted(t, unaryCall(l, a1, "/api.Registry/GetPackage"))
+	requireExhausted(t, unaryCall(l, a2, "/api.Registry/GetPackage"))
+	require.NoError(t, unaryCall(l, a1, "/grpc.health.v1.Health/Check"))
+	require.NoError(t, unaryCall(l, b, "/api.Registry/GetPackage"))
+
+	now = now.Add(time.Second)
+	require.NoError(t, unaryCall(l, a1, "/api.Registry/GetPackage"))
+	requireExhausted(t, unaryCall(l, a2, "/api.Registry/GetPackage"))
+	require.Len(t, *rejected, 3)
+	require.Equal(t, RejectReasonPeerRate, (*rejected)[0].reason)
+
+	// Idle peers are forgotten
+	now = now.Add(2 * idlePeerTimeout)
+	require.NoError(t, unaryCall(l, b, "/api.Registry/GetPackage"))
+	require.Len(t, l.peers, 1)
+}
+
+func TestNewLimiter(t *testing.T) {
+	l, err := NewLimiter(Limits{})
+	require.NoError(t, err)
+	require.Nil(t, l)
+
+	l, err = NewLimiter(Limits{PeerRate: 2.5})
+	require.NoError(t, err)
+	require.Equal(t, 3, l.limits.PeerBurst)
+
+	for _, limits := range []Limits{
+		{MaxInFlight: -1},
+		{MethodConcurrency: map[string]int{"ListBundles": 0}},
+		{MethodConcurrency: map[string]int{"ListBundle": 1}},
+		{MethodConcurrency: map[string]int{"/api.Registry/ListBundle": 1}},
+		{MethodConcurrency: map[string]int{"/grpc.health.v1.Health/Check": 1}},
+		{PeerRate: -1},
+		{PeerRate: 1, PeerBurst: -1},
+	} {
+		_, err := NewLimiter(limits)
+		require.Error(t, err, "%+v", limits)
+	}
+}
+
+func TestLimitsFromFlags(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	AddLimitFlags(fs)
+	require.NoError(t, fs.Parse([]string{
+		"--max-in-flight=100",
+		"--max-concurrent-requests=ListBundles=2,GetBundle=20",
+		"--peer-rate-limit=0.5",
+	}))
+	limits, err := LimitsFromFlags(fs)
+	require.NoError(t, err)
+	require.Equal(t, Limits{
+		MaxInFlight:       100,
+		MethodConcurrency: map[string]int{"ListBundles": 2, "GetBundle": 20},
+		PeerRate:          0.5,
+	}, limits)
+
+	fs = pflag.NewFlagSet("test", pflag.ContinueOnError)
+	AddLimitFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--max-concurrent-requests=ListBundles=-1"}))
+	_, err = LimitsFromFlags(fs)
+	require.Error(t, err)
+
+	fs = pflag.NewFlagSet("test", pflag.ContinueOnError)
+	AddLimitFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--max-concurrent-requests=ListBundle=2"}))
+	_, err = LimitsFromFlags(fs)
+	require.EqualError(t, err, `invalid concurrency limit for unknown method "ListBundle"`)
+}
+
+func TestLimiterRejectLogger(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+	l, err := NewLimiter(Limits{PeerRate: 1}, WithRejectLogger(logger))
+	require.NoError(t, err)
+	ctx := peerContext("10.0.0.1:40000")
+	require.NoError(t, unaryCall(l, ctx, "/api.Registry/GetPackage"))
+	requireExhausted(t, unaryCall(l, ctx, "/api.Registry/GetPackage"))
+
+	require.Len(t, hook.AllEntries(), 1)
+	entry := hook.LastEntry()
+	require.Equal(t, logrus.DebugLevel, entry.Level)
+	require.Equal(t, logrus.Fields{"method": "/api.Registry/GetPackage", "reason": RejectReasonPeerRate}, entry.Data)
+}
+
+func TestMetricsObserveRejected(t *testing.T) {
+	metrics := NewMetrics()
+	l, err := NewLimiter(Limits{PeerRate: 1}, WithRejectObserver(metrics.ObserveRejected))
+	require.NoError(t, err)
+	ctx := peerContext("10.0.0.1:40000")
+	require.NoError(t, unaryCall(l, ctx, "/api.Registry/GetPackage"))
+	requireExhausted(t, unaryCall(l, ctx, "/api.Registry/GetPackage"))
+
+	srv := httptest.NewServer(metrics.Handler())
+	defer srv.Close()
+	resp, err := srv.Client().Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `grpc_server_rejected_total{grpc_method="GetPackage",grpc_service="api.Registry",reason="peer_rate"} 1`)
+}
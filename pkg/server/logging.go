@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+)
+
+// UnaryAccessLogInterceptor returns an interceptor that logs every unary RPC handled,
+// along with the package it asked for, how long it took and its status code.
+func UnaryAccessLogInterceptor(logger logrus.FieldLogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logAccess(ctx, logger, info.FullMethod, req, start, err)
+		return resp, err
+	}
+}
+
+// StreamAccessLogInterceptor returns an interceptor that logs every streaming RPC
+// handled, along with the package its first request asked for, how long it took and
+// its status code.
+func StreamAccessLogInterceptor(logger logrus.FieldLogger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		rs := &recordingServerStream{ServerStream: ss}
+		err := handler(srv, rs)
+		logAccess(ss.Context(), logger, info.FullMethod, rs.first, start, err)
+		return err
+	}
+}
+
+func logAccess(ctx context.Context, logger logrus.FieldLogger, method string, req interface{}, start time.Time, err error) {
+	code := status.Code(err)
+	fields := logrus.Fields{
+		"method":  method,
+		"latency": time.Since(start).String(),
+		"code":    code.String(),
+	}
+	if pkg := requestPackage(req); pkg != "" {
+		fields["package"] = pkg
+	}
+	entry := logger.WithFields(fields)
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	entry.Info("handled request")
+}
+
+// requestPackage returns the name of the package a request asks for, if any.
+func requestPackage(req interface{}) string {
+	switch r := req.(type) {
+	case *api.GetPackageRequest:
+		return r.GetName()
+	case interface{ GetPkgName() string }:
+		return r.GetPkgName()
+	case interface{ GetPackage() string }:
+		return r.GetPackage()
+	}
+	return ""
+}
+
+// recordingServerStream records the first message received on a stream.
+type recordingServerStream struct {
+	grpc.ServerStream
+	first interface{}
+}
+
+func (s *recordingServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.first == nil {
+		s.first = m
+	}
+	return err
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+func TestAccessLog(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		store func(t *testing.T) registry.GRPCQuery
+	}{
+		{
+			name: "SQL",
+			store: func(t *testing.T) registry.GRPCQuery {
+				return dbStore(filepath.Join(t.TempDir(), "test.db"))
+			},
+		},
+		{
+			name: "Memory",
+			store: func(t *testing.T) registry.GRPCQuery {
+				return registry.NewQuerier(cfgModel())
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, hook := logtest.NewNullLogger()
+
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			s := grpc.NewServer(InterceptorOptions(logger, nil, nil)...)
+			api.RegisterRegistryServer(s, NewRegistryServer(tt.store(t)))
+			go s.Serve(lis)
+			defer s.Stop()
+
+			conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+			require.NoError(t, err)
+			defer conn.Close()
+			c := api.NewRegistryClient(conn)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			_, err = c.GetPackage(ctx, &api.GetPackageRequest{Name: "etcd"})
+			require.NoError(t, err)
+			_, err = c.GetPackage(ctx, &api.GetPackageRequest{Name: "missing"})
+			require.Error(t, err)
+			stream, err := c.GetChannelEntriesThatReplace(ctx, &api.GetAllReplacementsRequest{CsvName: "etcdoperator.v0.9.0"})
+			require.NoError(t, err)
+			for {
+				if _, err := stream.Recv(); err != nil {
+					break
+				}
+			}
+			s.GracefulStop()
+
+			entries := hook.AllEntries()
+			require.Len(t, entries, 3)
+			for _, e := range entries {
+				require.Equal(t, logrus.InfoLevel, e.Level)
+				require.Equal(t, "handled request", e.Message)
+				require.NotEmpty(t, e.Data["latency"])
+			}
+
+			require.Equal(t, "/api.Registry/GetPackage", entries[0].Data["method"])
+			require.Equal(t, "etcd", entries[0].Data["package"])
+			require.Equal(t, "OK", entries[0].Data["code"])
+			require.Nil(t, entries[0].Data[logrus.ErrorKey])
+
+			require.Equal(t, "missing", entries[1].Data["package"])
+			require.NotEqual(t, "OK", entries[1].Data["code"])
+			require.NotNil(t, entries[1].Data[logrus.ErrorKey])
+
+			require.Equal(t, "/api.Registry/GetChannelEntriesThatReplace", entries[2].Data["method"])
+			require.Equal(t, "OK", entries[2].Data["code"])
+			require.NotContains(t, entries[2].Data, "package")
+		})
+	}
+}
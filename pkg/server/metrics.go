@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+)
+
+// Metrics collects Prometheus metrics for the RPCs handled by a registry server and
+// the catalog it serves.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	handled  *prometheus.CounterVec
+	handling *prometheus.HistogramVec
+	rejected *prometheus.CounterVec
+
+	packages          prometheus.Gauge
+	channels          prometheus.Gauge
+	bundles           prometheus.Gauge
+	loadDuration      prometheus.Gauge
+	lastReloadSuccess prometheus.Gauge
+	lastReloadTime    prometheus.Gauge
+}
+
+// NewMetrics returns Metrics registered with a new registry, along with the standard
+// Go runtime and process collectors.
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Total number of RPCs completed on the server, regardless of success or failure.",
+		}, []string{"grpc_type", "grpc_service", "grpc_method", "grpc_code"}),
+		handling: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Histogram of response latency (seconds) of RPCs handled by the server.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"grpc_type", "grpc_service", "grpc_method"}),
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_rejected_total",
+			Help: "Total number of RPCs rejected by the server for exceeding its limits.",
+		}, []string{"grpc_service", "grpc_method", "reason"}),
+		packages: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "registry_catalog_packages",
+			Help: "Number of packages in the served catalog.",
+		}),
+		channels: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "registry_catalog_channels",
+			Help: "Number of channels in the served catalog.",
+		}),
+		bundles: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "registry_catalog_bundles",
+			Help: "Number of bundles in the served catalog.",
+		}),
+		loadDuration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "registry_catalog_load_duration_seconds",
+			Help: "Time taken to load the served catalog.",
+		}),
+		lastReloadSuccess: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "registry_catalog_last_reload_success",
+			Help: "Whether the last attempt to load the catalog succeeded (1) or failed (0).",
+		}),
+		lastReloadTime: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "registry_catalog_last_reload_timestamp_seconds",
+			Help: "Unix time of the last attempt to load the catalog.",
+		}),
+	}
+	m.registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		m.handled,
+		m.handling,
+		m.rejected,
+		m.packages,
+		m.channels,
+		m.bundles,
+		m.loadDuration,
+		m.lastReloadSuccess,
+		m.lastReloadTime,
+	)
+	return m
+}
+
+// Handler returns an HTTP handler that serves the collected metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveLoad records an attempt to load the catalog that took duration. If it
+// succeeded, the catalog gauges are set from info; otherwise they keep describing
+// the catalog that is still being served.
+func (m *Metrics) ObserveLoad(info *api.CatalogInfo, duration time.Duration, err error) {
+	m.lastReloadTime.SetToCurrentTime()
+	if err != nil {
+		m.lastReloadSuccess.Set(0)
+		return
+	}
+	m.lastReloadSuccess.Set(1)
+	m.loadDuration.Set(duration.Seconds())
+	if info != nil {
+		m.packages.Set(float64(info.GetPackageCount()))
+		m.channels.Set(float64(info.GetChannelCount()))
+		m.bundles.Set(float64(info.GetBundleCount()))
+	}
+}
+
+// UnaryServerInterceptor returns an interceptor that records unary RPCs.
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observe("unary", info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor that records streaming RPCs.
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.observe(streamType(info), info.FullMethod, start, err)
+		return err
+	}
+}
+
+// ObserveRejected records an RPC rejected for exceeding a limit of the server.
+func (m *Metrics) ObserveRejected(fullMethod, reason string) {
+	service, method := splitMethodName(fullMethod)
+	m.rejected.WithLabelValues(service, method, reason).Inc()
+}
+
+func (m *Metrics) observe(rpcType, fullMethod string, start time.Time, err error) {
+	service, method := splitMethodName(fullMethod)
+	m.handled.WithLabelValues(rpcType, service, method, status.Code(err).String()).Inc()
+	m.handling.WithLabelValues(rpcType, service, method).Observe(time.Since(start).Seconds())
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return "bidi_stream"
+	case info.IsClientStream:
+		return "client_stream"
+	default:
+		return "server_stream"
+	}
+}
+
+// splitMethodName splits a full method name of the form "/service/method".
+func splitMethodName(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.Index(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
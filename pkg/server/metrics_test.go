@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+func TestMetrics(t *testing.T) {
+	m := watchTestModel(map[string][]string{"stable": {"0.1.0", "0.2.0"}})
+	info := NewModelCatalogInfo(SourceTypeDeclcfg, "sha256:abc", time.Now(), m)
+
+	metrics := NewMetrics()
+	metrics.ObserveLoad(info, 1500*time.Millisecond, nil)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(metrics.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(metrics.StreamServerInterceptor()),
+	)
+	api.RegisterRegistryServer(s, NewRegistryServer(registry.NewQuerier(m)))
+	go s.Serve(lis)
+	defer s.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+	c := api.NewRegistryClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i := 0; i < 2; i++ {
+		_, err = c.GetPackage(ctx, &api.GetPackageRequest{Name: "foo"})
+		require.NoError(t, err)
+	}
+	_, err = c.GetCatalogInfo(ctx, &api.GetCatalogInfoRequest{})
+	require.Error(t, err)
+	stream, err := c.ListPackages(ctx, &api.ListPackageRequest{})
+	require.NoError(t, err)
+	for {
+		if _, err := stream.Recv(); errors.Is(err, io.EOF) {
+			break
+		} else {
+			require.NoError(t, err)
+		}
+	}
+
+	srv := httptest.NewServer(metrics.Handler())
+	defer srv.Close()
+	scrape := func(t *testing.T) string {
+		resp, err := http.Get(srv.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	t.Run("RPCs", func(t *testing.T) {
+		body := scrape(t)
+		require.Contains(t, body, `grpc_server_handled_total{grpc_code="OK",grpc_method="GetPackage",grpc_service="api.Registry",grpc_type="unary"} 2`)
+		require.Contains(t, body, `grpc_server_handled_total{grpc_code="Unavailable",grpc_method="GetCatalogInfo",grpc_service="api.Registry",grpc_type="unary"} 1`)
+		require.Contains(t, body, `grpc_server_handled_total{grpc_code="OK",grpc_method="ListPackages",grpc_service="api.Registry",grpc_type="server_stream"} 1`)
+		require.Contains(t, body, `grpc_server_handling_seconds_count{grpc_method="GetPackage",grpc_service="api.Registry",grpc_type="unary"} 2`)
+	})
+
+	t.Run("Catalog", func(t *testing.T) {
+		body := scrape(t)
+		require.Contains(t, body, "registry_catalog_packages 1\n")
+		require.Contains(t, body, "registry_catalog_channels 1\n")
+		require.Contains(t, body, "registry_catalog_bundles 2\n")
+		require.Contains(t, body, "registry_catalog_load_duration_seconds 1.5\n")
+		require.Contains(t, body, "registry_catalog_last_reload_success 1\n")
+	})
+
+	t.Run("FailedReload", func(t *testing.T) {
+		metrics.ObserveLoad(nil, time.Second, errors.New("invalid configs"))
+		body := scrape(t)
+		require.Contains(t, body, "registry_catalog_last_reload_success 0\n")
+		require.Contains(t, body, "registry_catalog_packages 1\n")
+		require.Contains(t, body, "registry_catalog_load_duration_seconds 1.5\n")
+	})
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+	"github.com/operator-framework/operator-registry/pkg/sqlite"
+)
+
+// rpcResult is the outcome of an RPC: the messages it returned, and its status code.
+type rpcResult struct {
+	msgs []proto.Message
+	code codes.Code
+}
+
+func unaryResult(m proto.Message, err error) rpcResult {
+	if err != nil {
+		return rpcResult{code: status.Code(err)}
+	}
+	return rpcResult{msgs: []proto.Message{m}}
+}
+
+func streamResult(recv func() (proto.Message, error), err error) rpcResult {
+	var res rpcResult
+	for err == nil {
+		var m proto.Message
+		if m, err = recv(); err == nil {
+			res.msgs = append(res.msgs, m)
+		}
+	}
+	if err != io.EOF {
+		res.code = status.Code(err)
+	}
+	return res
+}
+
+// normalize sorts messages and their repeated fields, whose order the sql and memory
+// serve modes don't guarantee.
+func (r rpcResult) normalize(t *testing.T) []string {
+	var normalized []string
+	for _, m := range r.msgs {
+		m = proto.Clone(m)
+		switch m := m.(type) {
+		case *api.Bundle:
+			normalizeBundle(m)
+		case *api.Package:
+			sort.Slice(m.Channels, func(i, j int) bool { return m.Channels[i].Name < m.Channels[j].Name })
+		}
+		data, err := protojson.Marshal(m)
+		require.NoError(t, err)
+		normalized = append(normalized, string(data))
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+func normalizeBundle(b *api.Bundle) {
+	sort.Strings(b.Object)
+	sort.Slice(b.ProvidedApis, func(i, j int) bool { return b.ProvidedApis[i].String() < b.ProvidedApis[j].String() })
+	sort.Slice(b.RequiredApis, func(i, j int) bool { return b.RequiredApis[i].String() < b.RequiredApis[j].String() })
+	sort.Slice(b.Dependencies, func(i, j int) bool { return b.Dependencies[i].String() < b.Dependencies[j].String() })
+	sort.Slice(b.Properties, func(i, j int) bool { return b.Properties[i].String() < b.Properties[j].String() })
+}
+
+func serveParity(t *testing.T, store registry.GRPCQuery, info *api.CatalogInfo) api.RegistryClient {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := server(store, WithCatalogInfo(info))
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return api.NewRegistryClient(conn)
+}
+
+// TestServeModeParity requires that serving a sqlite database from memory answers every
+// RPC the same way as serving it with sql queries.
+func TestServeModeParity(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := dbStore(filepath.Join(t.TempDir(), "parity.db"))
+	info, err := NewSQLCatalogInfo(ctx, SourceTypeSqlite, "sha256:db", loadTime, sqlStore)
+	require.NoError(t, err)
+	memoryStore, err := sqlite.NewMemoryQuerier(ctx, sqlStore)
+	require.NoError(t, err)
+
+	sqlClient := serveParity(t, sqlStore, info)
+	memoryClient := serveParity(t, memoryStore, info)
+
+	type call struct {
+		name string
+		do   func(api.RegistryClient) rpcResult
+	}
+	var calls []call
+	add := func(name string, do func(api.RegistryClient) rpcResult) {
+		calls = append(calls, call{name: name, do: do})
+	}
+
+	add("GetCatalogInfo", func(c api.RegistryClient) rpcResult {
+		return unaryResult(c.GetCatalogInfo(ctx, &api.GetCatalogInfoRequest{}))
+	})
+	add("ListPackages", func(c api.RegistryClient) rpcResult {
+		stream, err := c.ListPackages(ctx, &api.ListPackageRequest{})
+		return streamResult(func() (proto.Message, error) { return stream.Recv() }, err)
+	})
+	add("ListBundles", func(c api.RegistryClient) rpcResult {
+		stream, err := c.ListBundles(ctx, &api.ListBundlesRequest{})
+		return streamResult(func() (proto.Message, error) { return stream.Recv() }, err)
+	})
+	add("Watch", func(c api.RegistryClient) rpcResult {
+		stream, err := c.Watch(ctx, &api.WatchRequest{})
+		return streamResult(func() (proto.Message, error) { return stream.Recv() }, err)
+	})
+
+	// Derive the remaining requests from the contents of the catalog, plus some that don't match anything.
+	stream, err := sqlClient.ListBundles(ctx, &api.ListBundlesRequest{})
+	require.NoError(t, err)
+	bundles := streamResult(func() (proto.Message, error) { return stream.Recv() }, err)
+	require.Equal(t, codes.OK, bundles.code)
+	require.NotEmpty(t, bundles.msgs)
+
+	packages := map[string]struct{}{"missing": {}}
+	csvNames := map[string]struct{}{"missing": {}}
+	type gvk struct{ Group, Version, Kind string }
+	gvks := map[gvk]struct{}{{Group: "missing", Version: "v1", Kind: "Missing"}: {}}
+	type replacement struct{ csvName, pkgName, channelName string }
+	replacements := map[replacement]struct{}{{"missing", "etcd", "alpha"}: {}}
+	for _, msg := range bundles.msgs {
+		b := msg.(*api.Bundle)
+		packages[b.PackageName] = struct{}{}
+		csvNames[b.CsvName] = struct{}{}
+		for _, provided := range b.ProvidedApis {
+			gvks[gvk{Group: provided.Group, Version: provided.Version, Kind: provided.Kind}] = struct{}{}
+		}
+		for _, replaced := range append([]string{b.Replaces}, b.Skips...) {
+			if replaced != "" {
+				csvNames[replaced] = struct{}{}
+				replacements[replacement{replaced, b.PackageName, b.ChannelName}] = struct{}{}
+			}
+		}
+
+		pkgName, channelName, csvName := b.PackageName, b.ChannelName, b.CsvName
+		add("GetBundle/"+pkgName+"/"+channelName+"/"+csvName, func(c api.RegistryClient) rpcResult {
+			return unaryResult(c.GetBundle(ctx, &api.GetBundleRequest{PkgName: pkgName, ChannelName: channelName, CsvName: csvName}))
+		})
+		add("GetBundleForChannel/"+pkgName+"/"+channelName, func(c api.RegistryClient) rpcResult {
+			return unaryResult(c.GetBundleForChannel(ctx, &api.GetBundleInChannelRequest{PkgName: pkgName, ChannelName: channelName}))
+		})
+	}
+	add("GetBundle/missing", func(c api.RegistryClient) rpcResult {
+		return unaryResult(c.GetBundle(ctx, &api.GetBundleRequest{PkgName: "etcd", ChannelName: "alpha", CsvName: "missing"}))
+	})
+	add("GetBundleForChannel/missing", func(c api.RegistryClient) rpcResult {
+		return unaryResult(c.GetBundleForChannel(ctx, &api.GetBundleInChannelRequest{PkgName: "etcd", ChannelName: "missing"}))
+	})
+	for pkgName := range packages {
+		pkgName := pkgName
+		add("GetPackage/"+pkgName, func(c api.RegistryClient) rpcResult {
+			return unaryResult(c.GetPackage(ctx, &api.GetPackageRequest{Name: pkgName}))
+		})
+	}
+	for csvName := range csvNames {
+		csvName := csvName
+		add("GetChannelEntriesThatReplace/"+csvName, func(c api.RegistryClient) rpcResult {
+			stream, err := c.GetChannelEntriesThatReplace(ctx, &api.GetAllReplacementsRequest{CsvName: csvName})
+			return streamResult(func() (proto.Message, error) { return stream.Recv() }, err)
+		})
+	}
+	for r := range replacements {
+		r := r
+		add("GetBundleThatReplaces/"+r.pkgName+"/"+r.channelName+"/"+r.csvName, func(c api.RegistryClient) rpcResult {
+			return unaryResult(c.GetBundleThatReplaces(ctx, &api.GetReplacementRequest{CsvName: r.csvName, PkgName: r.pkgName, ChannelName: r.channelName}))
+		})
+	}
+	for g := range gvks {
+		gvk := g
+		name := gvk.Group + "/" + gvk.Version + "/" + gvk.Kind
+		add("GetChannelEntriesThatProvide/"+name, func(c api.RegistryClient) rpcResult {
+			stream, err := c.GetChannelEntriesThatProvide(ctx, &api.GetAllProvidersRequest{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind})
+			return streamResult(func() (proto.Message, error) { return stream.Recv() }, err)
+		})
+		add("GetLatestChannelEntriesThatProvide/"+name, func(c api.RegistryClient) rpcResult {
+			stream, err := c.GetLatestChannelEntriesThatProvide(ctx, &api.GetLatestProvidersRequest{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind})
+			return streamResult(func() (proto.Message, error) { return stream.Recv() }, err)
+		})
+		add("GetDefaultBundleThatProvides/"+name, func(c api.RegistryClient) rpcResult {
+			return unaryResult(c.GetDefaultBundleThatProvides(ctx, &api.GetDefaultProviderRequest{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind}))
+		})
+	}
+
+	// Every RPC must be covered.
+	covered := map[string]struct{}{}
+	for _, c := range calls {
+		covered[c.name[:len(c.name)-len(trimMethod(c.name))]] = struct{}{}
+	}
+	methods := api.File_registry_proto.Services().ByName("Registry").Methods()
+	for i := 0; i < methods.Len(); i++ {
+		require.Contains(t, covered, string(methods.Get(i).Name()))
+	}
+
+	for _, c := range calls {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			expected, actual := c.do(sqlClient), c.do(memoryClient)
+			require.Equal(t, expected.code, actual.code)
+			require.Equal(t, expected.normalize(t), actual.normalize(t))
+		})
+	}
+}
+
+// trimMethod returns the part of a call name that follows its method name.
+func trimMethod(name string) string {
+	for i, r := range name {
+		if r == '/' {
+			return name[i:]
+		}
+	}
+	return ""
+}
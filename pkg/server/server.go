@@ -1,7 +1,12 @@
 package server
 
 import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/operator-framework/operator-registry/pkg/api"
 	"github.com/operator-framework/operator-registry/pkg/registry"
@@ -9,17 +14,124 @@ import (
 
 type RegistryServer struct {
 	api.UnimplementedRegistryServer
-	store registry.GRPCQuery
+	watcher *ModelWatcher
+	logger  logrus.FieldLogger
+
+	cacheResponses bool
+
+	mu          sync.RWMutex
+	store       registry.GRPCQuery
+	catalogInfo *api.CatalogInfo
+	responses   *cachedResponses
+	reloadHooks []func(registry.GRPCQuery)
 }
 
 var _ api.RegistryServer = &RegistryServer{}
 
-func NewRegistryServer(store registry.GRPCQuery) *RegistryServer {
-	return &RegistryServer{UnimplementedRegistryServer: api.UnimplementedRegistryServer{}, store: store}
+type RegistryServerOption func(*RegistryServer)
+
+// WithCatalogInfo sets the metadata returned by GetCatalogInfo.
+func WithCatalogInfo(info *api.CatalogInfo) RegistryServerOption {
+	return func(s *RegistryServer) {
+		s.catalogInfo = info
+	}
+}
+
+// WithModelWatcher enables the Watch RPC, streaming the changes recorded by w.
+func WithModelWatcher(w *ModelWatcher) RegistryServerOption {
+	return func(s *RegistryServer) {
+		s.watcher = w
+	}
+}
+
+// WithLogger sets the logger for problems the server recovers from, such as responses
+// that can't be precomputed.
+func WithLogger(logger logrus.FieldLogger) RegistryServerOption {
+	return func(s *RegistryServer) {
+		s.logger = logger
+	}
+}
+
+// WithResponseCache precomputes the responses to ListPackages, ListBundles and GetPackage
+// whenever a store is loaded, instead of querying the store on every request. The streamed
+// responses are also encoded ahead of time, so gRPC clients are sent them without encoding
+// them again. Stores must not change while they are served; Reload replaces the cached
+// responses.
+func WithResponseCache() RegistryServerOption {
+	return func(s *RegistryServer) {
+		s.cacheResponses = true
+	}
+}
+
+func NewRegistryServer(store registry.GRPCQuery, opts ...RegistryServerOption) *RegistryServer {
+	s := &RegistryServer{UnimplementedRegistryServer: api.UnimplementedRegistryServer{}, store: store, logger: logrus.StandardLogger()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.responses = s.precompute(store)
+	return s
+}
+
+// Reload replaces the store queried and the catalog info returned by the server.
+func (s *RegistryServer) Reload(store registry.GRPCQuery, info *api.CatalogInfo) {
+	responses := s.precompute(store)
+	s.mu.RLock()
+	hooks := s.reloadHooks
+	s.mu.RUnlock()
+	for _, hook := range hooks {
+		hook(store)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store = store
+	s.catalogInfo = info
+	s.responses = responses
+}
+
+// precompute returns the cached responses for store, or nil if responses aren't cached.
+// Requests are answered from the store if the responses can't be computed.
+func (s *RegistryServer) precompute(store registry.GRPCQuery) *cachedResponses {
+	if !s.cacheResponses || store == nil {
+		return nil
+	}
+	responses, err := precomputeResponses(context.Background(), store)
+	if err != nil {
+		s.logger.WithError(err).Warn("unable to precompute responses, answering requests from the store")
+		return nil
+	}
+	return responses
+}
+
+// addReloadHook calls hook with the new store on every Reload, before the store is served.
+func (s *RegistryServer) addReloadHook(hook func(registry.GRPCQuery)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reloadHooks = append(s.reloadHooks, hook)
+}
+
+func (s *RegistryServer) getStore() registry.GRPCQuery {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.store
+}
+
+func (s *RegistryServer) getResponses() *cachedResponses {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.responses
 }
 
 func (s *RegistryServer) ListPackages(req *api.ListPackageRequest, stream api.Registry_ListPackagesServer) error {
-	packageNames, err := s.store.ListPackages(stream.Context())
+	if r := s.getResponses(); r != nil {
+		for _, p := range r.packageNames {
+			if err := stream.SendMsg(p); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	packageNames, err := s.getStore().ListPackages(stream.Context())
 	if err != nil {
 		return err
 	}
@@ -33,7 +145,16 @@ func (s *RegistryServer) ListPackages(req *api.ListPackageRequest, stream api.Re
 }
 
 func (s *RegistryServer) ListBundles(req *api.ListBundlesRequest, stream api.Registry_ListBundlesServer) error {
-	bundles, err := s.store.ListBundles(stream.Context())
+	if r := s.getResponses(); r != nil {
+		for _, b := range r.bundles {
+			if err := stream.SendMsg(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	bundles, err := s.getStore().ListBundles(stream.Context())
 	if err != nil {
 		return err
 	}
@@ -47,7 +168,12 @@ func (s *RegistryServer) ListBundles(req *api.ListBundlesRequest, stream api.Reg
 }
 
 func (s *RegistryServer) GetPackage(ctx context.Context, req *api.GetPackageRequest) (*api.Package, error) {
-	packageManifest, err := s.store.GetPackage(ctx, req.GetName())
+	if r := s.getResponses(); r != nil {
+		if p, ok := r.packages[req.GetName()]; ok {
+			return p, nil
+		}
+	}
+	packageManifest, err := s.getStore().GetPackage(ctx, req.GetName())
 	if err != nil {
 		return nil, err
 	}
@@ -55,15 +181,15 @@ func (s *RegistryServer) GetPackage(ctx context.Context, req *api.GetPackageRequ
 }
 
 func (s *RegistryServer) GetBundle(ctx context.Context, req *api.GetBundleRequest) (*api.Bundle, error) {
-	return s.store.GetBundle(ctx, req.GetPkgName(), req.GetChannelName(), req.GetCsvName())
+	return s.getStore().GetBundle(ctx, req.GetPkgName(), req.GetChannelName(), req.GetCsvName())
 }
 
 func (s *RegistryServer) GetBundleForChannel(ctx context.Context, req *api.GetBundleInChannelRequest) (*api.Bundle, error) {
-	return s.store.GetBundleForChannel(ctx, req.GetPkgName(), req.GetChannelName())
+	return s.getStore().GetBundleForChannel(ctx, req.GetPkgName(), req.GetChannelName())
 }
 
 func (s *RegistryServer) GetChannelEntriesThatReplace(req *api.GetAllReplacementsRequest, stream api.Registry_GetChannelEntriesThatReplaceServer) error {
-	channelEntries, err := s.store.GetChannelEntriesThatReplace(stream.Context(), req.GetCsvName())
+	channelEntries, err := s.getStore().GetChannelEntriesThatReplace(stream.Context(), req.GetCsvName())
 	if err != nil {
 		return err
 	}
@@ -76,11 +202,11 @@ func (s *RegistryServer) GetChannelEntriesThatReplace(req *api.GetAllReplacement
 }
 
 func (s *RegistryServer) GetBundleThatReplaces(ctx context.Context, req *api.GetReplacementRequest) (*api.Bundle, error) {
-	return s.store.GetBundleThatReplaces(ctx, req.GetCsvName(), req.GetPkgName(), req.GetChannelName())
+	return s.getStore().GetBundleThatReplaces(ctx, req.GetCsvName(), req.GetPkgName(), req.GetChannelName())
 }
 
 func (s *RegistryServer) GetChannelEntriesThatProvide(req *api.GetAllProvidersRequest, stream api.Registry_GetChannelEntriesThatProvideServer) error {
-	channelEntries, err := s.store.GetChannelEntriesThatProvide(stream.Context(), req.GetGroup(), req.GetVersion(), req.GetKind())
+	channelEntries, err := s.getStore().GetChannelEntriesThatProvide(stream.Context(), req.GetGroup(), req.GetVersion(), req.GetKind())
 	if err != nil {
 		return err
 	}
@@ -93,7 +219,7 @@ func (s *RegistryServer) GetChannelEntriesThatProvide(req *api.GetAllProvidersRe
 }
 
 func (s *RegistryServer) GetLatestChannelEntriesThatProvide(req *api.GetLatestProvidersRequest, stream api.Registry_GetLatestChannelEntriesThatProvideServer) error {
-	channelEntries, err := s.store.GetLatestChannelEntriesThatProvide(stream.Context(), req.GetGroup(), req.GetVersion(), req.GetKind())
+	channelEntries, err := s.getStore().GetLatestChannelEntriesThatProvide(stream.Context(), req.GetGroup(), req.GetVersion(), req.GetKind())
 	if err != nil {
 		return err
 	}
@@ -106,5 +232,21 @@ func (s *RegistryServer) GetLatestChannelEntriesThatProvide(req *api.GetLatestPr
 }
 
 func (s *RegistryServer) GetDefaultBundleThatProvides(ctx context.Context, req *api.GetDefaultProviderRequest) (*api.Bundle, error) {
-	return s.store.GetBundleThatProvides(ctx, req.GetGroup(), req.GetVersion(), req.GetKind())
+	return s.getStore().GetBundleThatProvides(ctx, req.GetGroup(), req.GetVersion(), req.GetKind())
+}
+
+func (s *RegistryServer) GetCatalogInfo(ctx context.Context, req *api.GetCatalogInfoRequest) (*api.CatalogInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.catalogInfo == nil {
+		return nil, status.Error(codes.Unavailable, "catalog info not available")
+	}
+	return s.catalogInfo, nil
+}
+
+func (s *RegistryServer) Watch(req *api.WatchRequest, stream api.Registry_WatchServer) error {
+	if s.watcher == nil {
+		return status.Error(codes.Unimplemented, "watch is only served for catalogs that are reloaded")
+	}
+	return s.watcher.Watch(stream.Context(), req.GetResumeToken(), stream.Send)
 }
@@ -18,6 +18,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
 
+	"github.com/operator-framework/operator-registry/internal/model"
 	"github.com/operator-framework/operator-registry/pkg/api"
 	"github.com/operator-framework/operator-registry/pkg/registry"
 	"github.com/operator-framework/operator-registry/pkg/sqlite"
@@ -32,6 +33,8 @@ const (
 	cfgAddress = "localhost" + cfgPort
 )
 
+var loadTime = time.Date(2021, time.May, 1, 12, 0, 0, 0, time.UTC)
+
 func dbStore(dbPath string) *sqlite.SQLQuerier {
 	_ = os.Remove(dbPath)
 
@@ -61,7 +64,7 @@ func dbStore(dbPath string) *sqlite.SQLQuerier {
 	return store
 }
 
-func cfgStore() *registry.Querier {
+func cfgModel() model.Model {
 	tmpDir, err := ioutil.TempDir("", "server_test-")
 	if err != nil {
 		logrus.Fatal(err)
@@ -75,34 +78,41 @@ func cfgStore() *registry.Querier {
 	if err != nil {
 		logrus.Fatal(err)
 	}
-	store := registry.NewQuerier(m)
-	return store
+	return m
 }
 
-func server(store registry.GRPCQuery) *grpc.Server {
+func server(store registry.GRPCQuery, opts ...RegistryServerOption) *grpc.Server {
 	s := grpc.NewServer()
-	api.RegisterRegistryServer(s, NewRegistryServer(store))
+	api.RegisterRegistryServer(s, NewRegistryServer(store, opts...))
 	return s
 }
 
 func TestMain(m *testing.M) {
-	s1 := server(dbStore(dbName))
-	s2 := server(cfgStore())
+	db := dbStore(dbName)
+	dbInfo, err := NewSQLCatalogInfo(context.TODO(), SourceTypeSqlite, "sha256:db", loadTime, db)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	cfg := cfgModel()
+	cfgInfo := NewModelCatalogInfo(SourceTypeDeclcfg, "sha256:cfg", loadTime, cfg)
+
+	s1 := server(db, WithCatalogInfo(dbInfo))
+	s2 := server(registry.NewQuerier(cfg), WithCatalogInfo(cfgInfo))
+	lis1, err := net.Listen("tcp", dbPort)
+	if err != nil {
+		logrus.Fatalf("failed to listen: %v", err)
+	}
+	lis2, err := net.Listen("tcp", cfgPort)
+	if err != nil {
+		logrus.Fatalf("failed to listen: %v", err)
+	}
 	go func() {
-		lis, err := net.Listen("tcp", dbPort)
-		if err != nil {
-			logrus.Fatalf("failed to listen: %v", err)
-		}
-		if err := s1.Serve(lis); err != nil {
+		if err := s1.Serve(lis1); err != nil {
 			logrus.Fatalf("failed to serve db: %v", err)
 		}
 	}()
 	go func() {
-		lis, err := net.Listen("tcp", cfgPort)
-		if err != nil {
-			logrus.Fatalf("failed to listen: %v", err)
-		}
-		if err := s2.Serve(lis); err != nil {
+		if err := s2.Serve(lis2); err != nil {
 			logrus.Fatalf("failed to serve configs: %v", err)
 		}
 	}()
@@ -549,6 +559,41 @@ func testGetDefaultBundleThatProvides(addr string, expected *api.Bundle) func(*t
 	}
 }
 
+func TestGetCatalogInfo(t *testing.T) {
+	t.Run("Sqlite", testGetCatalogInfo(dbAddress, &api.CatalogInfo{
+		SourceType:    SourceTypeSqlite,
+		SchemaVersion: "12",
+		Digest:        "sha256:db",
+		LoadTime:      "2021-05-01T12:00:00Z",
+		PackageCount:  3,
+		ChannelCount:  7,
+		BundleCount:   10,
+	}))
+	t.Run("DeclarativeConfig", testGetCatalogInfo(cfgAddress, &api.CatalogInfo{
+		SourceType:    SourceTypeDeclcfg,
+		SchemaVersion: "olm.package,olm.bundle",
+		Digest:        "sha256:cfg",
+		LoadTime:      "2021-05-01T12:00:00Z",
+		PackageCount:  3,
+		ChannelCount:  7,
+		BundleCount:   10,
+	}))
+}
+
+func testGetCatalogInfo(addr string, expected *api.CatalogInfo) func(*testing.T) {
+	return func(t *testing.T) {
+		c, conn := client(t, addr)
+		defer conn.Close()
+
+		info, err := c.GetCatalogInfo(context.TODO(), &api.GetCatalogInfoRequest{})
+		require.NoError(t, err)
+		opts := []cmp.Option{
+			cmpopts.IgnoreUnexported(api.CatalogInfo{}),
+		}
+		require.True(t, cmp.Equal(expected, info, opts...), cmp.Diff(expected, info, opts...))
+	}
+}
+
 func TestListBundles(t *testing.T) {
 	t.Run("Sqlite", testListBundles(dbAddress,
 		etcdoperator_v0_9_2("alpha", true, false),
@@ -0,0 +1,201 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/operator-framework/operator-registry/internal/model"
+	"github.com/operator-framework/operator-registry/pkg/api"
+	v2 "github.com/operator-framework/operator-registry/pkg/api/v2"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+// RegistryV2Server serves the registry.v2 API from the store of a RegistryServer, so both
+// APIs answer from the same contents, including after the RegistryServer is reloaded.
+// Stores must implement registry.ModelQuery.
+type RegistryV2Server struct {
+	v2.UnimplementedRegistryServer
+
+	mu    sync.RWMutex
+	index *v2Index
+	err   error
+}
+
+var _ v2.RegistryServer = &RegistryV2Server{}
+
+// NewRegistryV2Server returns a server for the store of s, indexing it immediately and
+// again whenever s is reloaded.
+func NewRegistryV2Server(s *RegistryServer) *RegistryV2Server {
+	v := &RegistryV2Server{}
+	v.load(s.getStore())
+	s.addReloadHook(v.load)
+	return v
+}
+
+// v2Index is the model of a store, with the copies of each bundle in the channels it is a
+// member of grouped together.
+type v2Index struct {
+	model   model.Model
+	bundles map[string]map[string][]*model.Bundle
+}
+
+func newV2Index(ctx context.Context, store registry.GRPCQuery) (*v2Index, error) {
+	mq, ok := store.(registry.ModelQuery)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "the registry.v2 API is not supported by %T stores", store)
+	}
+	m, err := mq.Model(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get index model: %v", err)
+	}
+	index := &v2Index{model: m, bundles: map[string]map[string][]*model.Bundle{}}
+	for _, pkg := range m {
+		bundles := map[string][]*model.Bundle{}
+		for _, ch := range pkg.Channels {
+			for _, b := range ch.Bundles {
+				bundles[b.Name] = append(bundles[b.Name], b)
+			}
+		}
+		index.bundles[pkg.Name] = bundles
+	}
+	return index, nil
+}
+
+// load indexes store, which requests are answered from until the next load. Requests
+// fail with the error if store can't be indexed.
+func (s *RegistryV2Server) load(store registry.GRPCQuery) {
+	index, err := newV2Index(context.Background(), store)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index, s.err = index, err
+}
+
+// getIndex returns the index of the registry server's current store.
+func (s *RegistryV2Server) getIndex() (*v2Index, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index, s.err
+}
+
+func (s *RegistryV2Server) ListPackages(req *v2.ListPackagesRequest, stream v2.Registry_ListPackagesServer) error {
+	index, err := s.getIndex()
+	if err != nil {
+		return err
+	}
+	for _, name := range index.packageNames() {
+		pkg, err := api.ConvertModelPackageToV2Package(*index.model[name])
+		if err != nil {
+			return fmt.Errorf("convert package %q: %v", name, err)
+		}
+		if err := stream.Send(pkg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *RegistryV2Server) GetPackage(ctx context.Context, req *v2.GetPackageRequest) (*v2.Package, error) {
+	index, err := s.getIndex()
+	if err != nil {
+		return nil, err
+	}
+	pkg, ok := index.model[req.GetName()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "package %q not found", req.GetName())
+	}
+	out, err := api.ConvertModelPackageToV2Package(*pkg)
+	if err != nil {
+		return nil, fmt.Errorf("convert package %q: %v", pkg.Name, err)
+	}
+	return out, nil
+}
+
+func (s *RegistryV2Server) GetChannel(ctx context.Context, req *v2.GetChannelRequest) (*v2.Channel, error) {
+	index, err := s.getIndex()
+	if err != nil {
+		return nil, err
+	}
+	pkg, ok := index.model[req.GetPackage()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "package %q not found", req.GetPackage())
+	}
+	ch, ok := pkg.Channels[req.GetName()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "package %q has no channel %q", req.GetPackage(), req.GetName())
+	}
+	out, err := api.ConvertModelChannelToV2Channel(*ch)
+	if err != nil {
+		return nil, fmt.Errorf("convert package %q channel %q: %v", pkg.Name, ch.Name, err)
+	}
+	return out, nil
+}
+
+func (s *RegistryV2Server) ListBundles(req *v2.ListBundlesRequest, stream v2.Registry_ListBundlesServer) error {
+	index, err := s.getIndex()
+	if err != nil {
+		return err
+	}
+	pkgNames := index.packageNames()
+	if req.GetPackage() != "" {
+		if _, ok := index.bundles[req.GetPackage()]; !ok {
+			return status.Errorf(codes.NotFound, "package %q not found", req.GetPackage())
+		}
+		pkgNames = []string{req.GetPackage()}
+	}
+	for _, pkgName := range pkgNames {
+		bundles := index.bundles[pkgName]
+		for _, name := range bundleNames(bundles) {
+			b, err := api.ConvertModelBundlesToV2Bundle(bundles[name])
+			if err != nil {
+				return fmt.Errorf("convert bundle %q: %v", name, err)
+			}
+			if err := stream.Send(b); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *RegistryV2Server) GetBundle(ctx context.Context, req *v2.GetBundleRequest) (*v2.Bundle, error) {
+	index, err := s.getIndex()
+	if err != nil {
+		return nil, err
+	}
+	bundles, ok := index.bundles[req.GetPackage()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "package %q not found", req.GetPackage())
+	}
+	copies, ok := bundles[req.GetName()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "package %q has no bundle %q", req.GetPackage(), req.GetName())
+	}
+	b, err := api.ConvertModelBundlesToV2Bundle(copies)
+	if err != nil {
+		return nil, fmt.Errorf("convert bundle %q: %v", req.GetName(), err)
+	}
+	return b, nil
+}
+
+func (i *v2Index) packageNames() []string {
+	names := make([]string, 0, len(i.bundles))
+	for name := range i.bundles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func bundleNames(bundles map[string][]*model.Bundle) []string {
+	names := make([]string, 0, len(bundles))
+	for name := range bundles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
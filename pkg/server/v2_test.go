@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/operator-framework/operator-registry/internal/model"
+	"github.com/operator-framework/operator-registry/internal/property"
+	"github.com/operator-framework/operator-registry/pkg/api"
+	v2 "github.com/operator-framework/operator-registry/pkg/api/v2"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+	"github.com/operator-framework/operator-registry/pkg/sqlite"
+)
+
+func serveV2(t *testing.T, s *RegistryServer) v2.RegistryClient {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	api.RegisterRegistryServer(grpcServer, s)
+	v2.RegisterRegistryServer(grpcServer, NewRegistryV2Server(s))
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return v2.NewRegistryClient(conn)
+}
+
+func listV2Bundles(t *testing.T, c v2.RegistryClient, pkg string) []*v2.Bundle {
+	stream, err := c.ListBundles(context.Background(), &v2.ListBundlesRequest{Package: pkg})
+	require.NoError(t, err)
+	var bundles []*v2.Bundle
+	for {
+		b, err := stream.Recv()
+		if err == io.EOF {
+			return bundles
+		}
+		require.NoError(t, err)
+		bundles = append(bundles, b)
+	}
+}
+
+func TestRegistryV2Server(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := dbStore(filepath.Join(t.TempDir(), "v2.db"))
+	m, err := sqlite.ToModel(ctx, sqlStore)
+	require.NoError(t, err)
+
+	for name, store := range map[string]registry.GRPCQuery{
+		"SQL":    sqlStore,
+		"Memory": registry.NewQuerier(m),
+	} {
+		store := store
+		t.Run(name, func(t *testing.T) {
+			c := serveV2(t, NewRegistryServer(store))
+
+			pkg, err := c.GetPackage(ctx, &v2.GetPackageRequest{Name: "etcd"})
+			require.NoError(t, err)
+			require.Equal(t, "alpha", pkg.GetDefaultChannel())
+			var heads []string
+			for _, ch := range pkg.GetChannels() {
+				heads = append(heads, ch.GetName()+"="+ch.GetHead())
+			}
+			require.Equal(t, []string{"alpha=etcdoperator.v0.9.2", "beta=etcdoperator.v0.9.0", "stable=etcdoperator.v0.9.2"}, heads)
+
+			ch, err := c.GetChannel(ctx, &v2.GetChannelRequest{Package: "etcd", Name: "beta"})
+			require.NoError(t, err)
+			require.Equal(t, "etcdoperator.v0.9.0", ch.GetHead())
+			require.Len(t, ch.GetEntries(), 2)
+			require.Equal(t, "etcdoperator.v0.6.1", ch.GetEntries()[1].GetReplaces())
+
+			b, err := c.GetBundle(ctx, &v2.GetBundleRequest{Package: "etcd", Name: "etcdoperator.v0.9.2"})
+			require.NoError(t, err)
+			require.Equal(t, "0.9.2", b.GetVersion())
+			require.False(t, b.GetDeprecated())
+			var channels []string
+			for _, entry := range b.GetChannels() {
+				require.Equal(t, "etcdoperator.v0.9.0", entry.GetReplaces())
+				require.Equal(t, "< 0.6.0", entry.GetSkipRange())
+				channels = append(channels, entry.GetChannelName())
+			}
+			require.Equal(t, []string{"alpha", "stable"}, channels)
+			var gvks []string
+			for _, p := range b.GetProperties() {
+				require.NotContains(t, []string{"olm.channel", "olm.skips", "olm.skipRange", "olm.bundle.object"}, p.GetType())
+				if gvk := p.GetGvk(); gvk != nil {
+					gvks = append(gvks, gvk.GetKind())
+				}
+			}
+			require.ElementsMatch(t, []string{"EtcdBackup", "EtcdCluster", "EtcdRestore"}, gvks)
+			require.NotEmpty(t, b.GetObjects())
+
+			// Bundles are listed once, no matter how many channels they're in.
+			bundles := listV2Bundles(t, c, "etcd")
+			var names []string
+			for _, b := range bundles {
+				names = append(names, b.GetName())
+			}
+			require.Equal(t, []string{"etcdoperator.v0.6.1", "etcdoperator.v0.9.0", "etcdoperator.v0.9.2"}, names)
+			require.Greater(t, len(listV2Bundles(t, c, "")), len(bundles))
+
+			_, err = c.GetPackage(ctx, &v2.GetPackageRequest{Name: "missing"})
+			require.Equal(t, codes.NotFound, status.Code(err))
+			_, err = c.GetChannel(ctx, &v2.GetChannelRequest{Package: "etcd", Name: "missing"})
+			require.Equal(t, codes.NotFound, status.Code(err))
+			_, err = c.GetBundle(ctx, &v2.GetBundleRequest{Package: "etcd", Name: "missing"})
+			require.Equal(t, codes.NotFound, status.Code(err))
+			stream, err := c.ListBundles(ctx, &v2.ListBundlesRequest{Package: "missing"})
+			require.NoError(t, err)
+			_, err = stream.Recv()
+			require.Equal(t, codes.NotFound, status.Code(err))
+		})
+	}
+}
+
+func TestRegistryV2ServerReload(t *testing.T) {
+	ctx := context.Background()
+	m := model.Model{}
+	s := NewRegistryServer(registry.NewQuerier(m))
+	c := serveV2(t, s)
+	require.Empty(t, listV2Bundles(t, c, ""))
+
+	// The v2 API serves the reloaded store, like v1 does.
+	reloaded := cfgModel()
+	deprecated := reloaded["etcd"].Channels["beta"].Bundles["etcdoperator.v0.6.1"]
+	deprecated.Properties = append(deprecated.Properties, property.Property{Type: registry.DeprecatedType, Value: json.RawMessage(`{}`)})
+	s.Reload(registry.NewQuerier(reloaded), nil)
+
+	b, err := c.GetBundle(ctx, &v2.GetBundleRequest{Package: "etcd", Name: "etcdoperator.v0.6.1"})
+	require.NoError(t, err)
+	require.True(t, b.GetDeprecated())
+	b, err = c.GetBundle(ctx, &v2.GetBundleRequest{Package: "etcd", Name: "etcdoperator.v0.9.0"})
+	require.NoError(t, err)
+	require.False(t, b.GetDeprecated())
+}
+
+func TestRegistryV2ServerUnsupportedStore(t *testing.T) {
+	// Stores that can't return a model don't support the v2 API.
+	store := struct{ registry.GRPCQuery }{registry.NewQuerier(model.Model{})}
+	c := serveV2(t, NewRegistryServer(store))
+	_, err := c.GetPackage(context.Background(), &v2.GetPackageRequest{Name: "etcd"})
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+// countingModelStore counts the models built from a store.
+type countingModelStore struct {
+	*registry.Querier
+	models int
+}
+
+func (s *countingModelStore) Model(ctx context.Context) (model.Model, error) {
+	s.models++
+	return s.Querier.Model(ctx)
+}
+
+func TestRegistryV2ServerIndexesOnLoad(t *testing.T) {
+	// Stores are indexed when the server is created and reloaded, rather than by requests.
+	store := &countingModelStore{Querier: registry.NewQuerier(cfgModel())}
+	s := NewRegistryServer(store)
+	c := serveV2(t, s)
+	require.Equal(t, 1, store.models)
+	require.NotEmpty(t, listV2Bundles(t, c, "etcd"))
+	require.Equal(t, 1, store.models)
+
+	reloaded := &countingModelStore{Querier: registry.NewQuerier(cfgModel())}
+	s.Reload(reloaded, nil)
+	require.Equal(t, 1, reloaded.models)
+	require.NotEmpty(t, listV2Bundles(t, c, "etcd"))
+	require.Equal(t, 1, reloaded.models)
+}
@@ -0,0 +1,368 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/operator-framework/operator-registry/internal/model"
+	"github.com/operator-framework/operator-registry/pkg/api"
+)
+
+const defaultWatchHistorySize = 1024
+
+// ModelWatcher computes package, channel and bundle level change events by diffing
+// successive snapshots of an index model, and streams them to watchers.
+//
+// Every event is assigned a resume token. A watcher that passes the token of the last
+// event it received to Watch receives all events that followed it, provided they are
+// still retained in the watcher's history.
+type ModelWatcher struct {
+	mu sync.Mutex
+
+	current snapshot
+
+	// history holds the most recent events; the first has sequence number first.
+	history    []*api.WatchEvent
+	first      uint64
+	sequence   uint64
+	maxHistory int
+
+	// updated is closed and replaced whenever new events are recorded.
+	updated chan struct{}
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewModelWatcher returns a ModelWatcher whose initial snapshot is m.
+func NewModelWatcher(m model.Model) (*ModelWatcher, error) {
+	s, err := newSnapshot(m)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelWatcher{
+		current:    *s,
+		first:      1,
+		maxHistory: defaultWatchHistorySize,
+		updated:    make(chan struct{}),
+		done:       make(chan struct{}),
+	}, nil
+}
+
+// Close ends all in-progress and future calls to Watch. It must be called before
+// gracefully stopping a server with active watchers, which would otherwise never return.
+func (w *ModelWatcher) Close() {
+	w.closeOnce.Do(func() {
+		close(w.done)
+	})
+}
+
+// Update replaces the current snapshot with m and records the changes between them.
+func (w *ModelWatcher) Update(m model.Model) error {
+	next, err := newSnapshot(m)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	events, err := w.current.diff(*next)
+	if err != nil {
+		return err
+	}
+	w.current = *next
+	if len(events) == 0 {
+		return nil
+	}
+	for _, e := range events {
+		w.sequence++
+		e.ResumeToken = strconv.FormatUint(w.sequence, 10)
+	}
+	w.history = append(w.history, events...)
+	if extra := len(w.history) - w.maxHistory; extra > 0 {
+		w.history = append([]*api.WatchEvent(nil), w.history[extra:]...)
+		w.first += uint64(extra)
+	}
+	close(w.updated)
+	w.updated = make(chan struct{})
+	return nil
+}
+
+// Watch sends change events to send until ctx is done or send fails.
+//
+// Without a resume token, Watch first sends an ADDED event for every package, channel and
+// bundle in the current snapshot, all carrying the token of the latest recorded change.
+// With a resume token, Watch sends the events recorded after the one that carried it.
+func (w *ModelWatcher) Watch(ctx context.Context, resumeToken string, send func(*api.WatchEvent) error) error {
+	w.mu.Lock()
+	var (
+		pending []*api.WatchEvent
+		err     error
+		current = w.current
+		last    = w.sequence
+	)
+	if resumeToken != "" {
+		last, err = w.parseResumeToken(resumeToken)
+		if err == nil {
+			pending, last, err = w.since(last)
+		}
+	}
+	updated := w.updated
+	w.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if resumeToken == "" {
+		// Snapshots are immutable, so the listing can be built without holding the lock.
+		if pending, err = current.list(strconv.FormatUint(last, 10)); err != nil {
+			return err
+		}
+	}
+
+	for {
+		for _, e := range pending {
+			if err := send(e); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.done:
+			return nil
+		case <-updated:
+		}
+
+		w.mu.Lock()
+		pending, last, err = w.since(last)
+		updated = w.updated
+		w.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (w *ModelWatcher) parseResumeToken(token string) (uint64, error) {
+	seq, err := strconv.ParseUint(token, 10, 64)
+	if err != nil || seq > w.sequence {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid resume token %q", token)
+	}
+	return seq, nil
+}
+
+// since returns the events recorded after sequence number last, along with the sequence
+// number of the latest recorded event. The caller must hold w.mu.
+func (w *ModelWatcher) since(last uint64) ([]*api.WatchEvent, uint64, error) {
+	if last+1 < w.first {
+		return nil, 0, status.Errorf(codes.OutOfRange, "changes after resume token %q are no longer available, restart the watch without a resume token", strconv.FormatUint(last, 10))
+	}
+	return w.history[last+1-w.first:], w.sequence, nil
+}
+
+type channelKey struct {
+	pkg, channel string
+}
+
+type bundleKey struct {
+	pkg, channel, bundle string
+}
+
+// snapshot is the comparable representation of an index model. Bundles are compared
+// by the digest of their API representation to avoid holding a copy of every bundle.
+type snapshot struct {
+	model    model.Model
+	packages map[string]*api.Package
+	channels map[channelKey]*api.Channel
+	bundles  map[bundleKey][sha256.Size]byte
+}
+
+func newSnapshot(m model.Model) (*snapshot, error) {
+	s := &snapshot{
+		model:    m,
+		packages: map[string]*api.Package{},
+		channels: map[channelKey]*api.Channel{},
+		bundles:  map[bundleKey][sha256.Size]byte{},
+	}
+	for _, pkg := range m {
+		apiPkg := &api.Package{Name: pkg.Name}
+		if pkg.DefaultChannel != nil {
+			apiPkg.DefaultChannelName = pkg.DefaultChannel.Name
+		}
+		for _, ch := range pkg.Channels {
+			head, err := ch.Head()
+			if err != nil {
+				return nil, fmt.Errorf("package %q, channel %q has invalid head: %v", pkg.Name, ch.Name, err)
+			}
+			apiCh := &api.Channel{Name: ch.Name, CsvName: head.Name}
+			apiPkg.Channels = append(apiPkg.Channels, apiCh)
+			s.channels[channelKey{pkg.Name, ch.Name}] = apiCh
+
+			for _, b := range ch.Bundles {
+				apiBundle, err := api.ConvertModelBundleToAPIBundle(*b)
+				if err != nil {
+					return nil, fmt.Errorf("convert bundle %q: %v", b.Name, err)
+				}
+				data, err := proto.MarshalOptions{Deterministic: true}.Marshal(apiBundle)
+				if err != nil {
+					return nil, fmt.Errorf("marshal bundle %q: %v", b.Name, err)
+				}
+				s.bundles[bundleKey{pkg.Name, ch.Name, b.Name}] = sha256.Sum256(data)
+			}
+		}
+		sort.Slice(apiPkg.Channels, func(i, j int) bool {
+			return apiPkg.Channels[i].Name < apiPkg.Channels[j].Name
+		})
+		s.packages[pkg.Name] = apiPkg
+	}
+	return s, nil
+}
+
+func (s snapshot) bundle(k bundleKey) (*api.Bundle, error) {
+	b := s.model[k.pkg].Channels[k.channel].Bundles[k.bundle]
+	apiBundle, err := api.ConvertModelBundleToAPIBundle(*b)
+	if err != nil {
+		return nil, fmt.Errorf("convert bundle %q: %v", b.Name, err)
+	}
+	return apiBundle, nil
+}
+
+// list returns ADDED events for the entire snapshot, parents first.
+func (s snapshot) list(resumeToken string) ([]*api.WatchEvent, error) {
+	var events []*api.WatchEvent
+	for _, name := range sortedPackageKeys(s.packages) {
+		events = append(events, packageEvent(api.WatchEvent_ADDED, s.packages[name]))
+	}
+	for _, k := range sortedChannelKeys(s.channels) {
+		events = append(events, channelEvent(api.WatchEvent_ADDED, k, s.channels[k]))
+	}
+	for _, k := range sortedBundleKeys(s.bundles) {
+		b, err := s.bundle(k)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, bundleEvent(api.WatchEvent_ADDED, k, b))
+	}
+	for _, e := range events {
+		e.ResumeToken = resumeToken
+	}
+	return events, nil
+}
+
+// diff returns the events that transform s into next. Removals are ordered children
+// first, additions parents first, followed by updates.
+func (s snapshot) diff(next snapshot) ([]*api.WatchEvent, error) {
+	var removed, added, updated []*api.WatchEvent
+
+	for _, k := range sortedBundleKeys(s.bundles) {
+		if _, ok := next.bundles[k]; !ok {
+			removed = append(removed, bundleEvent(api.WatchEvent_REMOVED, k, &api.Bundle{CsvName: k.bundle, PackageName: k.pkg, ChannelName: k.channel}))
+		}
+	}
+	for _, k := range sortedChannelKeys(s.channels) {
+		if _, ok := next.channels[k]; !ok {
+			removed = append(removed, channelEvent(api.WatchEvent_REMOVED, k, &api.Channel{Name: k.channel}))
+		}
+	}
+	for _, name := range sortedPackageKeys(s.packages) {
+		if _, ok := next.packages[name]; !ok {
+			removed = append(removed, packageEvent(api.WatchEvent_REMOVED, &api.Package{Name: name}))
+		}
+	}
+
+	for _, name := range sortedPackageKeys(next.packages) {
+		pkg := next.packages[name]
+		if old, ok := s.packages[name]; !ok {
+			added = append(added, packageEvent(api.WatchEvent_ADDED, pkg))
+		} else if !proto.Equal(old, pkg) {
+			updated = append(updated, packageEvent(api.WatchEvent_UPDATED, pkg))
+		}
+	}
+	for _, k := range sortedChannelKeys(next.channels) {
+		ch := next.channels[k]
+		if old, ok := s.channels[k]; !ok {
+			added = append(added, channelEvent(api.WatchEvent_ADDED, k, ch))
+		} else if !proto.Equal(old, ch) {
+			updated = append(updated, channelEvent(api.WatchEvent_UPDATED, k, ch))
+		}
+	}
+	for _, k := range sortedBundleKeys(next.bundles) {
+		old, ok := s.bundles[k]
+		if ok && old == next.bundles[k] {
+			continue
+		}
+		b, err := next.bundle(k)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			added = append(added, bundleEvent(api.WatchEvent_ADDED, k, b))
+		} else {
+			updated = append(updated, bundleEvent(api.WatchEvent_UPDATED, k, b))
+		}
+	}
+
+	return append(append(removed, added...), updated...), nil
+}
+
+func packageEvent(t api.WatchEvent_Type, pkg *api.Package) *api.WatchEvent {
+	return &api.WatchEvent{Type: t, PackageName: pkg.Name, Object: &api.WatchEvent_Package{Package: pkg}}
+}
+
+func channelEvent(t api.WatchEvent_Type, k channelKey, ch *api.Channel) *api.WatchEvent {
+	return &api.WatchEvent{Type: t, PackageName: k.pkg, Object: &api.WatchEvent_Channel{Channel: ch}}
+}
+
+func bundleEvent(t api.WatchEvent_Type, k bundleKey, b *api.Bundle) *api.WatchEvent {
+	return &api.WatchEvent{Type: t, PackageName: k.pkg, Object: &api.WatchEvent_Bundle{Bundle: b}}
+}
+
+func sortedPackageKeys(m map[string]*api.Package) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedChannelKeys(m map[channelKey]*api.Channel) []channelKey {
+	keys := make([]channelKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].pkg != keys[j].pkg {
+			return keys[i].pkg < keys[j].pkg
+		}
+		return keys[i].channel < keys[j].channel
+	})
+	return keys
+}
+
+func sortedBundleKeys(m map[bundleKey][sha256.Size]byte) []bundleKey {
+	keys := make([]bundleKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].pkg != keys[j].pkg {
+			return keys[i].pkg < keys[j].pkg
+		}
+		if keys[i].channel != keys[j].channel {
+			return keys[i].channel < keys[j].channel
+		}
+		return keys[i].bundle < keys[j].bundle
+	})
+	return keys
+}
@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/operator-framework/operator-registry/internal/model"
+	"github.com/operator-framework/operator-registry/internal/property"
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+// watchTestModel returns a model with a single package "foo", whose channels contain
+// bundles for the given versions in upgrade order. The default channel is "stable".
+func watchTestModel(channels map[string][]string) model.Model {
+	pkg := &model.Package{Name: "foo", Channels: map[string]*model.Channel{}}
+	for name, versions := range channels {
+		ch := &model.Channel{Package: pkg, Name: name, Bundles: map[string]*model.Bundle{}}
+		for i, v := range versions {
+			b := &model.Bundle{
+				Package:    pkg,
+				Channel:    ch,
+				Name:       "foo.v" + v,
+				Image:      "quay.io/foo/bundle:v" + v,
+				Properties: []property.Property{property.MustBuildPackage("foo", v)},
+			}
+			if i > 0 {
+				b.Replaces = "foo.v" + versions[i-1]
+			}
+			ch.Bundles[b.Name] = b
+		}
+		pkg.Channels[name] = ch
+	}
+	pkg.DefaultChannel = pkg.Channels["stable"]
+	return model.Model{"foo": pkg}
+}
+
+type watchEvent struct {
+	Type   api.WatchEvent_Type
+	Kind   string
+	Name   string
+	Token  string
+	Head   string
+	Bundle string
+}
+
+func summarize(e *api.WatchEvent) watchEvent {
+	s := watchEvent{Type: e.GetType(), Token: e.GetResumeToken()}
+	switch {
+	case e.GetPackage() != nil:
+		s.Kind, s.Name = "package", e.GetPackage().GetName()
+	case e.GetChannel() != nil:
+		s.Kind, s.Name, s.Head = "channel", e.GetChannel().GetName(), e.GetChannel().GetCsvName()
+	case e.GetBundle() != nil:
+		s.Kind, s.Name, s.Bundle = "bundle", e.GetBundle().GetChannelName(), e.GetBundle().GetCsvName()
+	}
+	return s
+}
+
+// collect watches w until n events have been received.
+func collect(t *testing.T, w *ModelWatcher, resumeToken string, n int) ([]watchEvent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var events []watchEvent
+	done := errors.New("done")
+	err := w.Watch(ctx, resumeToken, func(e *api.WatchEvent) error {
+		events = append(events, summarize(e))
+		if len(events) == n {
+			return done
+		}
+		return nil
+	})
+	if err == done {
+		err = nil
+	}
+	return events, err
+}
+
+func TestModelWatcher(t *testing.T) {
+	w, err := NewModelWatcher(watchTestModel(map[string][]string{
+		"stable": {"0.1.0"},
+		"beta":   {"0.1.0"},
+	}))
+	require.NoError(t, err)
+
+	t.Run("InitialListing", func(t *testing.T) {
+		events, err := collect(t, w, "", 5)
+		require.NoError(t, err)
+		require.Equal(t, []watchEvent{
+			{Type: api.WatchEvent_ADDED, Kind: "package", Name: "foo", Token: "0"},
+			{Type: api.WatchEvent_ADDED, Kind: "channel", Name: "beta", Token: "0", Head: "foo.v0.1.0"},
+			{Type: api.WatchEvent_ADDED, Kind: "channel", Name: "stable", Token: "0", Head: "foo.v0.1.0"},
+			{Type: api.WatchEvent_ADDED, Kind: "bundle", Name: "beta", Token: "0", Bundle: "foo.v0.1.0"},
+			{Type: api.WatchEvent_ADDED, Kind: "bundle", Name: "stable", Token: "0", Bundle: "foo.v0.1.0"},
+		}, events)
+	})
+
+	require.NoError(t, w.Update(watchTestModel(map[string][]string{
+		"stable": {"0.1.0", "0.2.0"},
+	})))
+
+	expectedChanges := []watchEvent{
+		{Type: api.WatchEvent_REMOVED, Kind: "bundle", Name: "beta", Token: "1", Bundle: "foo.v0.1.0"},
+		{Type: api.WatchEvent_REMOVED, Kind: "channel", Name: "beta", Token: "2"},
+		{Type: api.WatchEvent_ADDED, Kind: "bundle", Name: "stable", Token: "3", Bundle: "foo.v0.2.0"},
+		{Type: api.WatchEvent_UPDATED, Kind: "package", Name: "foo", Token: "4"},
+		{Type: api.WatchEvent_UPDATED, Kind: "channel", Name: "stable", Token: "5", Head: "foo.v0.2.0"},
+	}
+
+	t.Run("ResumeFromStart", func(t *testing.T) {
+		events, err := collect(t, w, "0", 5)
+		require.NoError(t, err)
+		require.Equal(t, expectedChanges, events)
+	})
+
+	t.Run("ResumeFromMiddle", func(t *testing.T) {
+		events, err := collect(t, w, "3", 2)
+		require.NoError(t, err)
+		require.Equal(t, expectedChanges[3:], events)
+	})
+
+	t.Run("NoChanges", func(t *testing.T) {
+		require.NoError(t, w.Update(watchTestModel(map[string][]string{
+			"stable": {"0.1.0", "0.2.0"},
+		})))
+		require.Equal(t, uint64(5), w.sequence)
+	})
+
+	t.Run("StreamsLaterChanges", func(t *testing.T) {
+		result := make(chan []watchEvent)
+		go func() {
+			events, err := collect(t, w, "5", 1)
+			if err != nil {
+				t.Error(err)
+			}
+			result <- events
+		}()
+		time.Sleep(50 * time.Millisecond)
+		require.NoError(t, w.Update(watchTestModel(map[string][]string{
+			"stable": {"0.1.0", "0.2.0", "0.3.0"},
+		})))
+		require.Equal(t, []watchEvent{
+			{Type: api.WatchEvent_ADDED, Kind: "bundle", Name: "stable", Token: "6", Bundle: "foo.v0.3.0"},
+		}, <-result)
+	})
+
+	t.Run("InvalidResumeToken", func(t *testing.T) {
+		_, err := collect(t, w, "foo", 1)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = collect(t, w, "100", 1)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("ExpiredResumeToken", func(t *testing.T) {
+		w.mu.Lock()
+		w.maxHistory = 2
+		w.mu.Unlock()
+		require.NoError(t, w.Update(watchTestModel(map[string][]string{
+			"stable": {"0.1.0", "0.2.0", "0.3.0", "0.4.0"},
+		})))
+
+		_, err := collect(t, w, "4", 1)
+		require.Equal(t, codes.OutOfRange, status.Code(err))
+
+		events, err := collect(t, w, "9", 2)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		w.Close()
+		_, err := collect(t, w, "11", 1)
+		require.NoError(t, err)
+
+		// Closing again is a no-op
+		w.Close()
+	})
+}
+
+func TestWatchWithoutWatcher(t *testing.T) {
+	s := NewRegistryServer(registry.NewQuerier(watchTestModel(map[string][]string{"stable": {"0.1.0"}})))
+	err := s.Watch(&api.WatchRequest{}, nil)
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+}
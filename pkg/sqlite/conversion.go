@@ -33,6 +33,11 @@ func ToModel(ctx context.Context, q *SQLQuerier) (model.Model, error) {
 	return pkgs, nil
 }
 
+// Model returns the contents of the database as an index model.
+func (s *SQLQuerier) Model(ctx context.Context) (model.Model, error) {
+	return ToModel(ctx, s)
+}
+
 func initializeModelPackages(ctx context.Context, q *SQLQuerier) (model.Model, error) {
 	pkgNames, err := q.ListPackages(ctx)
 	if err != nil {
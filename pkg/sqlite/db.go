@@ -1,9 +1,14 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"os"
+	"sync/atomic"
 
-	_ "github.com/mattn/go-sqlite3"
+	sqlite3 "github.com/mattn/go-sqlite3"
 )
 
 // Open opens a connection to a sqlite db. It should be used everywhere instead of sql.Open so that foreign keys are
@@ -27,3 +32,113 @@ func EnableForeignKeys(fileName string) string {
 func EnableImmutable(fileName string) string {
 	return "file:" + fileName + "?immutable=true"
 }
+
+// OpenReadOnlyMigrated opens the db at fileName at the latest migration without writing to
+// disk. If the db is already at the latest migration, it is opened immutable. Otherwise it
+// is copied into memory and migrated there, so the whole db is held in memory.
+func OpenReadOnlyMigrated(ctx context.Context, fileName string) (*sql.DB, error) {
+	// Opening a db that doesn't exist would otherwise succeed with an empty one.
+	if _, err := os.Stat(fileName); err != nil {
+		return nil, err
+	}
+	db, err := OpenReadOnly(fileName)
+	if err != nil {
+		return nil, err
+	}
+	needsMigration, err := NeedsMigration(ctx, db)
+	if err != nil || !needsMigration {
+		if err != nil {
+			db.Close()
+		}
+		return db, err
+	}
+	defer db.Close()
+
+	mem, err := copyToMemory(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("copy db into memory: %v", err)
+	}
+	migrator, err := NewSQLLiteMigrator(mem)
+	if err != nil {
+		mem.Close()
+		return nil, err
+	}
+	if err := migrator.Migrate(ctx); err != nil {
+		mem.Close()
+		return nil, fmt.Errorf("migrate db in memory: %v", err)
+	}
+	return mem, nil
+}
+
+var memoryDBs int64
+
+// memoryConnector connects to an in-memory db shared by all of its connections. An
+// in-memory db only lives as long as a connection to it is open, so the connector holds
+// one of its own until it's closed along with the pool.
+type memoryConnector struct {
+	driver    *sqlite3.SQLiteDriver
+	name      string
+	keepAlive driver.Conn
+}
+
+func (c *memoryConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.name)
+}
+
+func (c *memoryConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// Close is called by sql.DB.Close once the pool's connections are closed.
+func (c *memoryConnector) Close() error {
+	return c.keepAlive.Close()
+}
+
+// copyToMemory copies src into a new in-memory db.
+func copyToMemory(ctx context.Context, src *sql.DB) (*sql.DB, error) {
+	c := &memoryConnector{
+		driver: &sqlite3.SQLiteDriver{},
+		name:   fmt.Sprintf("file:registry-%d-%d?mode=memory&cache=shared&_foreign_keys=on", os.Getpid(), atomic.AddInt64(&memoryDBs, 1)),
+	}
+	var err error
+	if c.keepAlive, err = c.driver.Open(c.name); err != nil {
+		return nil, err
+	}
+	dst := sql.OpenDB(c)
+
+	if err := backup(ctx, dst, src); err != nil {
+		dst.Close()
+		return nil, err
+	}
+	return dst, nil
+}
+
+// backup copies the contents of src into dst.
+func backup(ctx context.Context, dst, src *sql.DB) error {
+	dstConn, err := dst.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer dstConn.Close()
+	srcConn, err := src.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close()
+
+	return dstConn.Raw(func(dstDriverConn interface{}) error {
+		return srcConn.Raw(func(srcDriverConn interface{}) error {
+			b, err := dstDriverConn.(*sqlite3.SQLiteConn).Backup("main", srcDriverConn.(*sqlite3.SQLiteConn), "main")
+			if err != nil {
+				return err
+			}
+			for done := false; !done; {
+				if done, err = b.Step(-1); err != nil {
+					b.Close()
+					return err
+				}
+			}
+			return b.Finish()
+		})
+	})
+}
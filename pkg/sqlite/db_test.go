@@ -0,0 +1,107 @@
+package sqlite
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/pkg/sqlite/migrations"
+)
+
+func TestOpenReadOnlyMigrated(t *testing.T) {
+	ctx := context.Background()
+
+	// newDB creates a db with a package, migrated up to and including the given version.
+	newDB := func(t *testing.T, version int) string {
+		dbFile := filepath.Join(t.TempDir(), "index.db")
+		db, err := Open(dbFile)
+		require.NoError(t, err)
+		defer db.Close()
+		m := &SQLLiteMigrator{db: db, migrationsTable: DefaultMigrationsTable, migrations: migrations.All()}
+		require.NoError(t, m.Up(ctx, m.migrations.To(version)))
+		_, err = db.Exec(`INSERT INTO package(name) VALUES ("etcd")`)
+		require.NoError(t, err)
+		return dbFile
+	}
+
+	// requireUnchanged requires that nothing in the db's directory was written to.
+	requireUnchanged := func(t *testing.T, dbFile string, open func()) {
+		stat := func() map[string]os.FileInfo {
+			infos, err := ioutil.ReadDir(filepath.Dir(dbFile))
+			require.NoError(t, err)
+			stats := map[string]os.FileInfo{}
+			for _, info := range infos {
+				stats[info.Name()] = info
+			}
+			return stats
+		}
+		before := stat()
+		open()
+		after := stat()
+		require.Len(t, after, len(before))
+		for name, info := range before {
+			require.Contains(t, after, name)
+			require.Equal(t, info.Size(), after[name].Size())
+			require.Equal(t, info.ModTime(), after[name].ModTime())
+		}
+	}
+
+	requireServable := func(t *testing.T, dbFile string) {
+		requireUnchanged(t, dbFile, func() {
+			db, err := OpenReadOnlyMigrated(ctx, dbFile)
+			require.NoError(t, err)
+			defer db.Close()
+
+			needsMigration, err := NeedsMigration(ctx, db)
+			require.NoError(t, err)
+			require.False(t, needsMigration)
+			pkgs, err := NewSQLLiteQuerierFromDb(db).ListPackages(ctx)
+			require.NoError(t, err)
+			require.Equal(t, []string{"etcd"}, pkgs)
+		})
+	}
+
+	t.Run("Latest", func(t *testing.T) {
+		dbFile := newDB(t, len(migrations.All())-1)
+		requireServable(t, dbFile)
+	})
+
+	t.Run("Outdated", func(t *testing.T) {
+		dbFile := newDB(t, migrations.RelatedImagesMigrationKey)
+		requireServable(t, dbFile)
+
+		// The db on disk is left as it was.
+		db, err := OpenReadOnly(dbFile)
+		require.NoError(t, err)
+		defer db.Close()
+		needsMigration, err := NeedsMigration(ctx, db)
+		require.NoError(t, err)
+		require.True(t, needsMigration)
+	})
+
+	t.Run("OutdatedWithoutIdleConnections", func(t *testing.T) {
+		db, err := OpenReadOnlyMigrated(ctx, newDB(t, migrations.RelatedImagesMigrationKey))
+		require.NoError(t, err)
+		defer db.Close()
+
+		// The migrated db outlives the pool's connections
+		db.SetMaxIdleConns(0)
+		for i := 0; i < 2; i++ {
+			pkgs, err := NewSQLLiteQuerierFromDb(db).ListPackages(ctx)
+			require.NoError(t, err)
+			require.Equal(t, []string{"etcd"}, pkgs)
+		}
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		dbFile := filepath.Join(t.TempDir(), "missing.db")
+		_, err := OpenReadOnlyMigrated(ctx, dbFile)
+		require.Error(t, err)
+		_, err = os.Stat(dbFile)
+		require.True(t, os.IsNotExist(err))
+	})
+}
@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/operator-framework/operator-registry/internal/property"
+	"github.com/operator-framework/operator-registry/pkg/api"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+// modelOnlyProperties are the properties of bundles that index models carry, but that
+// databases record in dedicated tables rather than as properties of their bundles.
+var modelOnlyProperties = map[string]struct{}{
+	property.TypeChannel:     {},
+	property.TypeSkips:       {},
+	property.TypeSkipRange:   {},
+	property.TypeGVKRequired: {},
+}
+
+// MemoryQuerier answers queries from an index model of a database held in memory, with
+// the same responses as querying the database.
+type MemoryQuerier struct {
+	*registry.Querier
+
+	// plurals are the plural names of the apis in the database, which models don't record.
+	plurals map[registry.GVKProperty]string
+}
+
+var _ registry.GRPCQuery = &MemoryQuerier{}
+
+// NewMemoryQuerier loads the contents of the database into memory.
+func NewMemoryQuerier(ctx context.Context, q *SQLQuerier) (*MemoryQuerier, error) {
+	m, err := ToModel(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	plurals, err := q.plurals(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list api plurals: %v", err)
+	}
+	return &MemoryQuerier{Querier: registry.NewQuerier(m), plurals: plurals}, nil
+}
+
+func (q *MemoryQuerier) ListBundles(ctx context.Context) ([]*api.Bundle, error) {
+	bundles, err := q.Querier.ListBundles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, b := range bundles {
+		bundles[i], _ = q.convert(b, nil)
+	}
+	return bundles, nil
+}
+
+func (q *MemoryQuerier) GetBundle(ctx context.Context, pkgName, channelName, csvName string) (*api.Bundle, error) {
+	return q.convert(q.Querier.GetBundle(ctx, pkgName, channelName, csvName))
+}
+
+func (q *MemoryQuerier) GetBundleForChannel(ctx context.Context, pkgName string, channelName string) (*api.Bundle, error) {
+	return q.convert(q.Querier.GetBundleForChannel(ctx, pkgName, channelName))
+}
+
+func (q *MemoryQuerier) GetBundleThatReplaces(ctx context.Context, name, pkgName, channelName string) (*api.Bundle, error) {
+	return q.convert(q.Querier.GetBundleThatReplaces(ctx, name, pkgName, channelName))
+}
+
+func (q *MemoryQuerier) GetBundleThatProvides(ctx context.Context, group, version, kind string) (*api.Bundle, error) {
+	return q.convert(q.Querier.GetBundleThatProvides(ctx, group, version, kind))
+}
+
+// convert returns a copy of a bundle converted from the model that matches the bundle read
+// from the database: its apis get their plural names, and the properties that the database
+// doesn't record as such are removed.
+func (q *MemoryQuerier) convert(b *api.Bundle, err error) (*api.Bundle, error) {
+	if err != nil {
+		return nil, err
+	}
+	b = proto.Clone(b).(*api.Bundle)
+	for _, gvks := range [][]*api.GroupVersionKind{b.ProvidedApis, b.RequiredApis} {
+		for _, gvk := range gvks {
+			if gvk.Plural == "" {
+				gvk.Plural = q.plurals[registry.GVKProperty{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind}]
+			}
+		}
+	}
+	var props []*api.Property
+	for _, p := range b.Properties {
+		if _, ok := modelOnlyProperties[p.Type]; !ok {
+			props = append(props, p)
+		}
+	}
+	b.Properties = props
+	return b, nil
+}
+
+// plurals returns the plural names of the apis in the database.
+func (s *SQLQuerier) plurals(ctx context.Context) (map[registry.GVKProperty]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT group_name, version, kind, plural FROM api`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plurals := map[registry.GVKProperty]string{}
+	for rows.Next() {
+		var gvk registry.GVKProperty
+		var plural string
+		if err := rows.Scan(&gvk.Group, &gvk.Version, &gvk.Kind, &plural); err != nil {
+			return nil, err
+		}
+		plurals[gvk] = plural
+	}
+	return plurals, nil
+}
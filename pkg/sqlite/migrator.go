@@ -40,6 +40,31 @@ func NewSQLLiteMigrator(db *sql.DB) (Migrator, error) {
 	}, nil
 }
 
+// NeedsMigration reports whether db is behind the latest migration, without writing to it.
+func NeedsMigration(ctx context.Context, db *sql.DB) (bool, error) {
+	version, err := Version(ctx, db)
+	if err != nil {
+		return false, err
+	}
+	return len(migrations.All().From(version+1)) > 0, nil
+}
+
+// Version returns the id of the latest migration applied to db, or NilVersion if it has not been migrated.
+func Version(ctx context.Context, db *sql.DB) (int, error) {
+	m := &SQLLiteMigrator{
+		db:              db,
+		migrationsTable: DefaultMigrationsTable,
+		migrations:      migrations.All(),
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return NilVersion, err
+	}
+	defer tx.Rollback()
+
+	return m.version(ctx, tx)
+}
+
 // Migrate gets the current version from the database, the latest version from the migrations,
 // and migrates up the the latest
 func (m *SQLLiteMigrator) Migrate(ctx context.Context) error {
@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+// BundleImage is an image referenced by a bundle, either the bundle image itself or one of its related images.
+type BundleImage struct {
+	Bundle string
+	Image  string
+}
+
+// ImagePinner replaces the references to the images of the bundles in a database, such as references by tag with references by digest.
+type ImagePinner struct {
+	db *sql.DB
+}
+
+func NewSQLImagePinner(db *sql.DB) *ImagePinner {
+	return &ImagePinner{db: db}
+}
+
+// Images lists the bundle images and related images referenced by the bundles in the database, ordered by bundle.
+func (p *ImagePinner) Images(ctx context.Context) ([]BundleImage, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT name, bundlepath FROM operatorbundle WHERE bundlepath IS NOT NULL AND bundlepath != ''
+		UNION
+		SELECT operatorbundle_name, image FROM related_image WHERE image IS NOT NULL AND image != ''
+		ORDER BY 1, 2`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var images []BundleImage
+	for rows.Next() {
+		var bundle, image sql.NullString
+		if err := rows.Scan(&bundle, &image); err != nil {
+			return nil, err
+		}
+		images = append(images, BundleImage{Bundle: bundle.String, Image: image.String})
+	}
+	return images, rows.Err()
+}
+
+// Pin replaces every reference to an image in the database with the reference it's pinned to, keyed by the original reference.
+// Each bundle referencing a pinned image gets an olm.pinnedImage property recording the original reference.
+func (p *ImagePinner) Pin(ctx context.Context, pinned map[string]string) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		tx.Rollback()
+	}()
+
+	// The bundle path is part of the key the api, dependency and property tables reference bundles by,
+	// so check their foreign keys once both sides are updated.
+	if _, err := tx.ExecContext(ctx, "PRAGMA defer_foreign_keys = ON"); err != nil {
+		return err
+	}
+
+	froms := make([]string, 0, len(pinned))
+	for from := range pinned {
+		froms = append(froms, from)
+	}
+	sort.Strings(froms)
+
+	for _, from := range froms {
+		to := pinned[from]
+		for _, query := range []string{
+			"UPDATE operatorbundle SET bundlepath = ? WHERE bundlepath = ?",
+			"UPDATE api_provider SET operatorbundle_path = ? WHERE operatorbundle_path = ?",
+			"UPDATE api_requirer SET operatorbundle_path = ? WHERE operatorbundle_path = ?",
+			"UPDATE dependencies SET operatorbundle_path = ? WHERE operatorbundle_path = ?",
+			"UPDATE properties SET operatorbundle_path = ? WHERE operatorbundle_path = ?",
+			"UPDATE related_image SET image = ? WHERE image = ?",
+		} {
+			if _, err := tx.ExecContext(ctx, query, to, from); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, from := range froms {
+		to := pinned[from]
+		bundles, err := bundlesReferencingImage(ctx, tx, to)
+		if err != nil {
+			return err
+		}
+
+		value, err := json.Marshal(registry.PinnedImageProperty{Image: to, Tag: from})
+		if err != nil {
+			return err
+		}
+		for _, b := range bundles {
+			if _, err := tx.ExecContext(ctx,
+				"INSERT INTO properties(type, value, operatorbundle_name, operatorbundle_version, operatorbundle_path) VALUES(?, ?, ?, ?, ?)",
+				registry.PinnedImageType, string(value), b.name, b.version, b.path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+type bundleKey struct {
+	name, version, path sql.NullString
+}
+
+// bundlesReferencingImage returns the bundles with an image as their bundle image or one of their related images.
+func bundlesReferencingImage(ctx context.Context, tx *sql.Tx, image string) ([]bundleKey, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT name, version, bundlepath FROM operatorbundle WHERE bundlepath = ?
+		UNION
+		SELECT operatorbundle.name, operatorbundle.version, operatorbundle.bundlepath FROM operatorbundle
+		INNER JOIN related_image ON related_image.operatorbundle_name = operatorbundle.name
+		WHERE related_image.image = ?`, image, image)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bundles []bundleKey
+	for rows.Next() {
+		var b bundleKey
+		if err := rows.Scan(&b.name, &b.version, &b.path); err != nil {
+			return nil, err
+		}
+		bundles = append(bundles, b)
+	}
+	return bundles, rows.Err()
+}
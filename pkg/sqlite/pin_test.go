@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/registry"
+)
+
+func TestImagePinner(t *testing.T) {
+	ctx := context.TODO()
+	db, cleanup := CreateTestDb(t)
+	defer cleanup()
+	store, err := NewSQLLiteLoader(db)
+	require.NoError(t, err)
+	require.NoError(t, store.Migrate(ctx))
+	query := NewSQLLiteQuerierFromDb(db)
+	graphLoader, err := NewSQLGraphLoaderFromDB(db)
+	require.NoError(t, err)
+
+	const (
+		bundleName   = "prometheusoperator.0.22.2"
+		bundleImage  = "quay.io/test/prometheus.0.22.2"
+		bundlePinned = "quay.io/test/prometheus.0.22.2@sha256:4b5a1b4ac8f50c5fd8c5b4a5d6a3a57cd8e0b8d5b2d9c0e8c4b7a0e1e6b0f6d5"
+		relatedImage = "quay.io/coreos/configmap-reload:v0.0.1"
+		relatedPin   = "quay.io/coreos/configmap-reload@sha256:e2fd60ff0ae4500a75b80ebaa30e0e7deba9ad107833e8ca53f0047c42c5a057"
+		operator     = "quay.io/coreos/prometheus-operator@sha256:3daa69a8c6c2f1d35dcf1fe48a7cd8b230e55f5229a1ded438f687debade5bcf"
+	)
+	require.NoError(t, registry.NewDirectoryPopulator(
+		store,
+		graphLoader,
+		query,
+		map[image.Reference]string{
+			image.SimpleReference(bundleImage): "./testdata/strandedbundles/prometheus.0.22.2",
+		},
+		make(map[string]map[image.Reference]string, 0), false).Populate(registry.ReplacesMode))
+	_, err = db.Exec("INSERT INTO related_image(image, operatorbundle_name) VALUES(?, ?)", relatedImage, bundleName)
+	require.NoError(t, err)
+
+	pinner := NewSQLImagePinner(db)
+	images, err := pinner.Images(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []BundleImage{
+		{Bundle: bundleName, Image: relatedImage},
+		{Bundle: bundleName, Image: operator},
+		{Bundle: bundleName, Image: bundleImage},
+	}, images)
+
+	require.NoError(t, pinner.Pin(ctx, map[string]string{
+		bundleImage:  bundlePinned,
+		relatedImage: relatedPin,
+	}))
+
+	images, err = pinner.Images(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []BundleImage{
+		{Bundle: bundleName, Image: relatedPin},
+		{Bundle: bundleName, Image: operator},
+		{Bundle: bundleName, Image: bundlePinned},
+	}, images)
+
+	// The bundle is still found with its APIs and properties by its new path.
+	bundle, err := query.GetBundleThatProvides(ctx, "monitoring.coreos.com", "v1", "Prometheus")
+	require.NoError(t, err)
+	require.Equal(t, bundlePinned, bundle.BundlePath)
+	var pins []registry.PinnedImageProperty
+	for _, p := range bundle.Properties {
+		if p.Type != registry.PinnedImageType {
+			continue
+		}
+		var pin registry.PinnedImageProperty
+		require.NoError(t, json.Unmarshal([]byte(p.Value), &pin))
+		pins = append(pins, pin)
+	}
+	require.ElementsMatch(t, []registry.PinnedImageProperty{
+		{Image: bundlePinned, Tag: bundleImage},
+		{Image: relatedPin, Tag: relatedImage},
+	}, pins)
+
+	rows, err := db.Query("PRAGMA foreign_key_check")
+	require.NoError(t, err)
+	require.False(t, rows.Next())
+	require.NoError(t, rows.Close())
+}
@@ -38,6 +38,7 @@ type SQLQuerier struct {
 }
 
 var _ registry.Query = &SQLQuerier{}
+var _ registry.ModelQuery = &SQLQuerier{}
 
 func NewSQLLiteQuerier(dbFilename string) (*SQLQuerier, error) {
 	db, err := OpenReadOnly(dbFilename)
@@ -1334,3 +1335,36 @@ func (s *SQLQuerier) listBundleChannels(ctx context.Context, bundleName string)
 
 	return channels, nil
 }
+
+// GetMigrationVersion returns the id of the latest migration applied to the database,
+// or NilVersion if the database has not been migrated.
+func (s *SQLQuerier) GetMigrationVersion(ctx context.Context) (int, error) {
+	a, ok := s.db.(dbQuerierAdapter)
+	if !ok {
+		return NilVersion, fmt.Errorf("querier is not backed by a database")
+	}
+	return Version(ctx, a.db)
+}
+
+// CountContents returns the number of packages, channels and bundles served from the database.
+// Bundles are counted once, regardless of how many channels they belong to.
+func (s *SQLQuerier) CountContents(ctx context.Context) (packages, channels, bundles int, err error) {
+	query := `SELECT
+	  (SELECT COUNT(*) FROM package),
+	  (SELECT COUNT(*) FROM channel),
+	  (SELECT COUNT(DISTINCT operatorbundle.name) FROM operatorbundle
+	    INNER JOIN channel_entry ON channel_entry.operatorbundle_name = operatorbundle.name)`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, 0, 0, fmt.Errorf("no rows returned when counting database contents")
+	}
+	if err := rows.Scan(&packages, &channels, &bundles); err != nil {
+		return 0, 0, 0, err
+	}
+	return packages, channels, bundles, nil
+}
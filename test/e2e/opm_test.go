@@ -137,7 +137,7 @@ func buildFromIndexWith(containerTool string) error {
 // TODO(djzager): make this more complete than what should be a simple no-op
 func pruneIndexWith(containerTool string) error {
 	logger := logrus.WithFields(logrus.Fields{"packages": packageName})
-	indexAdder := indexer.NewIndexPruner(containertools.NewContainerTool(containerTool, containertools.NoneTool), logger)
+	indexAdder := indexer.NewIndexPruner(containertools.NewContainerTool(containerTool, containertools.NoneTool), containertools.NewContainerTool(containerTool, containertools.NoneTool), logger)
 
 	request := indexer.PruneFromIndexRequest{
 		Generate:          false,